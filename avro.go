@@ -0,0 +1,558 @@
+package grizzly
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Avro object container files. Numeric, boolean and decimal fields become
+// float columns, every other field becomes a string column. Logical dates are
+// read as "2006-01-02" and timestamps as RFC 3339 strings, nested values as
+// JSON. Nulls are read as NaN.
+
+var avroMagic = []byte{'O', 'b', 'j', 1}
+
+const avroBlockRows = 4096
+
+type avroSchema struct {
+	kind        string // primitive name, "record", "enum", "array", "map", "fixed" or "union"
+	logicalType string
+	scale       int
+	size        int
+	fields      []avroField
+	symbols     []string
+	items       *avroSchema
+	branches    []*avroSchema
+}
+
+type avroField struct {
+	name     string
+	schema   *avroSchema
+	metadata map[string]string
+}
+
+func init() {
+	formats["avro"] = format{
+		reader: ReaderFunc(func(input io.Reader) (DataFrame, error) { return ReadAvro(input) }),
+		writer: WriterFunc(func(output io.Writer, df *DataFrame) error { return df.WriteAvro(output) }),
+	}
+}
+
+func parseAvroSchema(raw any, named map[string]*avroSchema) (*avroSchema, error) {
+	switch value := raw.(type) {
+	case string:
+		switch value {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+			return &avroSchema{kind: value}, nil
+		}
+		if schema, ok := named[value]; ok {
+			return schema, nil
+		}
+		return nil, fmt.Errorf("unknown avro type %q", value)
+	case []any:
+		schema := &avroSchema{kind: "union"}
+		for _, branch := range value {
+			parsed, err := parseAvroSchema(branch, named)
+			if err != nil {
+				return nil, err
+			}
+			schema.branches = append(schema.branches, parsed)
+		}
+		return schema, nil
+	case map[string]any:
+		kind, _ := value["type"].(string)
+		if kind == "" {
+			// {"type": {...}} wraps another schema
+			return parseAvroSchema(value["type"], named)
+		}
+		schema := &avroSchema{kind: kind}
+		schema.logicalType, _ = value["logicalType"].(string)
+		if scale, ok := value["scale"].(float64); ok {
+			schema.scale = int(scale)
+		}
+		if name, ok := value["name"].(string); ok && (kind == "record" || kind == "enum" || kind == "fixed") {
+			named[name] = schema
+		}
+		switch kind {
+		case "record":
+			fields, _ := value["fields"].([]any)
+			for _, rawField := range fields {
+				field, _ := rawField.(map[string]any)
+				name, _ := field["name"].(string)
+				parsed, err := parseAvroSchema(field["type"], named)
+				if err != nil {
+					return nil, fmt.Errorf("field %q: %w", name, err)
+				}
+				// Series metadata travels as a custom field attribute
+				var metadata map[string]string
+				if rawMetadata, ok := field["metadata"].(map[string]any); ok {
+					metadata = make(map[string]string, len(rawMetadata))
+					for key, value := range rawMetadata {
+						metadata[key] = fmt.Sprintf("%v", value)
+					}
+				}
+				schema.fields = append(schema.fields, avroField{name: name, schema: parsed, metadata: metadata})
+			}
+		case "enum":
+			symbols, _ := value["symbols"].([]any)
+			for _, symbol := range symbols {
+				text, _ := symbol.(string)
+				schema.symbols = append(schema.symbols, text)
+			}
+		case "fixed":
+			size, _ := value["size"].(float64)
+			schema.size = int(size)
+		case "array", "map":
+			key := "items"
+			if kind == "map" {
+				key = "values"
+			}
+			items, err := parseAvroSchema(value[key], named)
+			if err != nil {
+				return nil, err
+			}
+			schema.items = items
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+		default:
+			if named[kind] != nil {
+				return named[kind], nil
+			}
+			return nil, fmt.Errorf("unknown avro type %q", kind)
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("invalid avro schema %v", raw)
+}
+
+// isNumeric tells if the schema, ignoring null branches, maps to a float column
+func (schema *avroSchema) isNumeric() bool {
+	if schema.kind == "union" {
+		numeric := false
+		for _, branch := range schema.branches {
+			if branch.kind == "null" {
+				continue
+			}
+			if !branch.isNumeric() {
+				return false
+			}
+			numeric = true
+		}
+		return numeric
+	}
+	if schema.logicalType == "decimal" {
+		return true
+	}
+	if schema.logicalType != "" && schema.logicalType != "time-millis" && schema.logicalType != "time-micros" {
+		return false
+	}
+	switch schema.kind {
+	case "boolean", "int", "long", "float", "double":
+		return true
+	}
+	return false
+}
+
+type avroDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (decoder *avroDecoder) long() (int64, error) {
+	value, n := binary.Varint(decoder.data[decoder.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid avro integer at byte %d", decoder.pos)
+	}
+	decoder.pos += n
+	return value, nil
+}
+
+func (decoder *avroDecoder) bytes() ([]byte, error) {
+	length, err := decoder.long()
+	if err != nil {
+		return nil, err
+	}
+	return decoder.fixed(int(length))
+}
+
+func (decoder *avroDecoder) fixed(size int) ([]byte, error) {
+	if size < 0 || decoder.pos+size > len(decoder.data) {
+		return nil, fmt.Errorf("unexpected end of avro data")
+	}
+	value := decoder.data[decoder.pos : decoder.pos+size]
+	decoder.pos += size
+	return value, nil
+}
+
+// value decodes one datum. Floats are returned as float64, strings as
+// string and nested values as Go maps and slices.
+func (decoder *avroDecoder) value(schema *avroSchema) (any, error) {
+	switch schema.kind {
+	case "null":
+		return nil, nil
+	case "boolean":
+		raw, err := decoder.fixed(1)
+		if err != nil {
+			return nil, err
+		}
+		if raw[0] != 0 {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	case "int", "long":
+		value, err := decoder.long()
+		if err != nil {
+			return nil, err
+		}
+		switch schema.logicalType {
+		case "date":
+			return time.Unix(value*86400, 0).UTC().Format("2006-01-02"), nil
+		case "timestamp-millis", "local-timestamp-millis":
+			return time.UnixMilli(value).UTC().Format(time.RFC3339Nano), nil
+		case "timestamp-micros", "local-timestamp-micros":
+			return time.UnixMicro(value).UTC().Format(time.RFC3339Nano), nil
+		}
+		return float64(value), nil
+	case "float":
+		raw, err := decoder.fixed(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(raw))), nil
+	case "double":
+		raw, err := decoder.fixed(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(raw)), nil
+	case "bytes", "string", "fixed":
+		var raw []byte
+		var err error
+		if schema.kind == "fixed" {
+			raw, err = decoder.fixed(schema.size)
+		} else {
+			raw, err = decoder.bytes()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if schema.logicalType == "decimal" {
+			unscaled := new(big.Int).SetBytes(raw)
+			if len(raw) > 0 && raw[0]&0x80 != 0 {
+				// Two's complement negative number
+				unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(raw)*8)))
+			}
+			value, _ := new(big.Float).SetInt(unscaled).Float64()
+			return value / math.Pow10(schema.scale), nil
+		}
+		return string(raw), nil
+	case "enum":
+		index, err := decoder.long()
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 || int(index) >= len(schema.symbols) {
+			return nil, fmt.Errorf("enum index %d out of range", index)
+		}
+		return schema.symbols[index], nil
+	case "union":
+		index, err := decoder.long()
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 || int(index) >= len(schema.branches) {
+			return nil, fmt.Errorf("union index %d out of range", index)
+		}
+		return decoder.value(schema.branches[index])
+	case "record":
+		record := make(map[string]any, len(schema.fields))
+		for _, field := range schema.fields {
+			value, err := decoder.value(field.schema)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", field.name, err)
+			}
+			record[field.name] = value
+		}
+		return record, nil
+	case "array", "map":
+		var list []any
+		entries := map[string]any{}
+		for {
+			count, err := decoder.long()
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				break
+			}
+			if count < 0 {
+				count = -count
+				if _, err = decoder.long(); err != nil {
+					return nil, err
+				}
+			}
+			for i := int64(0); i < count; i++ {
+				var key []byte
+				if schema.kind == "map" {
+					if key, err = decoder.bytes(); err != nil {
+						return nil, err
+					}
+				}
+				value, err := decoder.value(schema.items)
+				if err != nil {
+					return nil, err
+				}
+				if schema.kind == "map" {
+					entries[string(key)] = value
+				} else {
+					list = append(list, value)
+				}
+			}
+		}
+		if schema.kind == "map" {
+			return entries, nil
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("unsupported avro type %q", schema.kind)
+}
+
+// ReadAvro parses an Avro object container file with the null or deflate
+// codec from a path or an io.Reader
+func ReadAvro(source any) (DataFrame, error) {
+	input, closeSource, err := openSource(source)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	defer closeSource()
+
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to read avro data: %w", err)
+	}
+	if !bytes.HasPrefix(data, avroMagic) {
+		return DataFrame{}, fmt.Errorf("data is not an avro object container file")
+	}
+	decoder := &avroDecoder{data: data, pos: len(avroMagic)}
+
+	metadata, err := decoder.value(&avroSchema{kind: "map", items: &avroSchema{kind: "bytes"}})
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to read avro header: %w", err)
+	}
+	header := metadata.(map[string]any)
+	codec, _ := header["avro.codec"].(string)
+	if codec == "" {
+		codec = "null"
+	}
+	if codec != "null" && codec != "deflate" {
+		return DataFrame{}, fmt.Errorf("unsupported avro codec %q", codec)
+	}
+	schemaText, _ := header["avro.schema"].(string)
+	var rawSchema any
+	if err = json.Unmarshal([]byte(schemaText), &rawSchema); err != nil {
+		return DataFrame{}, fmt.Errorf("failed to parse avro schema: %w", err)
+	}
+	schema, err := parseAvroSchema(rawSchema, map[string]*avroSchema{})
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to parse avro schema: %w", err)
+	}
+	if schema.kind != "record" {
+		return DataFrame{}, fmt.Errorf("avro schema must be a record, got %q", schema.kind)
+	}
+	sync, err := decoder.fixed(16)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to read avro header: %w", err)
+	}
+
+	columns := make([]Series, len(schema.fields))
+	for i, field := range schema.fields {
+		columns[i] = Series{Name: field.name, DataType: "string", Float: []float64{}, String: []string{}, Metadata: field.metadata}
+		if field.schema.isNumeric() {
+			columns[i].DataType = "float"
+		}
+	}
+
+	for decoder.pos < len(data) {
+		count, err := decoder.long()
+		if err != nil {
+			return DataFrame{}, err
+		}
+		block, err := decoder.bytes()
+		if err != nil {
+			return DataFrame{}, err
+		}
+		if codec == "deflate" {
+			block, err = io.ReadAll(flate.NewReader(bytes.NewReader(block)))
+			if err != nil {
+				return DataFrame{}, fmt.Errorf("failed to inflate avro block: %w", err)
+			}
+		}
+		marker, err := decoder.fixed(16)
+		if err != nil {
+			return DataFrame{}, err
+		}
+		if !bytes.Equal(marker, sync) {
+			return DataFrame{}, fmt.Errorf("invalid avro sync marker")
+		}
+
+		rows := &avroDecoder{data: block}
+		for row := int64(0); row < count; row++ {
+			for i, field := range schema.fields {
+				value, err := rows.value(field.schema)
+				if err != nil {
+					return DataFrame{}, fmt.Errorf("failed to decode field %q: %w", field.name, err)
+				}
+				appendAvroValue(&columns[i], value)
+			}
+		}
+	}
+	result := DataFrame{Columns: columns}
+	result.internOnRead()
+	return result, nil
+}
+
+func appendAvroValue(series *Series, value any) {
+	if series.DataType == "float" {
+		number, ok := value.(float64)
+		if !ok {
+			number = math.NaN()
+		}
+		series.Float = append(series.Float, number)
+		return
+	}
+	switch typed := value.(type) {
+	case nil:
+		series.String = append(series.String, "NaN")
+	case string:
+		series.String = append(series.String, typed)
+	case float64:
+		series.String = append(series.String, strconv.FormatFloat(typed, 'f', -1, 64))
+	default:
+		encoded, _ := json.Marshal(typed)
+		series.String = append(series.String, string(encoded))
+	}
+}
+
+var avroInvalidName = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// avroName replaces the characters Avro does not allow in field names
+func avroName(name string) string {
+	name = avroInvalidName.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+type avroEncoder struct {
+	buffer  bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (encoder *avroEncoder) long(value int64) {
+	n := binary.PutVarint(encoder.scratch[:], value)
+	encoder.buffer.Write(encoder.scratch[:n])
+}
+
+func (encoder *avroEncoder) bytes(value []byte) {
+	encoder.long(int64(len(value)))
+	encoder.buffer.Write(value)
+}
+
+// WriteAvro writes df as an Avro object container file to a path or an
+// io.Writer. Float columns are nullable doubles and string columns nullable
+// strings, NaN is written as null. Column names are adapted to the Avro naming
+// rules.
+func (df *DataFrame) WriteAvro(destination any) error {
+	output, closeDestination, err := openDestination(destination)
+	if err != nil {
+		return err
+	}
+	if err = df.writeAvro(output); err != nil {
+		closeDestination()
+		return err
+	}
+	return closeDestination()
+}
+
+func (df *DataFrame) writeAvro(output io.Writer) error {
+	fields := make([]map[string]any, len(df.Columns))
+	for i, series := range df.Columns {
+		kind := "string"
+		if series.DataType == "float" {
+			kind = "double"
+		}
+		fields[i] = map[string]any{"name": avroName(series.Name), "type": []string{"null", kind}, "default": nil}
+		if len(series.Metadata) > 0 {
+			fields[i]["metadata"] = series.Metadata
+			if description, ok := series.Metadata["description"]; ok {
+				fields[i]["doc"] = description
+			}
+		}
+	}
+	schema, err := json.Marshal(map[string]any{"type": "record", "name": "grizzly", "fields": fields})
+	if err != nil {
+		return fmt.Errorf("failed to build avro schema: %w", err)
+	}
+	sync := make([]byte, 16)
+	if _, err = rand.Read(sync); err != nil {
+		return fmt.Errorf("failed to create avro sync marker: %w", err)
+	}
+
+	header := &avroEncoder{}
+	header.buffer.Write(avroMagic)
+	header.long(2)
+	header.bytes([]byte("avro.schema"))
+	header.bytes(schema)
+	header.bytes([]byte("avro.codec"))
+	header.bytes([]byte("null"))
+	header.long(0)
+	header.buffer.Write(sync)
+	if _, err = output.Write(header.buffer.Bytes()); err != nil {
+		return fmt.Errorf("failed to write avro header: %w", err)
+	}
+
+	length := df.GetLength()
+	var scratch [8]byte
+	for start := 0; start < length; start += avroBlockRows {
+		end := minInt(start+avroBlockRows, length)
+		rows := &avroEncoder{}
+		for row := start; row < end; row++ {
+			for _, series := range df.Columns {
+				if series.DataType == "float" {
+					if row >= len(series.Float) || math.IsNaN(series.Float[row]) {
+						rows.long(0)
+						continue
+					}
+					rows.long(1)
+					binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(series.Float[row]))
+					rows.buffer.Write(scratch[:])
+				} else {
+					if row >= series.GetLength() || series.GetValueString(row) == "NaN" {
+						rows.long(0)
+						continue
+					}
+					rows.long(1)
+					rows.bytes([]byte(series.GetValueString(row)))
+				}
+			}
+		}
+		block := &avroEncoder{}
+		block.long(int64(end - start))
+		block.bytes(rows.buffer.Bytes())
+		block.buffer.Write(sync)
+		if _, err = output.Write(block.buffer.Bytes()); err != nil {
+			return fmt.Errorf("failed to write avro block: %w", err)
+		}
+	}
+	return nil
+}
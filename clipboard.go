@@ -0,0 +1,71 @@
+package grizzly
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// clipboardCommands returns the candidate programs to paste from and copy to
+// the system clipboard, the first one found in PATH is used
+func clipboardCommands(paste bool) [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		if paste {
+			return [][]string{{"pbpaste"}}
+		}
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		if paste {
+			return [][]string{{"powershell", "-NoProfile", "-Command", "Get-Clipboard -Raw"}}
+		}
+		return [][]string{{"clip"}}
+	default:
+		if paste {
+			return [][]string{{"wl-paste", "--no-newline"}, {"xclip", "-selection", "clipboard", "-o"}, {"xsel", "--clipboard", "--output"}}
+		}
+		return [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	}
+}
+
+func clipboardCommand(paste bool) (*exec.Cmd, error) {
+	for _, command := range clipboardCommands(paste) {
+		if _, err := exec.LookPath(command[0]); err == nil {
+			return exec.Command(command[0], command[1:]...), nil
+		}
+	}
+	return nil, fmt.Errorf("no clipboard program found for %s", runtime.GOOS)
+}
+
+// ReadClipboard parses tab separated text from the system clipboard, like
+// cells copied from a spreadsheet
+func ReadClipboard() (DataFrame, error) {
+	command, err := clipboardCommand(true)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	content, err := command.Output()
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to read clipboard: %w", err)
+	}
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	return ReadDelimited(bytes.NewReader(content), '\t')
+}
+
+// WriteClipboard copies df to the system clipboard as tab separated text
+func (df *DataFrame) WriteClipboard() error {
+	command, err := clipboardCommand(false)
+	if err != nil {
+		return err
+	}
+	var content bytes.Buffer
+	if err = df.WriteDelimited(&content, '\t'); err != nil {
+		return err
+	}
+	command.Stdin = &content
+	if err = command.Run(); err != nil {
+		return fmt.Errorf("failed to write clipboard: %w", err)
+	}
+	return nil
+}
@@ -2,7 +2,6 @@ package grizzly
 
 import (
 	"fmt"
-	"runtime"
 	"sync"
 )
 
@@ -18,7 +17,7 @@ func GrizzlyToMatrix(df DataFrame) ([][]float64, error) {
 	}
 
 	// Determine the number of CPU cores for concurrency
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 
 	// Error channel to capture the first error
 	errChan := make(chan error, 1) // Buffered channel to avoid blocking
@@ -1,6 +1,7 @@
 package grizzly
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -10,6 +11,11 @@ import (
 
 type DataFrame struct {
 	Columns []Series
+
+	key      string
+	history  *operationHistory
+	config   *Config
+	versions *versionStore
 }
 
 func CreateDataFrame(series ...Series) DataFrame {
@@ -41,17 +47,29 @@ func (df *DataFrame) CreateStringColumn(name string, words []string) error {
 }
 
 func (df *DataFrame) AddSeries(series Series) error {
+	if err := series.Validate(); err != nil {
+		return fmt.Errorf("cannot add an invalid series: %w", err)
+	}
 	if len(df.Columns) == 0 {
 		df.Columns = append(df.Columns, series)
 		return nil
 	}
 	if series.GetLength() != df.GetLength() {
-		return fmt.Errorf("cannot add a series with different length: series length %d, dataframe length %d",
-			series.GetLength(), df.GetLength())
+		return fmt.Errorf("cannot add series %q of length %d to a dataframe of length %d (column %q)",
+			series.Name, series.GetLength(), df.GetLength(), df.Columns[0].Name)
 
 	} else if isNameRepeated(df.Columns, series.Name) {
 		return fmt.Errorf("cannot add a series with repeated name: %s", series.Name)
 	}
+	// A copy of a column holds the same values, both become views so the
+	// first change to one of them copies its values and drops its statistics
+	for i := range df.Columns {
+		if series.sharesValues(&df.Columns[i]) {
+			df.Columns[i].shared = true
+			series.shared = true
+			series.stats = &statsCache{}
+		}
+	}
 	df.Columns = append(df.Columns, series)
 	return nil
 }
@@ -71,6 +89,34 @@ func (df *DataFrame) AddSeriesForced(series Series) {
 	df.FixShape()
 }
 
+// CheckIntegrity diagnoses a DataFrame whose Columns were changed directly:
+// invalid series, columns with another length than the first one, repeated
+// names and a key column that is missing. Every problem found is joined in
+// the error, nil means the DataFrame is consistent.
+func (df *DataFrame) CheckIntegrity() error {
+	var problems []error
+	names := make(map[string]int, len(df.Columns))
+	for i := range df.Columns {
+		series := &df.Columns[i]
+		if err := series.Validate(); err != nil {
+			problems = append(problems, fmt.Errorf("column %d: %w", i, err))
+		}
+		if i > 0 && series.GetLength() != df.Columns[0].GetLength() {
+			problems = append(problems, fmt.Errorf("column %d %q has length %d, column 0 %q has length %d",
+				i, series.Name, series.GetLength(), df.Columns[0].Name, df.Columns[0].GetLength()))
+		}
+		if previous, repeated := names[series.Name]; repeated {
+			problems = append(problems, fmt.Errorf("column %d has the name %q of column %d", i, series.Name, previous))
+		} else {
+			names[series.Name] = i
+		}
+	}
+	if _, found := names[df.key]; df.key != "" && !found {
+		problems = append(problems, fmt.Errorf("key column %q does not exist", df.key))
+	}
+	return errors.Join(problems...)
+}
+
 func (df *DataFrame) Print(min int, max int) error {
 	var err error
 	// Ensure max does not exceed the length of the DataFrame
@@ -88,13 +134,14 @@ func (df *DataFrame) Print(min int, max int) error {
 	}
 
 	// Print rows of data
+	config := df.getConfig()
 	for i := min; i < max; i++ {
 		var output []string
 		// Add the row index as the first element
 		output = append(output, strconv.Itoa(i))
 		// Add column values for this row
 		for _, series := range df.Columns {
-			output = append(output, series.GetValueAsString(i))
+			output = append(output, formatValue(&series, i, config))
 		}
 		// Join and print the row with the tab writer
 		_, err = fmt.Fprintln(writer, strings.Join(output, "\t"))
@@ -134,3 +181,12 @@ func (df *DataFrame) PrintTail(min int) error {
 	}
 	return nil
 }
+
+// Copy returns a DataFrame with its own copy of every column
+func (df *DataFrame) Copy() DataFrame {
+	result := DataFrame{Columns: make([]Series, len(df.Columns)), key: df.key, history: df.history.copy(), config: df.config, versions: df.versions.copy()}
+	for i, series := range df.Columns {
+		result.Columns[i] = series.Copy()
+	}
+	return result
+}
@@ -2,6 +2,8 @@ package grizzly
 
 import (
 	"fmt"
+	"math"
+	"sync"
 )
 
 func (df *DataFrame) GenericCalculation(operation func(series Series) (float64, error)) (DataFrame, error) {
@@ -33,7 +35,7 @@ func (df *DataFrame) GenericCalculation(operation func(series Series) (float64,
 		}
 		result = append(result, newSeries)
 	}
-	return DataFrame{result}, nil
+	return DataFrame{Columns: result}, nil
 }
 
 func (df *DataFrame) GetMax() (DataFrame, error) {
@@ -91,7 +93,7 @@ func (df *DataFrame) CountWord(word string) (DataFrame, error) {
 				count = 0
 			}
 		} else {
-			count = arrayStringCountWord(series.String, word)
+			count = arrayStringCountWord(series.strings(), word)
 		}
 		err = result.CreateFloatColumn(series.Name, []float64{count})
 		if err != nil {
@@ -102,6 +104,23 @@ func (df *DataFrame) CountWord(word string) (DataFrame, error) {
 	return result, err
 }
 
+// CountMatches returns a DataFrame with the total occurrences of pattern in
+// every string column, see Series.CountMatches
+func (df *DataFrame) CountMatches(pattern string, options MatchOptions) (DataFrame, error) {
+	var result DataFrame
+	for _, series := range df.Columns {
+		if series.DataType != "string" {
+			continue
+		}
+		total, _, err := series.CountMatches(pattern, options)
+		if err != nil {
+			return DataFrame{}, err
+		}
+		result.Columns = append(result.Columns, NewFloatSeries(series.Name, []float64{total}))
+	}
+	return result, nil
+}
+
 func (df *DataFrame) GetNonFloatValues() DataFrame {
 	var result []Series
 	var tempSeries Series
@@ -114,7 +133,7 @@ func (df *DataFrame) GetNonFloatValues() DataFrame {
 			result = append(result, tempSeries)
 		}
 	}
-	resultDataframe = DataFrame{result}
+	resultDataframe = DataFrame{Columns: result}
 	resultDataframe.FixShape()
 	return resultDataframe
 }
@@ -126,7 +145,7 @@ func (df *DataFrame) GetUniqueValues() DataFrame {
 	var tempFloat []float64
 	for _, column := range df.Columns {
 		if column.DataType == "string" {
-			tempString = arrayUniqueValuesString(column.String)
+			tempString = arrayUniqueValuesString(column.strings())
 			temp = NewStringSeries(column.Name, tempString)
 			result.Columns = append(result.Columns, temp)
 			tempString = nil
@@ -152,9 +171,232 @@ func (df *DataFrame) CountNaNValues() DataFrame {
 			series[i].Float = []float64{count}
 
 		} else {
-			count = arrayStringCountWord(column.String, "NaN")
+			count = arrayStringCountWord(column.strings(), "NaN")
 			series[i].Float = []float64{count}
 		}
 	}
-	return DataFrame{series}
+	return DataFrame{Columns: series}
+}
+
+// selectFloatColumns resolves the identifiers to float columns, every float
+// column is used when none is given
+func (df *DataFrame) selectFloatColumns(identifiers ...any) ([][]float64, error) {
+	var columns [][]float64
+	if len(identifiers) == 0 {
+		_, columns = df.floatColumns()
+		return columns, nil
+	}
+	for _, identifier := range identifiers {
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return nil, err
+		}
+		if series.DataType != "float" {
+			return nil, fmt.Errorf("column %v is not of type float; actual type is %q", identifier, series.DataType)
+		}
+		columns = append(columns, series.Float)
+	}
+	return columns, nil
+}
+
+// RowApply calls operation with the values of every row across the selected
+// float columns and returns the results as a new series
+func (df *DataFrame) RowApply(name string, operation func(values []float64) float64, identifiers ...any) (Series, error) {
+	columns, err := df.selectFloatColumns(identifiers...)
+	if err != nil {
+		return Series{}, fmt.Errorf("failed to apply row operation: %w", err)
+	}
+	if len(columns) == 0 {
+		return Series{}, fmt.Errorf("row operations require at least one float column")
+	}
+	length := df.GetLength()
+	result := make([]float64, length)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			row := make([]float64, len(columns))
+			for i := start; i < end; i++ {
+				for j, column := range columns {
+					row[j] = column[i]
+				}
+				result[i] = operation(row)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return NewFloatSeries(name, result), nil
+}
+
+// RowSum ignores NaN values, a row with only NaN values sums 0
+func (df *DataFrame) RowSum(name string, identifiers ...any) (Series, error) {
+	return df.RowApply(name, func(values []float64) float64 {
+		var sum float64
+		for _, value := range values {
+			if !math.IsNaN(value) {
+				sum += value
+			}
+		}
+		return sum
+	}, identifiers...)
+}
+
+// RowMean ignores NaN values, a row with only NaN values is NaN
+func (df *DataFrame) RowMean(name string, identifiers ...any) (Series, error) {
+	return df.RowApply(name, func(values []float64) float64 {
+		var sum float64
+		var count int
+		for _, value := range values {
+			if !math.IsNaN(value) {
+				sum += value
+				count++
+			}
+		}
+		if count == 0 {
+			return math.NaN()
+		}
+		return sum / float64(count)
+	}, identifiers...)
+}
+
+func (df *DataFrame) RowMin(name string, identifiers ...any) (Series, error) {
+	return df.RowApply(name, func(values []float64) float64 {
+		result := math.NaN()
+		for _, value := range values {
+			if !math.IsNaN(value) && (math.IsNaN(result) || value < result) {
+				result = value
+			}
+		}
+		return result
+	}, identifiers...)
+}
+
+func (df *DataFrame) RowMax(name string, identifiers ...any) (Series, error) {
+	return df.RowApply(name, func(values []float64) float64 {
+		result := math.NaN()
+		for _, value := range values {
+			if !math.IsNaN(value) && (math.IsNaN(result) || value > result) {
+				result = value
+			}
+		}
+		return result
+	}, identifiers...)
+}
+
+// AnyTrue returns 1 for the rows where any of the selected float columns is
+// true (not 0 nor NaN) and 0 otherwise
+func (df *DataFrame) AnyTrue(name string, identifiers ...any) (Series, error) {
+	return df.RowApply(name, func(values []float64) float64 {
+		for _, value := range values {
+			if value != 0 && !math.IsNaN(value) {
+				return 1
+			}
+		}
+		return 0
+	}, identifiers...)
+}
+
+// AllTrue returns 1 for the rows where all the selected float columns are
+// true (not 0 nor NaN) and 0 otherwise
+func (df *DataFrame) AllTrue(name string, identifiers ...any) (Series, error) {
+	return df.RowApply(name, func(values []float64) float64 {
+		for _, value := range values {
+			if value == 0 || math.IsNaN(value) {
+				return 0
+			}
+		}
+		return 1
+	}, identifiers...)
+}
+
+// Coalesce returns the first non-null value of each row, NaN for float
+// series and "NaN" or empty for string series count as null
+func Coalesce(name string, columns ...Series) (Series, error) {
+	if len(columns) == 0 {
+		return Series{}, fmt.Errorf("coalesce requires at least one series")
+	}
+	dataType := columns[0].DataType
+	length := columns[0].GetLength()
+	for _, column := range columns {
+		if column.DataType != dataType {
+			return Series{}, fmt.Errorf("coalesce requires series of the same type, %q is %q", column.Name, column.DataType)
+		}
+		if column.GetLength() != length {
+			return Series{}, fmt.Errorf("coalesce requires series of the same length, %q has %d rows", column.Name, column.GetLength())
+		}
+	}
+
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	if dataType == "float" {
+		result := make([]float64, length)
+		for g := 0; g < numGoroutines; g++ {
+			start := g * chunkSize
+			end := start + chunkSize
+			if start >= length {
+				break
+			}
+			if end > length {
+				end = length
+			}
+			wg.Add(1)
+			go func(start, end int) {
+				defer wg.Done()
+				for i := start; i < end; i++ {
+					result[i] = math.NaN()
+					for _, column := range columns {
+						if !math.IsNaN(column.Float[i]) {
+							result[i] = column.Float[i]
+							break
+						}
+					}
+				}
+			}(start, end)
+		}
+		wg.Wait()
+		return NewFloatSeries(name, result), nil
+	}
+
+	texts := make([][]string, len(columns))
+	for i := range columns {
+		texts[i] = columns[i].strings()
+	}
+	result := make([]string, length)
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				result[i] = "NaN"
+				for _, values := range texts {
+					if values[i] != "NaN" && values[i] != "" {
+						result[i] = values[i]
+						break
+					}
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return NewStringSeries(name, result), nil
 }
@@ -36,6 +36,9 @@ func (df *DataFrame) ContainsColumn(name string) bool {
 	return arrayContainsString(names, name)
 }
 
+// GetColumnByName returns the column with the given name. Compressed
+// columns stay compressed, read their values with Strings or
+// GetValueString.
 func (df *DataFrame) GetColumnByName(name string) (*Series, error) {
 	for i, series := range df.Columns {
 		if series.Name == name {
@@ -0,0 +1,204 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// KMeansOptions configures KMeans. A zero value uses 300 iterations, a
+// tolerance of 1e-4 and a random seed.
+type KMeansOptions struct {
+	MaxIterations int
+	Tolerance     float64
+	RandomState   int
+}
+
+// KMeans clusters the rows of the float columns of df in k groups. It
+// returns the cluster of each row as a float series ("cluster", NaN for rows
+// with NaN values) and the centroids with one row per cluster.
+func KMeans(df DataFrame, k int, options KMeansOptions) (Series, DataFrame, error) {
+	names, columns := df.floatColumns()
+	if len(columns) == 0 {
+		return Series{}, DataFrame{}, fmt.Errorf("k-means requires at least one float column")
+	}
+	rows := completeRows(columns)
+	if k <= 0 || k > len(rows) {
+		return Series{}, DataFrame{}, fmt.Errorf("k must be between 1 and the number of complete rows (%d)", len(rows))
+	}
+	if options.MaxIterations <= 0 {
+		options.MaxIterations = 300
+	}
+	if options.Tolerance <= 0 {
+		options.Tolerance = 1e-4
+	}
+	var rng *rand.Rand
+	if options.RandomState != 0 {
+		rng = rand.New(rand.NewSource(int64(options.RandomState)))
+	} else {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	dims := len(columns)
+	point := func(row int, buffer []float64) []float64 {
+		for j, column := range columns {
+			buffer[j] = column[row]
+		}
+		return buffer
+	}
+	centroids := kMeansPlusPlus(rows, k, dims, point, rng)
+	assignments := make([]int, len(rows))
+
+	numGoroutines := workerCount()
+	length := len(rows)
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	for iteration := 0; iteration < options.MaxIterations; iteration++ {
+		// Assignment and partial sums run in parallel, each goroutine keeps
+		// its own sums that are merged afterwards
+		sums := make([][][]float64, numGoroutines)
+		counts := make([][]int, numGoroutines)
+		var wg sync.WaitGroup
+		for g := 0; g < numGoroutines; g++ {
+			start := g * chunkSize
+			end := start + chunkSize
+			if start >= length {
+				break
+			}
+			if end > length {
+				end = length
+			}
+			wg.Add(1)
+			go func(start, end, g int) {
+				defer wg.Done()
+				localSums := make([][]float64, k)
+				for c := range localSums {
+					localSums[c] = make([]float64, dims)
+				}
+				localCounts := make([]int, k)
+				buffer := make([]float64, dims)
+				for i := start; i < end; i++ {
+					values := point(rows[i], buffer)
+					best := nearestCentroid(values, centroids)
+					assignments[i] = best
+					localCounts[best]++
+					for j, value := range values {
+						localSums[best][j] += value
+					}
+				}
+				sums[g] = localSums
+				counts[g] = localCounts
+			}(start, end, g)
+		}
+		wg.Wait()
+
+		var shift float64
+		for c := 0; c < k; c++ {
+			total := 0
+			center := make([]float64, dims)
+			for g := range sums {
+				if sums[g] == nil {
+					continue
+				}
+				total += counts[g][c]
+				for j := range center {
+					center[j] += sums[g][c][j]
+				}
+			}
+			if total == 0 {
+				// Keep empty clusters where they are
+				continue
+			}
+			for j := range center {
+				center[j] /= float64(total)
+				diff := center[j] - centroids[c][j]
+				shift += diff * diff
+			}
+			centroids[c] = center
+		}
+		if shift <= options.Tolerance*options.Tolerance {
+			break
+		}
+	}
+
+	labels := make([]float64, df.GetLength())
+	for i := range labels {
+		labels[i] = math.NaN()
+	}
+	for i, row := range rows {
+		labels[row] = float64(assignments[i])
+	}
+
+	var centroidFrame DataFrame
+	clusterIds := make([]float64, k)
+	for c := range clusterIds {
+		clusterIds[c] = float64(c)
+	}
+	centroidFrame.Columns = append(centroidFrame.Columns, NewFloatSeries("cluster", clusterIds))
+	for j, name := range names {
+		values := make([]float64, k)
+		for c := range centroids {
+			values[c] = centroids[c][j]
+		}
+		centroidFrame.Columns = append(centroidFrame.Columns, NewFloatSeries(name, values))
+	}
+	return NewFloatSeries("cluster", labels), centroidFrame, nil
+}
+
+// kMeansPlusPlus picks initial centroids far from each other
+func kMeansPlusPlus(rows []int, k int, dims int, point func(int, []float64) []float64, rng *rand.Rand) [][]float64 {
+	centroids := make([][]float64, 0, k)
+	first := rows[rng.Intn(len(rows))]
+	centroids = append(centroids, point(first, make([]float64, dims)))
+
+	distances := make([]float64, len(rows))
+	buffer := make([]float64, dims)
+	for len(centroids) < k {
+		var total float64
+		for i, row := range rows {
+			values := point(row, buffer)
+			best := math.Inf(1)
+			for _, centroid := range centroids {
+				best = math.Min(best, squaredDistance(values, centroid))
+			}
+			distances[i] = best
+			total += best
+		}
+		chosen := rows[rng.Intn(len(rows))]
+		if total > 0 {
+			target := rng.Float64() * total
+			for i, distance := range distances {
+				target -= distance
+				if target <= 0 {
+					chosen = rows[i]
+					break
+				}
+			}
+		}
+		centroids = append(centroids, point(chosen, make([]float64, dims)))
+	}
+	return centroids
+}
+
+func nearestCentroid(values []float64, centroids [][]float64) int {
+	best := 0
+	bestDistance := math.Inf(1)
+	for c, centroid := range centroids {
+		distance := squaredDistance(values, centroid)
+		if distance < bestDistance {
+			best = c
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+func squaredDistance(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		diff := a[i] - b[i]
+		sum += diff * diff
+	}
+	return sum
+}
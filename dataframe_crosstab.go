@@ -0,0 +1,188 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+)
+
+// CrosstabOptions configures Crosstab. Values and AggFunc ("count", "sum",
+// "mean", "min" or "max") select what is aggregated in every cell, counting
+// rows by default. Margins adds an "All" row and column and Normalize
+// ("all", "index" or "columns") divides the cells by the matching total.
+type CrosstabOptions struct {
+	Values    *Series
+	AggFunc   string
+	Margins   bool
+	Normalize string
+}
+
+func Crosstab(rowSeries Series, colSeries Series, options CrosstabOptions) (DataFrame, error) {
+	length := rowSeries.GetLength()
+	if colSeries.GetLength() != length {
+		return DataFrame{}, fmt.Errorf("crosstab series have different lengths: %d and %d", length, colSeries.GetLength())
+	}
+	aggFunc := options.AggFunc
+	if aggFunc == "" {
+		aggFunc = "count"
+	}
+	if aggFunc != "count" && options.Values == nil {
+		return DataFrame{}, fmt.Errorf("aggregation %q requires a values series", aggFunc)
+	}
+	if options.Values != nil {
+		if options.Values.DataType != "float" && aggFunc != "count" {
+			return DataFrame{}, fmt.Errorf("aggregation %q requires a float values series", aggFunc)
+		}
+		if options.Values.GetLength() != length {
+			return DataFrame{}, fmt.Errorf("values series has length %d, expected %d", options.Values.GetLength(), length)
+		}
+	}
+	aggregate, err := crosstabAggregation(aggFunc)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	if options.Normalize != "" && options.Normalize != "all" && options.Normalize != "index" && options.Normalize != "columns" {
+		return DataFrame{}, fmt.Errorf("unsupported normalization %q", options.Normalize)
+	}
+
+	// Collect the values of every cell
+	cells := make(map[[2]string][]float64)
+	rowValues := make(map[string][]float64)
+	colValues := make(map[string][]float64)
+	var allValues []float64
+	for i := 0; i < length; i++ {
+		rowLabel := rowSeries.GetValueAsString(i)
+		colLabel := colSeries.GetValueAsString(i)
+		value := 1.0
+		if options.Values != nil && options.Values.DataType == "float" {
+			value = options.Values.Float[i]
+		}
+		key := [2]string{rowLabel, colLabel}
+		cells[key] = append(cells[key], value)
+		rowValues[rowLabel] = append(rowValues[rowLabel], value)
+		colValues[colLabel] = append(colValues[colLabel], value)
+		allValues = append(allValues, value)
+	}
+	rowLabels := make([]string, 0, len(rowValues))
+	for label := range rowValues {
+		rowLabels = append(rowLabels, label)
+	}
+	rowLabels = ParallelSortString(rowLabels)
+	colLabels := make([]string, 0, len(colValues))
+	for label := range colValues {
+		colLabels = append(colLabels, label)
+	}
+	colLabels = ParallelSortString(colLabels)
+
+	table := make([][]float64, len(rowLabels))
+	for r, rowLabel := range rowLabels {
+		table[r] = make([]float64, len(colLabels))
+		for c, colLabel := range colLabels {
+			values, ok := cells[[2]string{rowLabel, colLabel}]
+			if !ok {
+				if aggFunc == "count" || aggFunc == "sum" {
+					table[r][c] = 0
+				} else {
+					table[r][c] = math.NaN()
+				}
+				continue
+			}
+			table[r][c] = aggregate(values)
+		}
+	}
+
+	var rowTotals, colTotals []float64
+	var grandTotal float64
+	if options.Margins || options.Normalize != "" {
+		rowTotals = make([]float64, len(rowLabels))
+		for r, label := range rowLabels {
+			rowTotals[r] = aggregate(rowValues[label])
+		}
+		colTotals = make([]float64, len(colLabels))
+		for c, label := range colLabels {
+			colTotals[c] = aggregate(colValues[label])
+		}
+		grandTotal = aggregate(allValues)
+	}
+
+	switch options.Normalize {
+	case "all":
+		for r := range table {
+			for c := range table[r] {
+				table[r][c] /= grandTotal
+			}
+			rowTotals[r] /= grandTotal
+		}
+		for c := range colTotals {
+			colTotals[c] /= grandTotal
+		}
+		grandTotal = 1
+	case "index":
+		for r := range table {
+			for c := range table[r] {
+				table[r][c] /= rowTotals[r]
+			}
+			rowTotals[r] = 1
+		}
+		for c := range colTotals {
+			colTotals[c] /= grandTotal
+		}
+		grandTotal = 1
+	case "columns":
+		for c := range colLabels {
+			for r := range table {
+				table[r][c] /= colTotals[c]
+			}
+			colTotals[c] = 1
+		}
+		for r := range rowTotals {
+			rowTotals[r] /= grandTotal
+		}
+		grandTotal = 1
+	}
+
+	labels := rowLabels
+	if options.Margins {
+		labels = append(append([]string{}, rowLabels...), "All")
+	}
+	name := rowSeries.Name
+	if name == "" {
+		name = "row"
+	}
+	result := DataFrame{Columns: []Series{NewStringSeries(name, labels)}}
+	for c, colLabel := range colLabels {
+		values := make([]float64, 0, len(labels))
+		for r := range rowLabels {
+			values = append(values, table[r][c])
+		}
+		if options.Margins {
+			values = append(values, colTotals[c])
+		}
+		err = result.AddSeries(NewFloatSeries(colLabel, values))
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to build crosstab column %q: %w", colLabel, err)
+		}
+	}
+	if options.Margins {
+		err = result.AddSeries(NewFloatSeries("All", append(append([]float64{}, rowTotals...), grandTotal)))
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to build crosstab margins: %w", err)
+		}
+	}
+	return result, nil
+}
+
+func crosstabAggregation(aggFunc string) (func([]float64) float64, error) {
+	switch aggFunc {
+	case "count":
+		return func(values []float64) float64 { return float64(len(values)) }, nil
+	case "sum":
+		return arraySum, nil
+	case "mean":
+		return arrayMean, nil
+	case "min":
+		return arrayMin, nil
+	case "max":
+		return arrayMax, nil
+	}
+	return nil, fmt.Errorf("unsupported aggregation %q", aggFunc)
+}
@@ -0,0 +1,206 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// CovMatrix returns the sample covariance between every pair of float
+// columns. Rows with NaN in any float column are ignored. The first column
+// holds the names of the columns.
+func (df *DataFrame) CovMatrix() (DataFrame, error) {
+	names, columns := df.floatColumns()
+	if len(columns) == 0 {
+		return DataFrame{}, fmt.Errorf("covariance requires at least one float column")
+	}
+	rows := completeRows(columns)
+	if len(rows) < 2 {
+		return DataFrame{}, fmt.Errorf("covariance requires at least 2 rows without NaN")
+	}
+	covariance := covarianceMatrix(columns, rows)
+
+	result := DataFrame{Columns: []Series{NewStringSeries("column", names)}}
+	for j, name := range names {
+		values := make([]float64, len(names))
+		for i := range names {
+			values[i] = covariance[i][j]
+		}
+		err := result.AddSeries(NewFloatSeries(name, values))
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to build covariance matrix: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// PCAResult holds the principal axes (one column per component, one row per
+// feature), the variance explained by each component and the data projected
+// over the components.
+type PCAResult struct {
+	Components        DataFrame
+	ExplainedVariance DataFrame
+	Scores            DataFrame
+}
+
+// PCA projects the float columns of df over their first nComponents
+// principal components. Rows with NaN get NaN scores.
+func PCA(df DataFrame, nComponents int) (PCAResult, error) {
+	names, columns := df.floatColumns()
+	if nComponents <= 0 || nComponents > len(columns) {
+		return PCAResult{}, fmt.Errorf("nComponents must be between 1 and %d", len(columns))
+	}
+	rows := completeRows(columns)
+	if len(rows) < 2 {
+		return PCAResult{}, fmt.Errorf("PCA requires at least 2 rows without NaN")
+	}
+	covariance := covarianceMatrix(columns, rows)
+	eigenvalues, eigenvectors := matrixSymmetricEigen(covariance)
+
+	var totalVariance float64
+	for _, value := range eigenvalues {
+		totalVariance += value
+	}
+	means := make([]float64, len(columns))
+	for j, column := range columns {
+		for _, row := range rows {
+			means[j] += column[row]
+		}
+		means[j] /= float64(len(rows))
+	}
+
+	componentNames := make([]string, nComponents)
+	for k := range componentNames {
+		componentNames[k] = "PC" + strconv.Itoa(k+1)
+	}
+
+	var result PCAResult
+	result.Components.Columns = []Series{NewStringSeries("feature", names)}
+	for k, name := range componentNames {
+		axis := make([]float64, len(names))
+		for j := range names {
+			axis[j] = eigenvectors[j][k]
+		}
+		result.Components.Columns = append(result.Components.Columns, NewFloatSeries(name, axis))
+	}
+
+	variance := make([]float64, nComponents)
+	ratio := make([]float64, nComponents)
+	for k := range variance {
+		variance[k] = eigenvalues[k]
+		if totalVariance != 0 {
+			ratio[k] = eigenvalues[k] / totalVariance
+		}
+	}
+	result.ExplainedVariance.Columns = []Series{
+		NewStringSeries("component", componentNames),
+		NewFloatSeries("variance", variance),
+		NewFloatSeries("ratio", ratio),
+	}
+
+	length := df.GetLength()
+	scores := make([][]float64, nComponents)
+	for k := range scores {
+		scores[k] = make([]float64, length)
+	}
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				for k := 0; k < nComponents; k++ {
+					var score float64
+					for j, column := range columns {
+						score += (column[i] - means[j]) * eigenvectors[j][k]
+					}
+					scores[k][i] = score
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	for k, name := range componentNames {
+		result.Scores.Columns = append(result.Scores.Columns, NewFloatSeries(name, scores[k]))
+	}
+	return result, nil
+}
+
+func (df *DataFrame) floatColumns() ([]string, [][]float64) {
+	var names []string
+	var columns [][]float64
+	for _, series := range df.Columns {
+		if series.DataType == "float" {
+			names = append(names, series.Name)
+			columns = append(columns, series.Float)
+		}
+	}
+	return names, columns
+}
+
+// completeRows returns the rows without NaN in any of the columns
+func completeRows(columns [][]float64) []int {
+	if len(columns) == 0 {
+		return nil
+	}
+	var rows []int
+	for i := range columns[0] {
+		complete := true
+		for _, column := range columns {
+			if math.IsNaN(column[i]) {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			rows = append(rows, i)
+		}
+	}
+	return rows
+}
+
+// covarianceMatrix computes every pair of columns in its own goroutine
+func covarianceMatrix(columns [][]float64, rows []int) [][]float64 {
+	numCols := len(columns)
+	means := make([]float64, numCols)
+	for j, column := range columns {
+		for _, row := range rows {
+			means[j] += column[row]
+		}
+		means[j] /= float64(len(rows))
+	}
+
+	covariance := make([][]float64, numCols)
+	for i := range covariance {
+		covariance[i] = make([]float64, numCols)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < numCols; i++ {
+		for j := i; j < numCols; j++ {
+			wg.Add(1)
+			go func(i, j int) {
+				defer wg.Done()
+				var sum float64
+				for _, row := range rows {
+					sum += (columns[i][row] - means[i]) * (columns[j][row] - means[j])
+				}
+				value := sum / float64(len(rows)-1)
+				covariance[i][j] = value
+				covariance[j][i] = value
+			}(i, j)
+		}
+	}
+	wg.Wait()
+	return covariance
+}
@@ -0,0 +1,199 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// RowDiff holds the changes between two versions of a DataFrame. Inserted
+// and Updated hold rows of the new version, Deleted rows of the old one.
+type RowDiff struct {
+	Inserted DataFrame
+	Updated  DataFrame
+	Deleted  DataFrame
+}
+
+// diffKeys returns the composite key of every row, failing on repeated keys
+func diffKeys(df *DataFrame, names []string) (map[string]int, []string, error) {
+	columns := make([]*Series, len(names))
+	for i, name := range names {
+		series, err := df.GetColumnByName(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		columns[i] = series
+	}
+	length := df.GetLength()
+	keys := make([]string, length)
+	positions := make(map[string]int, length)
+	parts := make([]string, len(columns))
+	for row := 0; row < length; row++ {
+		for i, series := range columns {
+			parts[i] = series.GetValueAsString(row)
+		}
+		key := strings.Join(parts, "\x00")
+		if _, ok := positions[key]; ok {
+			return nil, nil, fmt.Errorf("key %q is repeated", strings.Join(parts, ", "))
+		}
+		positions[key] = row
+		keys[row] = key
+	}
+	return positions, keys, nil
+}
+
+// DiffRows compares two versions of a DataFrame matching rows by the key
+// columns. Rows whose key is only in newDf are inserted, only in oldDf are
+// deleted, and rows in both with any different value in the columns they
+// share are updated. Keys must be unique in both DataFrames.
+func DiffRows(oldDf, newDf DataFrame, keyIdentifiers ...any) (RowDiff, error) {
+	if len(keyIdentifiers) == 0 {
+		return RowDiff{}, fmt.Errorf("at least one key column is required")
+	}
+	names := make([]string, len(keyIdentifiers))
+	for i, identifier := range keyIdentifiers {
+		series, err := oldDf.GetColumnDynamic(identifier)
+		if err != nil {
+			return RowDiff{}, fmt.Errorf("failed to retrieve key column %v: %w", identifier, err)
+		}
+		names[i] = series.Name
+	}
+	oldPositions, oldKeys, err := diffKeys(&oldDf, names)
+	if err != nil {
+		return RowDiff{}, fmt.Errorf("failed to diff old rows: %w", err)
+	}
+	newPositions, newKeys, err := diffKeys(&newDf, names)
+	if err != nil {
+		return RowDiff{}, fmt.Errorf("failed to diff new rows: %w", err)
+	}
+
+	// Columns present in both versions are compared
+	var shared [][2]*Series
+	for i := range newDf.Columns {
+		if oldSeries, err := oldDf.GetColumnByName(newDf.Columns[i].Name); err == nil {
+			shared = append(shared, [2]*Series{oldSeries, &newDf.Columns[i]})
+		}
+	}
+
+	var inserted, updated, deleted []int
+	for newRow, key := range newKeys {
+		oldRow, ok := oldPositions[key]
+		if !ok {
+			inserted = append(inserted, newRow)
+			continue
+		}
+		for _, pair := range shared {
+			if pair[0].GetValueAsString(oldRow) != pair[1].GetValueAsString(newRow) {
+				updated = append(updated, newRow)
+				break
+			}
+		}
+	}
+	for oldRow, key := range oldKeys {
+		if _, ok := newPositions[key]; !ok {
+			deleted = append(deleted, oldRow)
+		}
+	}
+
+	diff := RowDiff{}
+	if diff.Inserted, err = newDf.SelectRows(inserted); err != nil {
+		return RowDiff{}, err
+	}
+	if diff.Updated, err = newDf.SelectRows(updated); err != nil {
+		return RowDiff{}, err
+	}
+	if diff.Deleted, err = oldDf.SelectRows(deleted); err != nil {
+		return RowDiff{}, err
+	}
+	return diff, nil
+}
+
+// Update patches df with the values of other, like a file of corrections.
+// Rows are matched by the key columns on, which must be unique in other, and
+// every non null value of a column both frames share replaces the value of
+// the matching rows. When overwrite is false only the nulls of df are
+// filled. Rows of other without a match are ignored, numbers written to
+// string columns are formatted and strings written to float columns must be
+// numbers.
+func (df *DataFrame) Update(other DataFrame, on []string, overwrite bool) (err error) {
+	defer df.track("Update", other.GetShape(), on, overwrite)(&err)
+	if len(on) == 0 {
+		return fmt.Errorf("at least one key column is required")
+	}
+	otherPositions, _, err := diffKeys(&other, on)
+	if err != nil {
+		return fmt.Errorf("failed to read keys of the update: %w", err)
+	}
+	keyColumns := make([]*Series, len(on))
+	for i, name := range on {
+		if keyColumns[i], err = df.GetColumnByName(name); err != nil {
+			return fmt.Errorf("failed to retrieve key column %q: %w", name, err)
+		}
+	}
+
+	// Convert the shared columns before changing anything so a failure
+	// leaves df unchanged
+	type patch struct {
+		target *Series
+		floats []float64
+		texts  []string
+	}
+	var patches []patch
+	for i := range df.Columns {
+		target := &df.Columns[i]
+		source, err := other.GetColumnByName(target.Name)
+		if err != nil || arrayContainsString(on, target.Name) {
+			continue
+		}
+		if err = target.Decompress(); err != nil {
+			return err
+		}
+		current := patch{target: target}
+		if target.DataType == "string" {
+			current.texts = make([]string, source.GetLength())
+			for row := range current.texts {
+				current.texts[row] = source.GetValueAsString(row)
+			}
+		} else if source.DataType == "float" {
+			current.floats = source.Float
+		} else {
+			current.floats = make([]float64, source.GetLength())
+			for row, value := range source.strings() {
+				number, ok := tryConvertToFloat(value)
+				if !ok {
+					return fmt.Errorf("cannot write %q into float column %q", value, target.Name)
+				}
+				current.floats[row] = number
+			}
+		}
+		patches = append(patches, current)
+	}
+	for _, current := range patches {
+		current.target.invalidateIndexes()
+	}
+
+	parts := make([]string, len(keyColumns))
+	for row := 0; row < df.GetLength(); row++ {
+		for i, series := range keyColumns {
+			parts[i] = series.GetValueAsString(row)
+		}
+		otherRow, ok := otherPositions[strings.Join(parts, "\x00")]
+		if !ok {
+			continue
+		}
+		for _, current := range patches {
+			if current.floats != nil {
+				value := current.floats[otherRow]
+				if !math.IsNaN(value) && (overwrite || math.IsNaN(current.target.Float[row])) {
+					current.target.Float[row] = value
+				}
+				continue
+			}
+			value := current.texts[otherRow]
+			if value != "NaN" && (overwrite || current.target.String[row] == "NaN") {
+				current.target.String[row] = value
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package grizzly
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+)
+
+// Partition splits df into n DataFrames of consecutive rows, the first
+// length%n get one row more. Every shard can be aggregated on another
+// process with GroupBy.PartialAgg and the results combined with
+// MergePartials.
+func Partition(df DataFrame, n int) ([]DataFrame, error) {
+	length := df.GetLength()
+	if n < 1 {
+		return nil, fmt.Errorf("number of partitions must be at least 1, got %d", n)
+	}
+	if err := df.checkLengths(); err != nil {
+		return nil, err
+	}
+	shards := make([]DataFrame, n)
+	start := 0
+	for i := range shards {
+		size := length / n
+		if i < length%n {
+			size++
+		}
+		rows := make([]int, size)
+		for k := range rows {
+			rows[k] = start + k
+		}
+		shards[i] = DataFrame{Columns: make([]Series, len(df.Columns)), config: df.config}
+		for j, series := range df.Columns {
+			shards[i].Columns[j] = seriesTake(series, rows)
+		}
+		start += size
+	}
+	return shards, nil
+}
+
+// PartialAggregation is the state of GroupBy.Agg over a shard of the rows,
+// before the aggregations are finished. States from different shards with
+// the same keys and aggregations are combined with MergePartials and Result
+// returns the same DataFrame as Agg over every row. It is serialized with
+// MarshalBinary or encoding/gob to send it between processes.
+type PartialAggregation struct {
+	// Keys hold one row per group with the values of the key columns
+	Keys []Series
+	// Values hold the partial states of every aggregated column and function
+	Values []PartialValues
+}
+
+// PartialValues are the states of one aggregation of a column, one per group
+type PartialValues struct {
+	Column   string
+	Function string
+	Metadata map[string]string
+	States   [][]float64
+}
+
+// PartialAgg is Agg without the final step, the registered Partial function
+// of every aggregation is applied to the values of every group
+func (groupBy *GroupBy) PartialAgg(aggregations map[string][]string) (PartialAggregation, error) {
+	for name := range aggregations {
+		if _, err := groupBy.df.GetColumnByName(name); err != nil {
+			return PartialAggregation{}, fmt.Errorf("failed to retrieve column to aggregate %v: %w", name, err)
+		}
+	}
+
+	keys := groupBy.keyFrame()
+	for i := range keys.Columns {
+		if err := keys.Columns[i].Decompress(); err != nil {
+			return PartialAggregation{}, err
+		}
+	}
+	partial := PartialAggregation{Keys: keys.Columns}
+	for _, series := range groupBy.df.Columns {
+		functions, ok := aggregations[series.Name]
+		if !ok {
+			continue
+		}
+		for _, function := range functions {
+			aggregation, err := getAggregation(function)
+			if err != nil {
+				return PartialAggregation{}, err
+			}
+			values := PartialValues{Column: series.Name, Function: function}
+			if series.DataType == "float" {
+				values.States = groupBy.partialGroups(series.Float, aggregation)
+			} else if function == "count" {
+				counts := groupBy.countStrings(series.strings())
+				values.States = make([][]float64, len(counts))
+				for g, count := range counts {
+					values.States[g] = []float64{count}
+				}
+			} else {
+				return PartialAggregation{}, fmt.Errorf("aggregation %q requires column %q to be of type float", function, series.Name)
+			}
+			if function != "count" {
+				// Counts do not share the unit of the column
+				values.Metadata = copyMetadata(series.Metadata)
+			}
+			partial.Values = append(partial.Values, values)
+		}
+	}
+	return partial, nil
+}
+
+// partialGroups computes the partial state of every group in parallel chunks
+func (groupBy *GroupBy) partialGroups(data []float64, aggregation Aggregation) [][]float64 {
+	result := make([][]float64, len(groupBy.groups))
+	parallelChunks(len(groupBy.groups), func(_, start, end int) {
+		for g := start; g < end; g++ {
+			values := make([]float64, 0, len(groupBy.groups[g]))
+			for _, row := range groupBy.groups[g] {
+				if !math.IsNaN(data[row]) {
+					values = append(values, data[row])
+				}
+			}
+			result[g] = aggregation.Partial(values)
+		}
+	})
+	return result
+}
+
+// MergePartials combines the partial aggregations of several shards. Groups
+// found in more than one shard merge their states with the registered Merge
+// functions, groups keep the order in which they first appear.
+func MergePartials(partials ...PartialAggregation) (PartialAggregation, error) {
+	if len(partials) == 0 {
+		return PartialAggregation{}, fmt.Errorf("merge requires at least one partial aggregation")
+	}
+	first := partials[0]
+	aggregations := make([]Aggregation, len(first.Values))
+	for i, values := range first.Values {
+		aggregation, err := getAggregation(values.Function)
+		if err != nil {
+			return PartialAggregation{}, err
+		}
+		aggregations[i] = aggregation
+	}
+	for p, partial := range partials {
+		if err := partial.validate(); err != nil {
+			return PartialAggregation{}, fmt.Errorf("partial aggregation %d: %w", p, err)
+		}
+		if err := first.sameShape(partial); err != nil {
+			return PartialAggregation{}, fmt.Errorf("partial aggregation %d does not match the first one: %w", p, err)
+		}
+	}
+
+	merged := PartialAggregation{Keys: make([]Series, len(first.Keys)), Values: make([]PartialValues, len(first.Values))}
+	for j, key := range first.Keys {
+		merged.Keys[j] = Series{Name: key.Name, DataType: key.DataType, Metadata: copyMetadata(key.Metadata)}
+	}
+	for i, values := range first.Values {
+		merged.Values[i] = PartialValues{Column: values.Column, Function: values.Function, Metadata: copyMetadata(values.Metadata)}
+	}
+	positions := map[string]int{}
+	var buffer []byte
+	for _, partial := range partials {
+		floats := make([][]float64, len(partial.Keys))
+		texts := make([][]string, len(partial.Keys))
+		for j := range partial.Keys {
+			if partial.Keys[j].DataType == "float" {
+				floats[j] = partial.Keys[j].Float
+			} else {
+				texts[j] = partial.Keys[j].strings()
+			}
+		}
+		for g := 0; g < partial.groups(); g++ {
+			buffer = packGroupKey(buffer[:0], floats, texts, g)
+			position, found := positions[string(buffer)]
+			if !found {
+				positions[string(buffer)] = len(positions)
+				for j := range merged.Keys {
+					if floats[j] != nil {
+						merged.Keys[j].Float = append(merged.Keys[j].Float, floats[j][g])
+					} else {
+						merged.Keys[j].String = append(merged.Keys[j].String, texts[j][g])
+					}
+				}
+				for i := range merged.Values {
+					merged.Values[i].States = append(merged.Values[i].States, append([]float64{}, partial.Values[i].States[g]...))
+				}
+				continue
+			}
+			for i := range merged.Values {
+				merged.Values[i].States[position] = aggregations[i].Merge(merged.Values[i].States[position], partial.Values[i].States[g])
+			}
+		}
+	}
+	return merged, nil
+}
+
+// groups returns the number of groups of the partial aggregation
+func (partial *PartialAggregation) groups() int {
+	if len(partial.Keys) == 0 {
+		return 0
+	}
+	return partial.Keys[0].GetLength()
+}
+
+// validate checks that every key and every list of states has one value per
+// group, partial aggregations may come from other processes
+func (partial *PartialAggregation) validate() error {
+	if len(partial.Keys) == 0 {
+		return fmt.Errorf("partial aggregation has no key columns")
+	}
+	keys := DataFrame{Columns: partial.Keys}
+	if err := keys.CheckIntegrity(); err != nil {
+		return fmt.Errorf("invalid keys: %w", err)
+	}
+	for _, values := range partial.Values {
+		if len(values.States) != partial.groups() {
+			return fmt.Errorf("aggregation %q of column %q has %d states for %d groups", values.Function, values.Column, len(values.States), partial.groups())
+		}
+		aggregation, err := getAggregation(values.Function)
+		if err != nil {
+			return err
+		}
+		if aggregation.Valid == nil {
+			continue
+		}
+		for g, state := range values.States {
+			if !aggregation.Valid(state) {
+				return fmt.Errorf("aggregation %q of column %q has an invalid state of %d values for group %d", values.Function, values.Column, len(state), g)
+			}
+		}
+	}
+	return nil
+}
+
+// sameShape checks that other has the same key columns and aggregations
+func (partial *PartialAggregation) sameShape(other PartialAggregation) error {
+	if len(other.Keys) != len(partial.Keys) {
+		return fmt.Errorf("%d key columns, expected %d", len(other.Keys), len(partial.Keys))
+	}
+	for j, key := range other.Keys {
+		if key.Name != partial.Keys[j].Name || key.DataType != partial.Keys[j].DataType {
+			return fmt.Errorf("key column %d is %q of type %q, expected %q of type %q", j, key.Name, key.DataType, partial.Keys[j].Name, partial.Keys[j].DataType)
+		}
+	}
+	if len(other.Values) != len(partial.Values) {
+		return fmt.Errorf("%d aggregations, expected %d", len(other.Values), len(partial.Values))
+	}
+	for i, values := range other.Values {
+		if values.Column != partial.Values[i].Column || values.Function != partial.Values[i].Function {
+			return fmt.Errorf("aggregation %d is %s of %q, expected %s of %q", i, values.Function, values.Column, partial.Values[i].Function, partial.Values[i].Column)
+		}
+	}
+	return nil
+}
+
+// Result finishes the aggregations, the columns are the keys followed by
+// column_function like in Agg
+func (partial PartialAggregation) Result() (DataFrame, error) {
+	if err := partial.validate(); err != nil {
+		return DataFrame{}, err
+	}
+	result := DataFrame{}
+	for _, key := range partial.Keys {
+		result.Columns = append(result.Columns, key.Copy())
+	}
+	for _, values := range partial.Values {
+		aggregation, err := getAggregation(values.Function)
+		if err != nil {
+			return DataFrame{}, err
+		}
+		finished := make([]float64, len(values.States))
+		for g, state := range values.States {
+			finished[g] = aggregation.Final(state)
+		}
+		aggregated := NewFloatSeries(values.Column+"_"+values.Function, finished)
+		aggregated.Metadata = copyMetadata(values.Metadata)
+		if err = result.AddSeries(aggregated); err != nil {
+			return DataFrame{}, fmt.Errorf("failed to create column %q: %w", aggregated.Name, err)
+		}
+	}
+	return result, nil
+}
+
+// MarshalBinary encodes the partial aggregation with encoding/gob, which
+// keeps NaN and infinite states
+func (partial PartialAggregation) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+	// The fields are encoded without the methods to not call MarshalBinary again
+	type fields PartialAggregation
+	if err := gob.NewEncoder(&buffer).Encode(fields(partial)); err != nil {
+		return nil, fmt.Errorf("failed to encode partial aggregation: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a partial aggregation written by MarshalBinary
+func (partial *PartialAggregation) UnmarshalBinary(data []byte) error {
+	type fields PartialAggregation
+	var decoded fields
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return fmt.Errorf("failed to decode partial aggregation: %w", err)
+	}
+	*partial = PartialAggregation(decoded)
+	return nil
+}
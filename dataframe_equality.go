@@ -0,0 +1,102 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+)
+
+// Mismatch is the first difference found by Equals or ApproxEquals. Row is
+// -1 when the difference is not in the values, like a different data type.
+type Mismatch struct {
+	Column string
+	Row    int
+	Reason string
+	Left   string
+	Right  string
+}
+
+func (mismatch *Mismatch) String() string {
+	location := "dataframe"
+	if mismatch.Column != "" {
+		location = fmt.Sprintf("column %q", mismatch.Column)
+	}
+	if mismatch.Row >= 0 {
+		location += fmt.Sprintf(" row %d", mismatch.Row)
+	}
+	return fmt.Sprintf("%s: %s (%s != %s)", location, mismatch.Reason, mismatch.Left, mismatch.Right)
+}
+
+// Equals compares the name, data type and values of both series, NaN is
+// equal to NaN. The first difference is returned when they are not equal.
+func (series *Series) Equals(other Series) (bool, *Mismatch) {
+	return series.compare(&other, 0)
+}
+
+// ApproxEquals is Equals with floats that differ by at most tolerance,
+// absolute or relative to the larger value, considered equal
+func (series *Series) ApproxEquals(other Series, tolerance float64) (bool, *Mismatch) {
+	return series.compare(&other, tolerance)
+}
+
+func (series *Series) compare(other *Series, tolerance float64) (bool, *Mismatch) {
+	mismatch := func(row int, reason, left, right string) (bool, *Mismatch) {
+		return false, &Mismatch{Column: series.Name, Row: row, Reason: reason, Left: left, Right: right}
+	}
+	if series.Name != other.Name {
+		return mismatch(-1, "different names", series.Name, other.Name)
+	}
+	if series.DataType != other.DataType {
+		return mismatch(-1, "different data types", series.DataType, other.DataType)
+	}
+	if series.GetLength() != other.GetLength() {
+		return mismatch(-1, "different lengths", fmt.Sprint(series.GetLength()), fmt.Sprint(other.GetLength()))
+	}
+	if series.DataType == "float" {
+		for i, a := range series.Float {
+			b := other.Float[i]
+			if a == b || (math.IsNaN(a) && math.IsNaN(b)) {
+				continue
+			}
+			difference := math.Abs(a - b)
+			if difference <= tolerance || difference <= tolerance*math.Max(math.Abs(a), math.Abs(b)) {
+				continue
+			}
+			return mismatch(i, "different values", series.GetValueAsString(i), other.GetValueAsString(i))
+		}
+		return true, nil
+	}
+	left, right := series.strings(), other.strings()
+	for i := range left {
+		if left[i] != right[i] {
+			return mismatch(i, "different values", left[i], right[i])
+		}
+	}
+	return true, nil
+}
+
+// Equals compares the columns of both DataFrames in order, see Series.Equals
+func (df *DataFrame) Equals(other DataFrame) (bool, *Mismatch) {
+	return df.compare(&other, 0)
+}
+
+// ApproxEquals compares the columns of both DataFrames in order, see
+// Series.ApproxEquals
+func (df *DataFrame) ApproxEquals(other DataFrame, tolerance float64) (bool, *Mismatch) {
+	return df.compare(&other, tolerance)
+}
+
+func (df *DataFrame) compare(other *DataFrame, tolerance float64) (bool, *Mismatch) {
+	if len(df.Columns) != len(other.Columns) {
+		return false, &Mismatch{Row: -1, Reason: "different number of columns",
+			Left: fmt.Sprint(len(df.Columns)), Right: fmt.Sprint(len(other.Columns))}
+	}
+	for i := range df.Columns {
+		if equal, mismatch := df.Columns[i].compare(&other.Columns[i], tolerance); !equal {
+			if mismatch.Reason == "different names" {
+				mismatch.Reason = fmt.Sprintf("different column order at position %d", i)
+			}
+			return false, mismatch
+		}
+	}
+	return true, nil
+}
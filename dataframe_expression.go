@@ -0,0 +1,405 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Expr is a typed expression over the columns of a DataFrame. Expressions are
+// built with Col and Lit and evaluated by Evaluate, Filter and WithColumn.
+// Conditions evaluate to float columns holding 1 (true) or 0 (false).
+type Expr struct {
+	kind  string // "column", "literal", "unary" or "binary"
+	name  string
+	value any
+	op    string
+	args  []Expr
+}
+
+// Col references a column by name
+func Col(name string) Expr {
+	return Expr{kind: "column", name: name}
+}
+
+// Lit is a constant broadcast to every row, value must be a number or a string
+func Lit(value any) Expr {
+	return Expr{kind: "literal", value: value}
+}
+
+func (expr Expr) binary(op string, other Expr) Expr {
+	return Expr{kind: "binary", op: op, args: []Expr{expr, other}}
+}
+
+func (expr Expr) unary(op string) Expr {
+	return Expr{kind: "unary", op: op, args: []Expr{expr}}
+}
+
+func (expr Expr) Add(other Expr) Expr { return expr.binary("+", other) }
+func (expr Expr) Sub(other Expr) Expr { return expr.binary("-", other) }
+func (expr Expr) Mul(other Expr) Expr { return expr.binary("*", other) }
+func (expr Expr) Div(other Expr) Expr { return expr.binary("/", other) }
+
+func (expr Expr) Gt(other Expr) Expr { return expr.binary(">", other) }
+func (expr Expr) Ge(other Expr) Expr { return expr.binary(">=", other) }
+func (expr Expr) Lt(other Expr) Expr { return expr.binary("<", other) }
+func (expr Expr) Le(other Expr) Expr { return expr.binary("<=", other) }
+func (expr Expr) Eq(other Expr) Expr { return expr.binary("==", other) }
+func (expr Expr) Ne(other Expr) Expr { return expr.binary("!=", other) }
+
+func (expr Expr) And(other Expr) Expr { return expr.binary("and", other) }
+func (expr Expr) Or(other Expr) Expr  { return expr.binary("or", other) }
+func (expr Expr) Not() Expr           { return expr.unary("not") }
+
+// Contains checks if the string value holds the substring of other
+func (expr Expr) Contains(other Expr) Expr { return expr.binary("contains", other) }
+
+// IsNaN is true for NaN floats and "NaN" strings
+func (expr Expr) IsNaN() Expr { return expr.unary("isnan") }
+
+// Abs is the absolute value of a number
+func (expr Expr) Abs() Expr { return expr.unary("abs") }
+
+// Timestamp reads string dates as Unix seconds so they can be compared and
+// subtracted, values that are not dates become NaN
+func (expr Expr) Timestamp() Expr { return expr.unary("timestamp") }
+
+// Near is true when the numbers differ by at most tolerance
+func (expr Expr) Near(other Expr, tolerance float64) Expr {
+	return expr.Sub(other).Abs().Le(Lit(tolerance))
+}
+
+// Alias sets the name of the resulting series
+func (expr Expr) Alias(name string) Expr {
+	return Expr{kind: "unary", op: "alias", name: name, args: []Expr{expr}}
+}
+
+// columns returns the names of the columns referenced by the expression in
+// order of appearance
+func (expr Expr) columns() []string {
+	if expr.kind == "column" {
+		return []string{expr.name}
+	}
+	var names []string
+	for _, arg := range expr.args {
+		for _, name := range arg.columns() {
+			if !arrayContainsString(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// String shows the expression in infix notation
+func (expr Expr) String() string {
+	switch expr.kind {
+	case "column":
+		return "col(" + expr.name + ")"
+	case "literal":
+		return fmt.Sprintf("%v", expr.value)
+	case "unary":
+		if expr.op == "alias" {
+			return expr.args[0].String()
+		}
+		return expr.op + "(" + expr.args[0].String() + ")"
+	default:
+		return "(" + expr.args[0].String() + " " + expr.op + " " + expr.args[1].String() + ")"
+	}
+}
+
+// Evaluate computes the expression over every row of df
+func (df *DataFrame) Evaluate(expr Expr) (Series, error) {
+	result, err := expr.evaluate(df, df.GetLength())
+	if err != nil {
+		return Series{}, fmt.Errorf("failed to evaluate %v: %w", expr, err)
+	}
+	if expr.kind == "column" || expr.op == "alias" {
+		// Plain column references share the slices of df
+		result = result.Copy()
+		result.Name = expr.name
+	} else {
+		result.Name = expr.String()
+	}
+	return result, nil
+}
+
+// WithColumn stores the result of the expression as column name, replacing
+// an existing column with the same name
+func (df *DataFrame) WithColumn(name string, expr Expr) (err error) {
+	defer df.track("WithColumn", name, expr)(&err)
+	result, err := df.Evaluate(expr)
+	if err != nil {
+		return err
+	}
+	result.Name = name
+	for i := range df.Columns {
+		if df.Columns[i].Name == name {
+			df.Columns[i] = result
+			return nil
+		}
+	}
+	df.Columns = append(df.Columns, result)
+	return nil
+}
+
+// Filter keeps the rows where condition is true. condition is an Expr, an
+// expression as text read by ParseExpr, a []bool mask with one value per row
+// or a float Series of 0 and 1.
+func (df *DataFrame) Filter(condition any) (err error) {
+	defer df.track("Filter", condition)(&err)
+	mask, err := df.conditionMask(condition)
+	if err != nil {
+		return err
+	}
+	return df.filterMask(mask)
+}
+
+// filterMask keeps the rows where mask is true in their order. Every column
+// is built before any is replaced, so df is unchanged on error.
+func (df *DataFrame) filterMask(mask []bool) error {
+	if err := df.checkLengths(); err != nil {
+		return err
+	}
+	filtered, err := df.SelectRows(MaskIndexes(mask))
+	if err != nil {
+		return err
+	}
+	df.invalidateIndexes()
+	df.Columns = filtered.Columns
+	return nil
+}
+
+// conditionMask reads an Expr, an expression as text, a []bool or a float
+// Series of 0 and 1 as a mask with one value per row
+func (df *DataFrame) conditionMask(condition any) ([]bool, error) {
+	var mask []bool
+	if text, ok := condition.(string); ok {
+		expr, err := ParseExpr(text)
+		if err != nil {
+			return nil, err
+		}
+		condition = expr
+	}
+	switch value := condition.(type) {
+	case Expr:
+		result, err := df.Evaluate(value)
+		if err != nil {
+			return nil, err
+		}
+		if result.DataType != "float" {
+			return nil, fmt.Errorf("condition %v is not a boolean expression", value)
+		}
+		mask = make([]bool, len(result.Float))
+		for i, v := range result.Float {
+			mask[i] = v != 0 && !math.IsNaN(v)
+		}
+	case []bool:
+		mask = value
+	case Series:
+		var err error
+		if mask, err = value.ToMask(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported condition type %T", condition)
+	}
+	if len(mask) != df.GetLength() {
+		return nil, fmt.Errorf("condition length %d does not match DataFrame length %d", len(mask), df.GetLength())
+	}
+	return mask, nil
+}
+
+func (expr Expr) evaluate(df *DataFrame, length int) (Series, error) {
+	switch expr.kind {
+	case "column":
+		series, err := df.GetColumnByName(expr.name)
+		if err != nil {
+			return Series{}, err
+		}
+		return *series, nil
+	case "literal":
+		switch value := expr.value.(type) {
+		case string:
+			return NewStringSeries("literal", arrayResizeString([]string{}, length, value)), nil
+		default:
+			number, err := interfaceConvertToFloat(value)
+			if err != nil {
+				return Series{}, fmt.Errorf("unsupported literal %v: %w", value, err)
+			}
+			return NewFloatSeries("literal", arrayResizeFloat([]float64{}, length, number)), nil
+		}
+	case "unary":
+		operand, err := expr.args[0].evaluate(df, length)
+		if err != nil {
+			return Series{}, err
+		}
+		return evaluateUnary(expr.op, operand)
+	default:
+		if result, ok := expr.decide(df, length); ok {
+			return result, nil
+		}
+		left, err := expr.args[0].evaluate(df, length)
+		if err != nil {
+			return Series{}, err
+		}
+		right, err := expr.args[1].evaluate(df, length)
+		if err != nil {
+			return Series{}, err
+		}
+		return evaluateBinary(expr.op, left, right)
+	}
+}
+
+// decide answers a comparison between a float column and a number from the
+// cached statistics of the column, without comparing every row, when all the
+// rows give the same result
+func (expr Expr) decide(df *DataFrame, length int) (Series, bool) {
+	column, literal, op := expr.args[0], expr.args[1], expr.op
+	if column.kind == "literal" && literal.kind == "column" {
+		mirrored := map[string]string{">": "<", ">=": "<=", "<": ">", "<=": ">="}
+		column, literal, op = literal, column, mirrored[op]
+	}
+	if column.kind != "column" || literal.kind != "literal" {
+		return Series{}, false
+	}
+	if _, isString := literal.value.(string); isString {
+		return Series{}, false
+	}
+	value, err := interfaceConvertToFloat(literal.value)
+	if err != nil {
+		return Series{}, false
+	}
+	series, err := df.GetColumnByName(column.name)
+	if err != nil {
+		return Series{}, false
+	}
+	result, ok := series.decideComparison(op, value)
+	if !ok {
+		return Series{}, false
+	}
+	constant := 0.0
+	if result {
+		constant = 1
+	}
+	return NewFloatSeries("", arrayResizeFloat(nil, length, constant)), true
+}
+
+func evaluateUnary(op string, operand Series) (Series, error) {
+	switch op {
+	case "alias":
+		return operand, nil
+	case "isnan":
+		result := make([]float64, operand.GetLength())
+		texts := operand.strings()
+		for i := range result {
+			if operand.DataType == "float" && math.IsNaN(operand.Float[i]) ||
+				operand.DataType == "string" && texts[i] == "NaN" {
+				result[i] = 1
+			}
+		}
+		return NewFloatSeries("", result), nil
+	case "abs":
+		if operand.DataType != "float" {
+			return Series{}, fmt.Errorf("operator abs requires a numeric operand")
+		}
+		result := make([]float64, len(operand.Float))
+		for i, value := range operand.Float {
+			result[i] = math.Abs(value)
+		}
+		return NewFloatSeries("", result), nil
+	case "timestamp":
+		if operand.DataType == "float" {
+			return operand, nil
+		}
+		return NewFloatSeries("", seriesTimestamps(&operand)), nil
+	case "not":
+		if operand.DataType != "float" {
+			return Series{}, fmt.Errorf("operator not requires a boolean operand")
+		}
+		result := make([]float64, len(operand.Float))
+		for i, value := range operand.Float {
+			if value == 0 {
+				result[i] = 1
+			}
+		}
+		return NewFloatSeries("", result), nil
+	}
+	return Series{}, fmt.Errorf("unknown operator %q", op)
+}
+
+func evaluateBinary(op string, left, right Series) (Series, error) {
+	if err := sameLength(fmt.Sprintf("operator %q", op), &left, &right); err != nil {
+		return Series{}, err
+	}
+	if left.DataType != right.DataType {
+		return Series{}, fmt.Errorf("operands of %q have different types %q and %q", op, left.DataType, right.DataType)
+	}
+	length := left.GetLength()
+	result := make([]float64, length)
+	boolean := func(condition bool) float64 {
+		if condition {
+			return 1
+		}
+		return 0
+	}
+
+	if left.DataType == "string" {
+		leftValues, rightValues := left.strings(), right.strings()
+		for i := 0; i < length; i++ {
+			a, b := leftValues[i], rightValues[i]
+			switch op {
+			case "==":
+				result[i] = boolean(a == b)
+			case "!=":
+				result[i] = boolean(a != b)
+			case ">":
+				result[i] = boolean(a > b)
+			case ">=":
+				result[i] = boolean(a >= b)
+			case "<":
+				result[i] = boolean(a < b)
+			case "<=":
+				result[i] = boolean(a <= b)
+			case "contains":
+				result[i] = boolean(strings.Contains(a, b))
+			default:
+				return Series{}, fmt.Errorf("operator %q is not supported for strings", op)
+			}
+		}
+		return NewFloatSeries("", result), nil
+	}
+
+	for i := 0; i < length; i++ {
+		a, b := left.Float[i], right.Float[i]
+		switch op {
+		case "+":
+			result[i] = a + b
+		case "-":
+			result[i] = a - b
+		case "*":
+			result[i] = a * b
+		case "/":
+			result[i] = a / b
+		case "==":
+			result[i] = boolean(a == b)
+		case "!=":
+			result[i] = boolean(a != b)
+		case ">":
+			result[i] = boolean(a > b)
+		case ">=":
+			result[i] = boolean(a >= b)
+		case "<":
+			result[i] = boolean(a < b)
+		case "<=":
+			result[i] = boolean(a <= b)
+		case "and":
+			result[i] = boolean(a != 0 && b != 0 && !math.IsNaN(a) && !math.IsNaN(b))
+		case "or":
+			result[i] = boolean(a != 0 && !math.IsNaN(a) || b != 0 && !math.IsNaN(b))
+		default:
+			return Series{}, fmt.Errorf("operator %q is not supported for floats", op)
+		}
+	}
+	return NewFloatSeries("", result), nil
+}
@@ -0,0 +1,106 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+)
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0088
+
+// haversine returns the great circle distance in kilometers between two
+// points given in degrees
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	deltaPhi := (lat2 - lat1) * math.Pi / 180
+	deltaLambda := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(deltaPhi/2)*math.Sin(deltaPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(deltaLambda/2)*math.Sin(deltaLambda/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// coordinateColumns returns the values of a latitude and a longitude column
+func (df *DataFrame) coordinateColumns(latIdentifier, lonIdentifier any) ([]float64, []float64, error) {
+	latitudes, err := df.GetColumnDynamic(latIdentifier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve latitude column %v: %w", latIdentifier, err)
+	}
+	longitudes, err := df.GetColumnDynamic(lonIdentifier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve longitude column %v: %w", lonIdentifier, err)
+	}
+	if latitudes.DataType != "float" || longitudes.DataType != "float" {
+		return nil, nil, fmt.Errorf("coordinate columns %v and %v must be of type float", latIdentifier, lonIdentifier)
+	}
+	return latitudes.Float, longitudes.Float, nil
+}
+
+// coordinateApply calls operation for every row of the coordinate columns
+// in parallel chunks
+func coordinateApply(length int, operation func(row int)) {
+	parallelChunks(length, func(_, start, end int) {
+		for j := start; j < end; j++ {
+			operation(j)
+		}
+	})
+}
+
+// Haversine returns a float series named "distance_km" with the distance in
+// kilometers between two pairs of latitude and longitude columns in degrees
+func (df *DataFrame) Haversine(lat1, lon1, lat2, lon2 any) (Series, error) {
+	latitudes1, longitudes1, err := df.coordinateColumns(lat1, lon1)
+	if err != nil {
+		return Series{}, err
+	}
+	latitudes2, longitudes2, err := df.coordinateColumns(lat2, lon2)
+	if err != nil {
+		return Series{}, err
+	}
+	distances := make([]float64, len(latitudes1))
+	coordinateApply(len(distances), func(row int) {
+		distances[row] = haversine(latitudes1[row], longitudes1[row], latitudes2[row], longitudes2[row])
+	})
+	return NewFloatSeries("distance_km", distances), nil
+}
+
+// FilterWithinRadius keeps the rows whose coordinates are at most km
+// kilometers from the point (lat, lon)
+func (df *DataFrame) FilterWithinRadius(latIdentifier, lonIdentifier any, lat, lon, km float64) (err error) {
+	defer df.track("FilterWithinRadius", latIdentifier, lonIdentifier, lat, lon, km)(&err)
+	latitudes, longitudes, err := df.coordinateColumns(latIdentifier, lonIdentifier)
+	if err != nil {
+		return err
+	}
+	mask := make([]bool, len(latitudes))
+	coordinateApply(len(mask), func(row int) {
+		mask[row] = haversine(latitudes[row], longitudes[row], lat, lon) <= km
+	})
+	return df.Filter(mask)
+}
+
+// FilterBoundingBox keeps the rows whose coordinates are inside the box, a
+// minLon greater than maxLon describes a box crossing the antimeridian
+func (df *DataFrame) FilterBoundingBox(latIdentifier, lonIdentifier any, minLat, minLon, maxLat, maxLon float64) (err error) {
+	defer df.track("FilterBoundingBox", latIdentifier, lonIdentifier, minLat, minLon, maxLat, maxLon)(&err)
+	if minLat > maxLat {
+		return fmt.Errorf("minimum latitude %v is greater than maximum latitude %v", minLat, maxLat)
+	}
+	latitudes, longitudes, err := df.coordinateColumns(latIdentifier, lonIdentifier)
+	if err != nil {
+		return err
+	}
+	mask := make([]bool, len(latitudes))
+	coordinateApply(len(mask), func(row int) {
+		latitude, longitude := latitudes[row], longitudes[row]
+		if latitude < minLat || latitude > maxLat {
+			return
+		}
+		if minLon <= maxLon {
+			mask[row] = longitude >= minLon && longitude <= maxLon
+		} else {
+			mask[row] = longitude >= minLon || longitude <= maxLon
+		}
+	})
+	return df.Filter(mask)
+}
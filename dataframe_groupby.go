@@ -0,0 +1,495 @@
+package grizzly
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// GroupBy holds the rows of every distinct combination of the key columns,
+// groups keep the order in which they first appear
+type GroupBy struct {
+	df     *DataFrame
+	keys   []*Series
+	groups [][]int
+}
+
+// GroupKey is a group key computed from the rows instead of read from a
+// column, it is passed to GroupBy like a column identifier. Create it with
+// KeyFunc, KeyFloatFunc, KeyTruncate or KeyBins.
+type GroupKey struct {
+	name    string
+	compute func(df *DataFrame) (Series, error)
+}
+
+// KeyFunc groups by the string returned by function for every row
+func KeyFunc(name string, function func(df *DataFrame, row int) string) GroupKey {
+	return GroupKey{name: name, compute: func(df *DataFrame) (Series, error) {
+		values := make([]string, df.GetLength())
+		for row := range values {
+			values[row] = function(df, row)
+		}
+		return NewStringSeries(name, values), nil
+	}}
+}
+
+// KeyFloatFunc groups by the number returned by function for every row
+func KeyFloatFunc(name string, function func(df *DataFrame, row int) float64) GroupKey {
+	return GroupKey{name: name, compute: func(df *DataFrame) (Series, error) {
+		values := make([]float64, df.GetLength())
+		for row := range values {
+			values[row] = function(df, row)
+		}
+		return NewFloatSeries(name, values), nil
+	}}
+}
+
+// KeyTruncate groups a date column by "year", "quarter", "month", "week"
+// (starting on Monday), "day", "hour" or "minute". The key is named
+// column_unit and holds the start of the period, values that are not dates
+// are grouped under "NaN".
+func KeyTruncate(identifier any, unit string) GroupKey {
+	return GroupKey{name: fmt.Sprintf("%v_%s", identifier, unit), compute: func(df *DataFrame) (Series, error) {
+		if _, err := truncateDateTime(time.Time{}, unit); err != nil {
+			return Series{}, err
+		}
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return Series{}, fmt.Errorf("failed to retrieve date column %v: %w", identifier, err)
+		}
+		if series.DataType != "string" {
+			return Series{}, fmt.Errorf("date column %v is not of type string", identifier)
+		}
+		values := make([]string, series.GetLength())
+		for row, value := range series.strings() {
+			parsed, ok := parseDateTime(value)
+			if !ok {
+				values[row] = "NaN"
+				continue
+			}
+			values[row], _ = truncateDateTime(parsed, unit)
+		}
+		return NewStringSeries(series.Name+"_"+unit, values), nil
+	}}
+}
+
+func truncateDateTime(value time.Time, unit string) (string, error) {
+	year, month, day := value.Date()
+	switch unit {
+	case "year":
+		return fmt.Sprintf("%04d-01-01", year), nil
+	case "quarter":
+		return fmt.Sprintf("%04d-%02d-01", year, (month-1)/3*3+1), nil
+	case "month":
+		return fmt.Sprintf("%04d-%02d-01", year, month), nil
+	case "week":
+		offset := (int(value.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, time.UTC).Format("2006-01-02"), nil
+	case "day":
+		return value.Format("2006-01-02"), nil
+	case "hour":
+		return value.Truncate(time.Hour).Format("2006-01-02 15:04:05"), nil
+	case "minute":
+		return value.Truncate(time.Minute).Format("2006-01-02 15:04:05"), nil
+	}
+	return "", fmt.Errorf("unsupported truncation unit %q", unit)
+}
+
+// KeyBins groups a float column by the bins between edges. The key is named
+// column_bin and holds the start of the bin, every bin includes its start and
+// the last one also its end. Values outside the edges are grouped under NaN.
+func KeyBins(identifier any, edges []float64) GroupKey {
+	return GroupKey{name: fmt.Sprintf("%v_bin", identifier), compute: func(df *DataFrame) (Series, error) {
+		if len(edges) < 2 {
+			return Series{}, fmt.Errorf("bins need at least two edges")
+		}
+		for i := 1; i < len(edges); i++ {
+			if !(edges[i] > edges[i-1]) {
+				return Series{}, fmt.Errorf("bin edges must be increasing")
+			}
+		}
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return Series{}, fmt.Errorf("failed to retrieve column to bin %v: %w", identifier, err)
+		}
+		if series.DataType != "float" {
+			return Series{}, fmt.Errorf("column to bin %v is not of type float", identifier)
+		}
+		values := make([]float64, series.GetLength())
+		for row, value := range series.Float {
+			if bin := histogramBin(edges, value); bin >= 0 {
+				values[row] = edges[bin]
+			} else {
+				values[row] = math.NaN()
+			}
+		}
+		return NewFloatSeries(series.Name+"_bin", values), nil
+	}}
+}
+
+// KeyRound groups a float column by its values rounded to decimals, like 2
+// for cents, so floating point noise does not split groups. The key is named
+// column_rounded.
+func KeyRound(identifier any, decimals int) GroupKey {
+	return GroupKey{name: fmt.Sprintf("%v_rounded", identifier), compute: func(df *DataFrame) (Series, error) {
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return Series{}, fmt.Errorf("failed to retrieve column to round %v: %w", identifier, err)
+		}
+		if series.DataType != "float" {
+			return Series{}, fmt.Errorf("column to round %v is not of type float", identifier)
+		}
+		scale := math.Pow(10, float64(decimals))
+		values := make([]float64, len(series.Float))
+		for row, value := range series.Float {
+			values[row] = math.Round(value*scale) / scale
+			if values[row] == 0 {
+				// Small negative values round to -0, the key shows 0
+				values[row] = 0
+			}
+		}
+		return NewFloatSeries(series.Name+"_rounded", values), nil
+	}}
+}
+
+// KeyTolerance groups a float column so values closer than epsilon to the
+// next larger value fall in the same group. Groups are chains, so a group
+// can span more than epsilon when its values are evenly spread. The key is
+// named column_group and holds the smallest value of the group.
+func KeyTolerance(identifier any, epsilon float64) GroupKey {
+	return GroupKey{name: fmt.Sprintf("%v_group", identifier), compute: func(df *DataFrame) (Series, error) {
+		if epsilon < 0 {
+			return Series{}, fmt.Errorf("tolerance must not be negative, got %v", epsilon)
+		}
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return Series{}, fmt.Errorf("failed to retrieve column to group %v: %w", identifier, err)
+		}
+		if series.DataType != "float" {
+			return Series{}, fmt.Errorf("column to group %v is not of type float", identifier)
+		}
+		order, err := series.SortIndices(SortOptions{})
+		if err != nil {
+			return Series{}, err
+		}
+		values := make([]float64, len(series.Float))
+		start, previous := math.NaN(), math.NaN()
+		for _, row := range order {
+			value := series.Float[row]
+			if math.IsNaN(value) {
+				values[row] = math.NaN()
+				continue
+			}
+			if math.IsNaN(previous) || value-previous > epsilon {
+				start = value
+			}
+			values[row] = start
+			previous = value
+		}
+		return NewFloatSeries(series.Name+"_group", values), nil
+	}}
+}
+
+// GroupBy groups the rows by the values of the key columns, identifiers are
+// column names, indexes or computed GroupKey values
+func (df *DataFrame) GroupBy(identifiers ...any) (*GroupBy, error) {
+	span := startSpan("groupby")
+	groupBy, err := df.groupBy(identifiers)
+	if err != nil {
+		span.end(err, "rows", df.GetLength())
+		return nil, err
+	}
+	span.end(nil, "rows", df.GetLength(), "groups", groupBy.GetNumberOfGroups())
+	return groupBy, nil
+}
+
+func (df *DataFrame) groupBy(identifiers []any) (*GroupBy, error) {
+	if len(identifiers) == 0 {
+		return nil, fmt.Errorf("group by requires at least one key column")
+	}
+	keyColumns := make([]*Series, len(identifiers))
+	for i, identifier := range identifiers {
+		if key, ok := identifier.(GroupKey); ok {
+			series, err := key.compute(df)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute group key %q: %w", key.name, err)
+			}
+			if series.GetLength() != df.GetLength() {
+				return nil, fmt.Errorf("group key %q has length %d, DataFrame length is %d", key.name, series.GetLength(), df.GetLength())
+			}
+			keyColumns[i] = &series
+			continue
+		}
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve group key %v: %w", identifier, err)
+		}
+		keyColumns[i] = series
+	}
+
+	groups := hashGroups(keyColumns, df.GetLength())
+	return &GroupBy{df: df, keys: keyColumns, groups: groups}, nil
+}
+
+// localGroups are the groups found by one worker in its chunk of rows, in
+// order of first appearance
+type localGroups struct {
+	keys []string
+	rows [][]int
+}
+
+// hashGroups finds the rows of every distinct key in parallel. Every worker
+// groups a chunk of rows in its own hash table keyed by the packed values of
+// the key columns, then the tables are merged in chunk order so groups keep
+// the order in which they first appear and their rows stay sorted.
+func hashGroups(keyColumns []*Series, length int) [][]int {
+	floats := make([][]float64, len(keyColumns))
+	texts := make([][]string, len(keyColumns))
+	for i, series := range keyColumns {
+		if series.DataType == "float" {
+			floats[i] = series.Float
+		} else {
+			texts[i] = series.strings()
+		}
+	}
+
+	progress := startProgress("groupby", length)
+	defer progress.finish()
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	partials := make([]localGroups, numGoroutines)
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := minInt(start+chunkSize, length)
+		if start >= length {
+			break
+		}
+		wg.Add(1)
+		go func(g, start, end int) {
+			defer wg.Done()
+			// Pre-size for many distinct keys without reserving a slot per row
+			positions := make(map[string]int, minInt(end-start, 1<<12))
+			partial := &partials[g]
+			var buffer []byte
+			for row := start; row < end; row++ {
+				if (row-start)%1024 == 1023 {
+					progress.add(1024)
+				}
+				var key string
+				if len(keyColumns) == 1 && texts[0] != nil {
+					key = texts[0][row]
+				} else {
+					buffer = packGroupKey(buffer[:0], floats, texts, row)
+					key = string(buffer)
+				}
+				position, found := positions[key]
+				if !found {
+					position = len(partial.keys)
+					positions[key] = position
+					partial.keys = append(partial.keys, key)
+					partial.rows = append(partial.rows, nil)
+				}
+				partial.rows[position] = append(partial.rows[position], row)
+			}
+			progress.add((end - start) % 1024)
+		}(g, start, end)
+	}
+	wg.Wait()
+
+	if length <= chunkSize {
+		return partials[0].rows
+	}
+	positions := make(map[string]int, len(partials[0].keys))
+	var groups [][]int
+	for _, partial := range partials {
+		for i, key := range partial.keys {
+			position, found := positions[key]
+			if !found {
+				positions[key] = len(groups)
+				groups = append(groups, partial.rows[i])
+				continue
+			}
+			groups[position] = append(groups[position], partial.rows[i]...)
+		}
+	}
+	return groups
+}
+
+// packGroupKey appends the values of the key columns at row to buffer, floats
+// as their 8 bytes with a single NaN and a single zero and strings prefixed
+// by their length so different values never pack the same way
+func packGroupKey(buffer []byte, floats [][]float64, texts [][]string, row int) []byte {
+	for i := range floats {
+		if floats[i] != nil {
+			value := floats[i][row]
+			if value == 0 {
+				// -0 and 0 are the same key, rounding small negative values gives -0
+				value = 0
+			} else if math.IsNaN(value) {
+				value = math.NaN()
+			}
+			buffer = binary.LittleEndian.AppendUint64(buffer, math.Float64bits(value))
+			continue
+		}
+		buffer = binary.AppendUvarint(buffer, uint64(len(texts[i][row])))
+		buffer = append(buffer, texts[i][row]...)
+	}
+	return buffer
+}
+
+func (groupBy *GroupBy) GetNumberOfGroups() int {
+	return len(groupBy.groups)
+}
+
+// GetGroupIndexes returns the row indexes of every group
+func (groupBy *GroupBy) GetGroupIndexes() [][]int {
+	return groupBy.groups
+}
+
+func (groupBy *GroupBy) GetGroup(index int) (DataFrame, error) {
+	if index < 0 || index >= len(groupBy.groups) {
+		return DataFrame{}, fmt.Errorf("group index out of range: %d", index)
+	}
+	return groupBy.df.SelectRows(groupBy.groups[index])
+}
+
+// keyFrame returns one row per group with the values of the key columns
+func (groupBy *GroupBy) keyFrame() DataFrame {
+	firstRows := make([]int, len(groupBy.groups))
+	for i, rows := range groupBy.groups {
+		firstRows[i] = rows[0]
+	}
+	result := DataFrame{}
+	for _, key := range groupBy.keys {
+		result.Columns = append(result.Columns, seriesTake(*key, firstRows))
+	}
+	return result
+}
+
+// Agg reduces every group with the named aggregations, built-in ("sum",
+// "count", "mean", "min", "max", "product", "variance", "std", "median",
+// "approx_median") or
+// registered with RegisterAggregation. aggregations maps a column name to its
+// functions, results are named column_function and follow the column order
+// of the DataFrame. String columns only support "count".
+func (groupBy *GroupBy) Agg(aggregations map[string][]string) (DataFrame, error) {
+	for name := range aggregations {
+		if _, err := groupBy.df.GetColumnByName(name); err != nil {
+			return DataFrame{}, fmt.Errorf("failed to retrieve column to aggregate %v: %w", name, err)
+		}
+	}
+
+	result := groupBy.keyFrame()
+	for _, series := range groupBy.df.Columns {
+		functions, ok := aggregations[series.Name]
+		if !ok {
+			continue
+		}
+		for _, function := range functions {
+			aggregation, err := getAggregation(function)
+			if err != nil {
+				return DataFrame{}, err
+			}
+			var values []float64
+			if series.DataType == "float" {
+				values = groupBy.aggregateGroups(series.Float, aggregation)
+			} else if function == "count" {
+				values = groupBy.countStrings(series.strings())
+			} else {
+				return DataFrame{}, fmt.Errorf("aggregation %q requires column %q to be of type float", function, series.Name)
+			}
+			aggregated := NewFloatSeries(series.Name+"_"+function, values)
+			if function != "count" {
+				// Counts do not share the unit of the column
+				aggregated.Metadata = copyMetadata(series.Metadata)
+			}
+			err = result.AddSeries(aggregated)
+			if err != nil {
+				return DataFrame{}, fmt.Errorf("failed to create column %q: %w", aggregated.Name, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// aggregateGroups reduces the groups in parallel chunks
+func (groupBy *GroupBy) aggregateGroups(data []float64, aggregation Aggregation) []float64 {
+	length := len(groupBy.groups)
+	result := make([]float64, length)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+
+	for i := 0; i < numGoroutines; i++ {
+		start := i * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			var values []float64
+			for g := start; g < end; g++ {
+				values = values[:0]
+				for _, row := range groupBy.groups[g] {
+					values = append(values, data[row])
+				}
+				result[g] = arrayAggregate(aggregation, values)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return result
+}
+
+func (groupBy *GroupBy) countStrings(data []string) []float64 {
+	result := make([]float64, len(groupBy.groups))
+	for g, rows := range groupBy.groups {
+		for _, row := range rows {
+			if data[row] != "NaN" {
+				result[g]++
+			}
+		}
+	}
+	return result
+}
+
+// Describe summarizes every float column with the named aggregations, by
+// default count, mean, std, min, median and max
+func (df *DataFrame) Describe(aggregations ...string) (DataFrame, error) {
+	if len(aggregations) == 0 {
+		aggregations = []string{"count", "mean", "std", "min", "median", "max"}
+	}
+	functions := make([]Aggregation, len(aggregations))
+	for i, name := range aggregations {
+		aggregation, err := getAggregation(name)
+		if err != nil {
+			return DataFrame{}, err
+		}
+		functions[i] = aggregation
+	}
+
+	result := DataFrame{Columns: []Series{NewStringSeries("statistic", append([]string{}, aggregations...))}}
+	for _, series := range df.Columns {
+		if series.DataType != "float" {
+			continue
+		}
+		values := make([]float64, len(functions))
+		for i, aggregation := range functions {
+			values[i] = arrayAggregate(aggregation, series.Float)
+		}
+		err := result.CreateFloatColumn(series.Name, values)
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to create column %q: %w", series.Name, err)
+		}
+	}
+	return result, nil
+}
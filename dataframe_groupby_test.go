@@ -0,0 +1,50 @@
+package grizzly
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func groupByBenchmarkFrame(rows int) DataFrame {
+	random := rand.New(rand.NewSource(1))
+	cities := make([]string, rows)
+	years := make([]float64, rows)
+	prices := make([]float64, rows)
+	for i := 0; i < rows; i++ {
+		cities[i] = "city" + strconv.Itoa(random.Intn(1000))
+		years[i] = float64(2000 + random.Intn(20))
+		prices[i] = random.Float64() * 100
+	}
+	return DataFrame{Columns: []Series{
+		NewStringSeries("City", cities),
+		NewFloatSeries("Year", years),
+		NewFloatSeries("Price", prices),
+	}}
+}
+
+// BenchmarkGroupBy groups one million rows by a composite key with an
+// MaxWorkers from 1 to 16 to show how the per worker hash tables scale, the
+// workers above the CPUs of the machine only add overhead
+func BenchmarkGroupBy(b *testing.B) {
+	df := groupByBenchmarkFrame(1_000_000)
+	previous := GetConfig()
+	defer SetConfig(previous)
+	for _, workers := range []int{1, 2, 4, 8, 16} {
+		config := previous
+		config.MaxWorkers = workers
+		SetConfig(config)
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				groups, err := df.GroupBy("City", "Year")
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := groups.Agg(map[string][]string{"Price": {"mean"}}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
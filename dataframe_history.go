@@ -0,0 +1,159 @@
+package grizzly
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Operation is one transformation recorded in the history of a DataFrame.
+// InputHash identifies the data the operation was applied to.
+type Operation struct {
+	Name       string    `json:"name"`
+	Parameters []string  `json:"parameters"`
+	InputHash  string    `json:"input_hash"`
+	Timestamp  time.Time `json:"timestamp"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type operationHistory struct {
+	enabled    bool
+	depth      int
+	operations []Operation
+}
+
+func (history *operationHistory) copy() *operationHistory {
+	if history == nil {
+		return nil
+	}
+	return &operationHistory{enabled: history.enabled, operations: append([]Operation{}, history.operations...)}
+}
+
+// EnableHistory starts recording the transformations applied to df, every
+// record hashes the whole DataFrame so it has a cost on large data
+func (df *DataFrame) EnableHistory() {
+	if df.history == nil {
+		df.history = &operationHistory{}
+	}
+	df.history.enabled = true
+}
+
+// DisableHistory stops recording, the recorded operations are kept
+func (df *DataFrame) DisableHistory() {
+	if df.history != nil {
+		df.history.enabled = false
+	}
+}
+
+func (df *DataFrame) History() []Operation {
+	if df.history == nil {
+		return nil
+	}
+	return append([]Operation{}, df.history.operations...)
+}
+
+func (df *DataFrame) HistoryJSON() ([]byte, error) {
+	operations := df.History()
+	if operations == nil {
+		operations = []Operation{}
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(operations); err != nil {
+		return nil, fmt.Errorf("failed to serialize history: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// Hash returns a FNV-1a hash of the names, types and values of the columns
+func (df *DataFrame) Hash() string {
+	hasher := fnv.New64a()
+	buffer := make([]byte, 8)
+	for _, series := range df.Columns {
+		hasher.Write([]byte(series.Name))
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(series.DataType))
+		hasher.Write([]byte{0})
+		if series.DataType == "float" {
+			for _, value := range series.Float {
+				bits := math.Float64bits(value)
+				for i := range buffer {
+					buffer[i] = byte(bits >> (8 * i))
+				}
+				hasher.Write(buffer)
+			}
+		} else {
+			for _, value := range series.strings() {
+				hasher.Write([]byte(value))
+				hasher.Write([]byte{0})
+			}
+		}
+	}
+	return strconv.FormatUint(hasher.Sum64(), 16)
+}
+
+// track is deferred at the start of a transformation, the returned function
+// receives the final error and stores the record. Operations called by
+// another recorded operation are not recorded. Errors panic when the Config
+// sets PanicOnError.
+func (df *DataFrame) track(name string, parameters ...any) func(err *error) {
+	if df.history == nil || !df.history.enabled {
+		return df.panicOnError
+	}
+	history := df.history
+	history.depth++
+	if history.depth > 1 {
+		return func(err *error) {
+			history.depth--
+			df.panicOnError(err)
+		}
+	}
+
+	operation := Operation{
+		Name:       name,
+		Parameters: make([]string, len(parameters)),
+		InputHash:  df.Hash(),
+		Timestamp:  time.Now().UTC(),
+	}
+	for i, parameter := range parameters {
+		operation.Parameters[i] = formatParameter(parameter)
+	}
+	return func(err *error) {
+		history.depth--
+		if err != nil && *err != nil {
+			operation.Error = (*err).Error()
+		}
+		history.operations = append(history.operations, operation)
+		df.panicOnError(err)
+	}
+}
+
+// panicOnError panics with the error of a transformation when the Config
+// asks for it
+func (df *DataFrame) panicOnError(err *error) {
+	if err != nil {
+		panicOnError(*err, df.getConfig())
+	}
+}
+
+// formatParameter describes a parameter, functions can not be printed so
+// only their type is kept
+func formatParameter(parameter any) string {
+	if parameter == nil {
+		return "<nil>"
+	}
+	if reflect.TypeOf(parameter).Kind() == reflect.Func {
+		return reflect.TypeOf(parameter).String()
+	}
+	if expr, ok := parameter.(Expr); ok {
+		return expr.String()
+	}
+	return fmt.Sprintf("%v", parameter)
+}
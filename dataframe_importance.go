@@ -0,0 +1,80 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// PermutationImportance measures how much a model relies on every feature.
+// scoreFunc scores the model on the feature columns of df, every column but
+// target, against the target column, higher is better. Every feature is
+// shuffled repeats times while the others are left untouched and the drop
+// from the baseline score is reported. The result has the columns feature,
+// importance (mean drop) and std (standard deviation of the drops), sorted
+// by importance. Shuffles run in parallel, so scoreFunc must be safe for
+// concurrent use and must not modify the DataFrame it gets. A seed of 0 uses
+// the current time.
+func PermutationImportance(df DataFrame, target any, scoreFunc func(features DataFrame, target Series) float64, repeats int, seed int) (DataFrame, error) {
+	if repeats <= 0 {
+		return DataFrame{}, fmt.Errorf("number of repeats must be positive, got %d", repeats)
+	}
+	targetSeries, err := df.GetColumnDynamic(target)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to retrieve target column %v: %w", target, err)
+	}
+	var features DataFrame
+	for _, name := range df.GetColumnNames() {
+		if name == targetSeries.Name {
+			continue
+		}
+		series, err := df.GetColumnByName(name)
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to retrieve feature column %q: %w", name, err)
+		}
+		features.Columns = append(features.Columns, *series)
+	}
+	if len(features.Columns) == 0 {
+		return DataFrame{}, fmt.Errorf("DataFrame has no feature columns besides the target")
+	}
+
+	baseline := scoreFunc(features, *targetSeries)
+	length := df.GetLength()
+	drops := make([]float64, len(features.Columns)*repeats)
+	parallelDraws(len(drops), seed, func(i int, rng *rand.Rand) {
+		feature := i / repeats
+		// Shuffled features share every column but the permuted one
+		shuffled := DataFrame{Columns: append([]Series(nil), features.Columns...)}
+		shuffled.Columns[feature] = seriesTake(features.Columns[feature], rng.Perm(length))
+		drops[i] = baseline - scoreFunc(shuffled, *targetSeries)
+	})
+
+	names := make([]string, len(features.Columns))
+	importances := make([]float64, len(features.Columns))
+	deviations := make([]float64, len(features.Columns))
+	for feature, series := range features.Columns {
+		names[feature] = series.Name
+		values := drops[feature*repeats : (feature+1)*repeats]
+		for _, drop := range values {
+			importances[feature] += drop
+		}
+		importances[feature] /= float64(repeats)
+		for _, drop := range values {
+			deviations[feature] += (drop - importances[feature]) * (drop - importances[feature])
+		}
+		deviations[feature] = math.Sqrt(deviations[feature] / float64(repeats))
+	}
+
+	order := make([]int, len(names))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return importances[order[a]] > importances[order[b]] })
+	result := DataFrame{Columns: []Series{
+		seriesTake(NewStringSeries("feature", names), order),
+		seriesTake(NewFloatSeries("importance", importances), order),
+		seriesTake(NewFloatSeries("std", deviations), order),
+	}}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package grizzly
+
+import (
+	"fmt"
+)
+
+// SetKey marks a column as the key of the DataFrame. A hash index is kept on
+// it and rebuilt on demand after the column is modified.
+func (df *DataFrame) SetKey(identifier any) error {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to set key %v: %w", identifier, err)
+	}
+	series.BuildHashIndex()
+	df.key = series.Name
+	return nil
+}
+
+func (df *DataFrame) GetKey() string {
+	return df.key
+}
+
+func (df *DataFrame) BuildHashIndex(identifier any) error {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to build hash index for column %v: %w", identifier, err)
+	}
+	series.BuildHashIndex()
+	return nil
+}
+
+func (df *DataFrame) BuildSortedIndex(identifier any) error {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to build sorted index for column %v: %w", identifier, err)
+	}
+	series.BuildSortedIndex()
+	return nil
+}
+
+// keySeries returns the key column with its hash index up to date
+func (df *DataFrame) keySeries() (*Series, error) {
+	if df.key == "" {
+		return nil, fmt.Errorf("dataframe has no key, use SetKey first")
+	}
+	series, err := df.GetColumnByName(df.key)
+	if err != nil {
+		return nil, fmt.Errorf("key column is missing: %w", err)
+	}
+	if !series.HasHashIndex() {
+		series.BuildHashIndex()
+	}
+	return series, nil
+}
+
+// Lookup returns the rows whose key is equal to value
+func (df *DataFrame) Lookup(value any) (DataFrame, error) {
+	series, err := df.keySeries()
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to lookup %v: %w", value, err)
+	}
+	return df.lookupSeries(series, value)
+}
+
+// FilterEqual returns the rows of the column equal to value, using the
+// column indexes when they exist
+func (df *DataFrame) FilterEqual(identifier any, value any) (DataFrame, error) {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to filter column %v: %w", identifier, err)
+	}
+	return df.lookupSeries(series, value)
+}
+
+func (df *DataFrame) lookupSeries(series *Series, value any) (DataFrame, error) {
+	var rows []int
+	if series.DataType == "float" {
+		number, err := interfaceConvertToFloat(value)
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to lookup %v in column %q: %w", value, series.Name, err)
+		}
+		rows = series.lookupFloat(number)
+	} else {
+		word, err := interfaceConvertToString(value)
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to lookup %v in column %q: %w", value, series.Name, err)
+		}
+		rows = series.lookupString(word)
+	}
+	return df.SelectRows(rows)
+}
@@ -0,0 +1,175 @@
+package grizzly
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unsafe"
+)
+
+// exactDistinctLimit is the length up to which distinct values are counted
+// exactly, longer series are estimated with HyperLogLog
+const exactDistinctLimit = 1 << 16
+
+// hyperLogLogPrecision gives 2^14 registers, about 0.8% of standard error
+const hyperLogLogPrecision = 14
+
+// distinctHash hashes a value for HyperLogLog, FNV-1a mixed with the
+// SplitMix64 finalizer to spread its bits
+func distinctHash(data []byte) uint64 {
+	hasher := fnv.New64a()
+	hasher.Write(data)
+	hash := hasher.Sum64()
+	hash ^= hash >> 30
+	hash *= 0xbf58476d1ce4e5b9
+	hash ^= hash >> 27
+	hash *= 0x94d049bb133111eb
+	return hash ^ hash>>31
+}
+
+// Shape returns the number of rows and columns, GetShape returns them in
+// the opposite order
+func (df *DataFrame) Shape() (int, int) {
+	return df.GetLength(), len(df.Columns)
+}
+
+// Dtypes returns the data type of every column by name
+func (df *DataFrame) Dtypes() map[string]string {
+	types := make(map[string]string, len(df.Columns))
+	for _, series := range df.Columns {
+		types[series.Name] = series.DataType
+	}
+	return types
+}
+
+// NonNullCount returns the number of values that are not NaN
+func (series *Series) NonNullCount() int {
+	return series.GetLength() - series.NullCount()
+}
+
+// DistinctEstimate returns the number of distinct values, exact for short
+// series and estimated with HyperLogLog for long ones
+func (series *Series) DistinctEstimate() int {
+	length := series.GetLength()
+	if length <= exactDistinctLimit {
+		if series.DataType == "float" {
+			return len(arrayUniqueValuesFloat(series.Float))
+		}
+		return len(arrayUniqueValuesString(series.strings()))
+	}
+
+	registers := make([]uint8, 1<<hyperLogLogPrecision)
+	add := func(hash uint64) {
+		register := hash >> (64 - hyperLogLogPrecision)
+		rank := uint8(bits.LeadingZeros64(hash<<hyperLogLogPrecision|1<<(hyperLogLogPrecision-1)) + 1)
+		if rank > registers[register] {
+			registers[register] = rank
+		}
+	}
+	if series.DataType == "float" {
+		buffer := make([]byte, 8)
+		for _, value := range series.Float {
+			binary.LittleEndian.PutUint64(buffer, math.Float64bits(value))
+			add(distinctHash(buffer))
+		}
+	} else {
+		for _, value := range series.strings() {
+			add(distinctHash([]byte(value)))
+		}
+	}
+
+	size := float64(len(registers))
+	var sum float64
+	zeros := 0
+	for _, rank := range registers {
+		sum += math.Ldexp(1, -int(rank))
+		if rank == 0 {
+			zeros++
+		}
+	}
+	estimate := 0.7213 / (1 + 1.079/size) * size * size / sum
+	if estimate <= 2.5*size && zeros > 0 {
+		// Linear counting is more accurate for small cardinalities
+		estimate = size * math.Log(size/float64(zeros))
+	}
+	return int(math.Round(estimate))
+}
+
+// MemoryUsage returns an estimate in bytes of the memory held by the
+// values, compressed series report their compressed size
+func (series *Series) MemoryUsage() int {
+	if series.DataType == "float" {
+		return 8 * len(series.Float)
+	}
+	if series.compressed != nil {
+		return series.CompressedSize()
+	}
+	size := int(unsafe.Sizeof("")) * len(series.String)
+	for _, value := range series.String {
+		size += len(value)
+	}
+	return size
+}
+
+func (df *DataFrame) MemoryUsage() int {
+	size := 0
+	for i := range df.Columns {
+		size += df.Columns[i].MemoryUsage()
+	}
+	return size
+}
+
+// formatBytes writes a size with binary units like "1.5 MiB"
+func formatBytes(size int) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return strconv.Itoa(size) + " B"
+	}
+	return strconv.FormatFloat(value, 'f', 1, 64) + " " + units[unit]
+}
+
+// Info writes a summary of df to w: the shape and, for every column, the
+// data type, the number of non null values, the distinct values and the
+// memory used
+func (df *DataFrame) Info(w io.Writer) error {
+	rows, columns := df.Shape()
+	if _, err := fmt.Fprintf(w, "DataFrame: %d rows, %d columns\n", rows, columns); err != nil {
+		return fmt.Errorf("failed to write info: %w", err)
+	}
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join([]string{"#", "Column", "Dtype", "Non-Null", "Distinct", "Memory"}, "\t"))
+	for i := range df.Columns {
+		series := &df.Columns[i]
+		dtype := series.DataType
+		if series.IsCompressed() {
+			dtype += " (compressed)"
+		}
+		fmt.Fprintln(writer, strings.Join([]string{
+			strconv.Itoa(i),
+			series.Name,
+			dtype,
+			strconv.Itoa(series.NonNullCount()),
+			strconv.Itoa(series.DistinctEstimate()),
+			formatBytes(series.MemoryUsage()),
+		}, "\t"))
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write info: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "Memory usage: %s\n", formatBytes(df.MemoryUsage())); err != nil {
+		return fmt.Errorf("failed to write info: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Intervals are stored as a pair of columns holding the start and the end of
+// every row, either numbers or string dates. They are half open, the start
+// is inside the interval and the end is not, so consecutive tiers like 0-100
+// and 100-500 do not overlap.
+
+// seriesTimestamps returns the values of a float series, or the string dates
+// of a string series as Unix seconds with NaN for values that are not dates
+func seriesTimestamps(series *Series) []float64 {
+	if series.DataType == "float" {
+		return series.Float
+	}
+	values := series.strings()
+	result := make([]float64, len(values))
+	for i, value := range values {
+		result[i] = math.NaN()
+		if parsed, ok := parseDateTime(value); ok {
+			result[i] = float64(parsed.UnixNano()) / 1e9
+		}
+	}
+	return result
+}
+
+// intervalBounds reads the start and end columns of an interval
+func intervalBounds(df *DataFrame, start, end string) ([]float64, []float64, error) {
+	startSeries, err := df.GetColumnByName(start)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve interval start %q: %w", start, err)
+	}
+	endSeries, err := df.GetColumnByName(end)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve interval end %q: %w", end, err)
+	}
+	return seriesTimestamps(startSeries), seriesTimestamps(endSeries), nil
+}
+
+// intervalPoint reads a number, a string date or a time as a position
+func intervalPoint(point any) (float64, error) {
+	switch value := point.(type) {
+	case time.Time:
+		return float64(value.UnixNano()) / 1e9, nil
+	case string:
+		if parsed, ok := parseDateTime(value); ok {
+			return float64(parsed.UnixNano()) / 1e9, nil
+		}
+		return 0, fmt.Errorf("%q is not a date", value)
+	default:
+		return interfaceConvertToFloat(point)
+	}
+}
+
+// IntervalContains returns a mask with true for the rows whose interval,
+// between the start and end columns, contains point. point is a number, a
+// string date or a time.Time.
+func (df *DataFrame) IntervalContains(start, end string, point any) ([]bool, error) {
+	starts, ends, err := intervalBounds(df, start, end)
+	if err != nil {
+		return nil, err
+	}
+	position, err := intervalPoint(point)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read interval point: %w", err)
+	}
+	mask := make([]bool, len(starts))
+	for i := range mask {
+		mask[i] = starts[i] <= position && position < ends[i]
+	}
+	return mask, nil
+}
+
+// OverlapJoin matches every row with the rows of otherDf whose interval
+// intersects its own, both DataFrames have the start and end columns. Rows
+// without match are dropped and every pair of overlapping rows is kept, the
+// columns of otherDf repeated on df get the suffix "_right".
+func (df *DataFrame) OverlapJoin(otherDf DataFrame, start, end string) (DataFrame, error) {
+	leftStarts, leftEnds, err := intervalBounds(df, start, end)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to overlap join: %w", err)
+	}
+	rightStarts, rightEnds, err := intervalBounds(&otherDf, start, end)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to overlap join: %w", err)
+	}
+
+	// Sort the right intervals by start, an interval that overlaps a row
+	// starts after the start of the row minus the longest right interval
+	rows := make([]int, 0, len(rightStarts))
+	longest := 0.0
+	for i := range rightStarts {
+		if math.IsNaN(rightStarts[i]) || math.IsNaN(rightEnds[i]) || rightStarts[i] >= rightEnds[i] {
+			continue
+		}
+		rows = append(rows, i)
+		longest = math.Max(longest, rightEnds[i]-rightStarts[i])
+	}
+	sort.SliceStable(rows, func(a, b int) bool {
+		return rightStarts[rows[a]] < rightStarts[rows[b]]
+	})
+
+	length := len(leftStarts)
+	matches := make([][]int, length)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		low := g * chunkSize
+		high := low + chunkSize
+		if low >= length {
+			break
+		}
+		if high > length {
+			high = length
+		}
+		wg.Add(1)
+		go func(low, high int) {
+			defer wg.Done()
+			for i := low; i < high; i++ {
+				from, to := leftStarts[i], leftEnds[i]
+				if math.IsNaN(from) || math.IsNaN(to) || from >= to {
+					continue
+				}
+				first := sort.Search(len(rows), func(j int) bool {
+					return rightStarts[rows[j]] > from-longest
+				})
+				for _, row := range rows[first:] {
+					if rightStarts[row] >= to {
+						break
+					}
+					if rightEnds[row] > from {
+						matches[i] = append(matches[i], row)
+					}
+				}
+			}
+		}(low, high)
+	}
+	wg.Wait()
+
+	var leftRows, rightRows []int
+	for i, rowMatches := range matches {
+		for _, row := range rowMatches {
+			leftRows = append(leftRows, i)
+			rightRows = append(rightRows, row)
+		}
+	}
+	result := DataFrame{Columns: make([]Series, 0, len(df.Columns)+len(otherDf.Columns))}
+	for _, column := range df.Columns {
+		result.Columns = append(result.Columns, seriesTake(column, leftRows))
+	}
+	for _, column := range otherDf.Columns {
+		taken := seriesTake(column, rightRows)
+		if isNameRepeated(result.Columns, taken.Name) {
+			taken.Name = taken.Name + "_right"
+		}
+		result.Columns = append(result.Columns, taken)
+	}
+	return result, nil
+}
@@ -0,0 +1,459 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+func (df *DataFrame) BuildBloomFilter(identifier any, falsePositiveRate float64) error {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to build bloom filter for column %v: %w", identifier, err)
+	}
+	series.BuildBloomFilter(falsePositiveRate)
+	return nil
+}
+
+func (df *DataFrame) SemiJoin(otherDf DataFrame, identifier any) (DataFrame, error) {
+	return df.membershipJoin(otherDf, identifier, true)
+}
+
+func (df *DataFrame) AntiJoin(otherDf DataFrame, identifier any) (DataFrame, error) {
+	return df.membershipJoin(otherDf, identifier, false)
+}
+
+// membershipJoin keeps the rows whose key is (or is not) present in the key
+// column of otherDf, using its bloom filter when one has been built
+func (df *DataFrame) membershipJoin(otherDf DataFrame, identifier any, keep bool) (DataFrame, error) {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to join on column %v: %w", identifier, err)
+	}
+	keys, err := otherDf.GetColumnByName(series.Name)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to join on column %v: %w", identifier, err)
+	}
+	if series.DataType != keys.DataType {
+		return DataFrame{}, fmt.Errorf("cannot join column %q of type %q with column of type %q",
+			series.Name, series.DataType, keys.DataType)
+	}
+
+	mask := series.IsinSeries(keys)
+	indices := make([]int, 0, len(mask))
+	for i, found := range mask {
+		if found == keep {
+			indices = append(indices, i)
+		}
+	}
+	return df.SelectRows(indices)
+}
+
+// JoinOptions configures Merge. How is "inner", "left", "right" or "outer".
+// Suffixes are appended to repeated column names of the left and right
+// DataFrames, "_right" on the right side by default. Validate is "one_to_one", "one_to_many", "many_to_one" or empty,
+// and Indicator adds a "_merge" column telling where each row came from.
+type JoinOptions struct {
+	How       string
+	Suffixes  [2]string
+	Validate  string
+	Indicator bool
+}
+
+// Join combines the rows of both DataFrames with equal key. how is "inner",
+// "left", "right" or "outer"; rows without match are filled with NaN.
+func (df *DataFrame) Join(otherDf DataFrame, identifier any, how string) (DataFrame, error) {
+	return df.Merge(otherDf, identifier, JoinOptions{How: how})
+}
+
+func (df *DataFrame) Merge(otherDf DataFrame, identifier any, options JoinOptions) (DataFrame, error) {
+	span := startSpan("join")
+	result, err := df.merge(otherDf, identifier, options)
+	span.end(err, "left_rows", df.GetLength(), "right_rows", otherDf.GetLength(), "rows", result.GetLength())
+	return result, err
+}
+
+func (df *DataFrame) merge(otherDf DataFrame, identifier any, options JoinOptions) (DataFrame, error) {
+	how := options.How
+	if how == "" {
+		how = "inner"
+	}
+	if options.Suffixes == [2]string{} {
+		options.Suffixes = [2]string{"", "_right"}
+	}
+	if how != "inner" && how != "left" && how != "right" && how != "outer" {
+		return DataFrame{}, fmt.Errorf("unsupported join type %q, use \"inner\", \"left\", \"right\" or \"outer\"", how)
+	}
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to join on column %v: %w", identifier, err)
+	}
+	otherSeries, err := otherDf.GetColumnByName(series.Name)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to join on column %v: %w", identifier, err)
+	}
+	if series.DataType != otherSeries.DataType {
+		return DataFrame{}, fmt.Errorf("cannot join column %q of type %q with column of type %q",
+			series.Name, series.DataType, otherSeries.DataType)
+	}
+
+	if limit := memoryLimit(); limit > 0 {
+		estimate := pairRowBytes * max(series.GetLength(), otherSeries.GetLength())
+		if !otherSeries.HasHashIndex() {
+			estimate += indexRowBytes * otherSeries.GetLength()
+		}
+		if estimate > limit {
+			return df.spillMerge(otherDf, series, otherSeries, how, options, limit)
+		}
+	}
+
+	// Reuse the index of the right column or build a private one
+	keys := *otherSeries
+	if !keys.HasHashIndex() {
+		keys.BuildHashIndex()
+	}
+
+	switch options.Validate {
+	case "":
+	case "one_to_one", "one_to_many", "many_to_one":
+		if options.Validate != "many_to_one" {
+			if err = checkUniqueKeys(*series); err != nil {
+				return DataFrame{}, fmt.Errorf("merge is not %s, left keys: %w", options.Validate, err)
+			}
+		}
+		if options.Validate != "one_to_many" {
+			if err = checkUniqueKeys(keys); err != nil {
+				return DataFrame{}, fmt.Errorf("merge is not %s, right keys: %w", options.Validate, err)
+			}
+		}
+	default:
+		return DataFrame{}, fmt.Errorf("unsupported validation %q", options.Validate)
+	}
+
+	var leftRows []int
+	var rightRows []int
+	leftKeys := series.strings()
+	matched := make([]bool, keys.GetLength())
+	progress := startProgress("join", series.GetLength())
+	defer progress.finish()
+	for i := 0; i < series.GetLength(); i++ {
+		if i%1024 == 1023 {
+			progress.add(1024)
+		}
+		var matches []int
+		if series.DataType == "float" {
+			matches = keys.hashIndex.floats[series.Float[i]]
+		} else {
+			matches = keys.hashIndex.strings[leftKeys[i]]
+		}
+		if len(matches) == 0 {
+			if how == "left" || how == "outer" {
+				leftRows = append(leftRows, i)
+				rightRows = append(rightRows, -1)
+			}
+			continue
+		}
+		for _, match := range matches {
+			leftRows = append(leftRows, i)
+			rightRows = append(rightRows, match)
+			matched[match] = true
+		}
+	}
+	if how == "right" || how == "outer" {
+		for j, found := range matched {
+			if !found {
+				leftRows = append(leftRows, -1)
+				rightRows = append(rightRows, j)
+			}
+		}
+	}
+	if how == "right" {
+		// Keep every matched pair but drop the left only rows
+		keptLeft := leftRows[:0]
+		keptRight := rightRows[:0]
+		for i := range leftRows {
+			if rightRows[i] >= 0 {
+				keptLeft = append(keptLeft, leftRows[i])
+				keptRight = append(keptRight, rightRows[i])
+			}
+		}
+		leftRows, rightRows = keptLeft, keptRight
+	}
+
+	take := func(column Series, right bool) (Series, error) {
+		if right {
+			return seriesTake(column, rightRows), nil
+		}
+		return seriesTake(column, leftRows), nil
+	}
+	takeKey := func(column Series) (Series, error) {
+		return seriesTakeCoalesce(column, keys, leftRows, rightRows), nil
+	}
+	indicator := func() ([]string, error) {
+		indicator := make([]string, len(leftRows))
+		for i := range leftRows {
+			indicator[i] = mergeIndicator(leftRows[i], rightRows[i])
+		}
+		return indicator, nil
+	}
+	return mergeColumns(df, otherDf, series.Name, options, take, takeKey, indicator)
+}
+
+// mergeColumns builds the result of a merge, take copies the matched rows of
+// a left or right column, takeKey the key column and indicator returns the
+// values of the "_merge" column
+func mergeColumns(df *DataFrame, otherDf DataFrame, key string, options JoinOptions, take func(column Series, right bool) (Series, error), takeKey func(column Series) (Series, error), indicator func() ([]string, error)) (DataFrame, error) {
+	leftNames := df.GetColumnNames()
+	rightNames := otherDf.GetColumnNames()
+	var result DataFrame
+	for _, column := range df.Columns {
+		var taken Series
+		var err error
+		if column.Name == key {
+			taken, err = takeKey(column)
+		} else {
+			taken, err = take(column, false)
+			if arrayContainsString(rightNames, column.Name) {
+				taken.Name = taken.Name + options.Suffixes[0]
+			}
+		}
+		if err != nil {
+			return DataFrame{}, err
+		}
+		result.Columns = append(result.Columns, taken)
+	}
+	for _, column := range otherDf.Columns {
+		if column.Name == key {
+			continue
+		}
+		taken, err := take(column, true)
+		if err != nil {
+			return DataFrame{}, err
+		}
+		if arrayContainsString(leftNames, column.Name) {
+			taken.Name = taken.Name + options.Suffixes[1]
+		}
+		if isNameRepeated(result.Columns, taken.Name) {
+			return DataFrame{}, fmt.Errorf("column %q is repeated after the merge, use different suffixes", taken.Name)
+		}
+		result.Columns = append(result.Columns, taken)
+	}
+
+	if options.Indicator {
+		values, err := indicator()
+		if err != nil {
+			return DataFrame{}, err
+		}
+		if err = result.AddSeries(NewStringSeries("_merge", values)); err != nil {
+			return DataFrame{}, fmt.Errorf("failed to add merge indicator: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// mergeIndicator tells where a row of a merge came from
+func mergeIndicator(leftRow, rightRow int) string {
+	switch {
+	case leftRow < 0:
+		return "right_only"
+	case rightRow < 0:
+		return "left_only"
+	}
+	return "both"
+}
+
+// checkUniqueKeys fails with the first repeated value of an indexed series
+func checkUniqueKeys(keys Series) error {
+	if !keys.HasHashIndex() {
+		keys.BuildHashIndex()
+	}
+	values := keys.strings()
+	for i := 0; i < keys.GetLength(); i++ {
+		var rows []int
+		if keys.DataType == "float" {
+			rows = keys.hashIndex.floats[keys.Float[i]]
+		} else {
+			rows = keys.hashIndex.strings[values[i]]
+		}
+		if len(rows) > 1 {
+			return fmt.Errorf("key %q is repeated %d times", keys.GetValueAsString(i), len(rows))
+		}
+	}
+	return nil
+}
+
+// seriesTakeCoalesce builds a key column taking the left value when the row
+// has one and the right value otherwise
+func seriesTakeCoalesce(left Series, right Series, leftRows []int, rightRows []int) Series {
+	result := seriesTake(left, leftRows)
+	compressed := result.IsCompressed()
+	result.invalidateIndexes()
+	rightValues := right.strings()
+	for i, row := range leftRows {
+		if row >= 0 || rightRows[i] < 0 {
+			continue
+		}
+		if result.DataType == "float" {
+			result.Float[i] = right.Float[rightRows[i]]
+		} else {
+			result.String[i] = rightValues[rightRows[i]]
+		}
+	}
+	if compressed {
+		result.Compress()
+	}
+	return result
+}
+
+// seriesTake copies the given rows of a series, negative rows become NaN
+func seriesTake(series Series, rows []int) Series {
+	if series.DataType == "float" {
+		values := make([]float64, len(rows))
+		for i, row := range rows {
+			if row < 0 {
+				values[i] = math.NaN()
+			} else {
+				values[i] = series.Float[row]
+			}
+		}
+		result := NewFloatSeries(series.Name, values)
+		result.Metadata = copyMetadata(series.Metadata)
+		return result
+	}
+	source := series.strings()
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		if row < 0 {
+			values[i] = "NaN"
+		} else {
+			values[i] = source[row]
+		}
+	}
+	result := NewStringSeries(series.Name, values)
+	result.Metadata = copyMetadata(series.Metadata)
+	if series.IsCompressed() {
+		// Compressed columns stay compressed after sorts and joins
+		result.Compress()
+	}
+	return result
+}
+
+// AsOfJoin matches every row with the most recent row of otherDf whose on
+// value is lower or equal, only among rows with the same by value when by is
+// not empty. Matches older than tolerance are discarded, use math.Inf(1) to
+// accept any distance.
+func (df *DataFrame) AsOfJoin(otherDf DataFrame, on string, by string, tolerance float64) (DataFrame, error) {
+	if tolerance < 0 || math.IsNaN(tolerance) {
+		return DataFrame{}, fmt.Errorf("tolerance must be a non negative number")
+	}
+	leftOn, err := df.GetColumnByName(on)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to as-of join on %q: %w", on, err)
+	}
+	rightOn, err := otherDf.GetColumnByName(on)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to as-of join on %q: %w", on, err)
+	}
+	if leftOn.DataType != "float" || rightOn.DataType != "float" {
+		return DataFrame{}, fmt.Errorf("as-of join column %q must be of type float on both dataframes", on)
+	}
+
+	var leftBy, rightBy *Series
+	if by != "" {
+		leftBy, err = df.GetColumnByName(by)
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to as-of join by %q: %w", by, err)
+		}
+		rightBy, err = otherDf.GetColumnByName(by)
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to as-of join by %q: %w", by, err)
+		}
+	}
+
+	// Group the right rows and sort every group by the on column
+	groups := make(map[string][]int)
+	for i := 0; i < rightOn.GetLength(); i++ {
+		if math.IsNaN(rightOn.Float[i]) {
+			continue
+		}
+		group := ""
+		if rightBy != nil {
+			group = rightBy.GetValueAsString(i)
+		}
+		groups[group] = append(groups[group], i)
+	}
+	for _, rows := range groups {
+		sort.SliceStable(rows, func(a, b int) bool {
+			return rightOn.Float[rows[a]] < rightOn.Float[rows[b]]
+		})
+	}
+
+	length := leftOn.GetLength()
+	matches := make([]int, length)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				matches[i] = -1
+				value := leftOn.Float[i]
+				if math.IsNaN(value) {
+					continue
+				}
+				group := ""
+				if leftBy != nil {
+					group = leftBy.GetValueAsString(i)
+				}
+				rows := groups[group]
+				// First row strictly after value, the candidate is the previous one
+				position := sort.Search(len(rows), func(j int) bool {
+					return rightOn.Float[rows[j]] > value
+				})
+				if position == 0 {
+					continue
+				}
+				candidate := rows[position-1]
+				if value-rightOn.Float[candidate] <= tolerance {
+					matches[i] = candidate
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	result := DataFrame{Columns: make([]Series, 0, len(df.Columns)+len(otherDf.Columns))}
+	for _, column := range df.Columns {
+		result.Columns = append(result.Columns, seriesTake(column, identityRows(length)))
+	}
+	for _, column := range otherDf.Columns {
+		if column.Name == on || column.Name == by {
+			continue
+		}
+		taken := seriesTake(column, matches)
+		if isNameRepeated(result.Columns, taken.Name) {
+			taken.Name = taken.Name + "_right"
+		}
+		result.Columns = append(result.Columns, taken)
+	}
+	return result, nil
+}
+
+func identityRows(length int) []int {
+	rows := make([]int, length)
+	for i := range rows {
+		rows[i] = i
+	}
+	return rows
+}
@@ -0,0 +1,101 @@
+package grizzly
+
+import (
+	"fmt"
+	"sync"
+)
+
+// dotBlockSize is the number of inner products accumulated per block, small
+// enough to keep the slices in cache
+const dotBlockSize = 256
+
+func (series *Series) Dot(other Series) (float64, error) {
+	if series.DataType != "float" || other.DataType != "float" {
+		return 0, fmt.Errorf("dot product requires float series")
+	}
+	if err := sameLength("dot product", series, &other); err != nil {
+		return 0, err
+	}
+	chain := arrayFloatPairBase(series.Float, other.Float, func(left, right, result float64) float64 {
+		return result + left*right
+	})
+	var result float64
+	for value := range chain {
+		result += value
+	}
+	return result, nil
+}
+
+// Dot multiplies the float columns of df (n x m) by the float columns of
+// otherDf (m x k). The result has n rows and the column names of otherDf.
+func (df *DataFrame) Dot(otherDf DataFrame) (DataFrame, error) {
+	leftNames, left := df.floatColumns()
+	rightNames, right := otherDf.floatColumns()
+	if len(left) != len(df.Columns) {
+		return DataFrame{}, fmt.Errorf("dot product requires every column of the dataframe to be float")
+	}
+	if len(right) != len(otherDf.Columns) {
+		return DataFrame{}, fmt.Errorf("dot product requires every column of the other dataframe to be float")
+	}
+	if len(left) != otherDf.GetLength() {
+		return DataFrame{}, fmt.Errorf("shapes do not align: %d columns (%v) and %d rows",
+			len(left), leftNames, otherDf.GetLength())
+	}
+
+	numRows := df.GetLength()
+	inner := len(left)
+	result := make([][]float64, len(right))
+	for k := range result {
+		result[k] = make([]float64, numRows)
+	}
+
+	numGoroutines := workerCount()
+	chunkSize := (numRows + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if start >= numRows {
+			break
+		}
+		if end > numRows {
+			end = numRows
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			// Walk the rows in blocks so each column slice is reused while hot
+			for blockStart := start; blockStart < end; blockStart += dotBlockSize {
+				blockEnd := minInt(blockStart+dotBlockSize, end)
+				for k := range right {
+					target := result[k]
+					for j := 0; j < inner; j++ {
+						weight := right[k][j]
+						column := left[j]
+						for i := blockStart; i < blockEnd; i++ {
+							target[i] += column[i] * weight
+						}
+					}
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	product := DataFrame{Columns: make([]Series, len(right))}
+	for k, name := range rightNames {
+		product.Columns[k] = NewFloatSeries(name, result[k])
+	}
+	return product, nil
+}
+
+// arrayFloatPairBase folds two slices chunk by chunk like arrayFloatBase
+func arrayFloatPairBase(left, right []float64, operation func(left, right, result float64) float64) chan float64 {
+	return chunkPartials(len(left), func(start, end int) float64 {
+		var result float64
+		for j := start; j < end; j++ {
+			result = operation(left[j], right[j], result)
+		}
+		return result
+	})
+}
@@ -0,0 +1,120 @@
+package grizzly
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// LiveFrame holds a DataFrame that writers append rows to while readers,
+// like dashboards, take consistent snapshots without locks. A snapshot sees
+// the rows of the last completed write and never the rows of a write in
+// progress.
+//
+// Appends write past the length of the published columns, which snapshots
+// never read, and publish the new lengths atomically. Other changes are
+// made on a copy that replaces the published columns, so readers keep the
+// values they started with.
+type LiveFrame struct {
+	mutex     sync.Mutex // Serializes the writers
+	columns   []Series   // Columns of the writers, with spare capacity to append
+	published atomic.Pointer[[]Series]
+	version   atomic.Uint64
+}
+
+// NewLiveFrame copies df into a LiveFrame. Compressed columns are
+// decompressed so rows can be appended to them.
+func NewLiveFrame(df DataFrame) (*LiveFrame, error) {
+	if err := df.CheckIntegrity(); err != nil {
+		return nil, fmt.Errorf("cannot create a live frame: %w", err)
+	}
+	live := &LiveFrame{columns: make([]Series, len(df.Columns))}
+	for i := range df.Columns {
+		live.columns[i] = df.Columns[i].Copy()
+		if err := live.columns[i].Decompress(); err != nil {
+			return nil, err
+		}
+	}
+	live.publish()
+	return live, nil
+}
+
+// publish makes the current columns visible to new snapshots. The stored
+// series are limited to their length, so appending to a snapshot copies its
+// values instead of writing over rows appended later by the writers.
+func (live *LiveFrame) publish() {
+	columns := make([]Series, len(live.columns))
+	for i, series := range live.columns {
+		columns[i] = Series{Name: series.Name, DataType: series.DataType, Metadata: series.Metadata}
+		columns[i].Float = series.Float[:len(series.Float):len(series.Float)]
+		columns[i].String = series.String[:len(series.String):len(series.String)]
+	}
+	live.published.Store(&columns)
+	live.version.Add(1)
+}
+
+// Snapshot returns the DataFrame as of the last completed write. It shares
+// the values with the LiveFrame but can be read and modified freely: its
+// columns copy their values the first time they are modified.
+func (live *LiveFrame) Snapshot() DataFrame {
+	published := *live.published.Load()
+	snapshot := DataFrame{Columns: make([]Series, len(published))}
+	for i, series := range published {
+		snapshot.Columns[i] = Series{
+			Name:     series.Name,
+			DataType: series.DataType,
+			Metadata: copyMetadata(series.Metadata),
+			Float:    series.Float,
+			String:   series.String,
+			stats:    &statsCache{},
+			shared:   true,
+		}
+	}
+	return snapshot
+}
+
+// Version returns the number of completed writes, it changes when a new
+// Snapshot would see different data
+func (live *LiveFrame) Version() uint64 {
+	return live.version.Load()
+}
+
+// Append adds the rows at the end, rows must have the same column names in
+// any order and are converted like in InsertRows. On error nothing is
+// appended.
+func (live *LiveFrame) Append(rows DataFrame) error {
+	live.mutex.Lock()
+	defer live.mutex.Unlock()
+	df := DataFrame{Columns: live.columns}
+	if err := df.InsertRows(df.GetLength(), rows); err != nil {
+		return fmt.Errorf("failed to append to live frame: %w", err)
+	}
+	live.columns = df.Columns
+	live.publish()
+	return nil
+}
+
+// Update applies any change to a copy of the DataFrame and publishes it when
+// operation returns nil and the columns still have the same length
+func (live *LiveFrame) Update(operation func(df *DataFrame) error) error {
+	live.mutex.Lock()
+	defer live.mutex.Unlock()
+	df := DataFrame{Columns: make([]Series, len(live.columns))}
+	for i := range live.columns {
+		df.Columns[i] = live.columns[i].Copy()
+	}
+	if err := operation(&df); err != nil {
+		return fmt.Errorf("live frame update rolled back: %w", err)
+	}
+	if err := df.CheckIntegrity(); err != nil {
+		return fmt.Errorf("live frame update rolled back: %w", err)
+	}
+	for i := range df.Columns {
+		if err := df.Columns[i].Decompress(); err != nil {
+			return err
+		}
+	}
+	live.columns = df.Columns
+	live.publish()
+	return nil
+}
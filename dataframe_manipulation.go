@@ -2,15 +2,19 @@ package grizzly
 
 import (
 	"fmt"
-	"runtime"
+	"math"
+	"math/rand"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
-func (df *DataFrame) FilterFloat(identifier any, condition func(value float64) bool) error {
+// FilterFloat deletes the rows where condition is true for the values of a
+// float column, the other rows keep their order
+func (df *DataFrame) FilterFloat(identifier any, condition func(value float64) bool) (err error) {
+	defer df.track("FilterFloat", identifier, condition)(&err)
 	var series *Series
-	var err error
 	series, err = df.GetColumnDynamic(identifier)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve column to filter float %v: %w", identifier, err)
@@ -20,122 +24,43 @@ func (df *DataFrame) FilterFloat(identifier any, condition func(value float64) b
 		return fmt.Errorf("column %v is not of type float; actual type is %q", identifier, series.DataType)
 	}
 
-	length := len(series.Float) // Use the actual length of the float slice
-	if length == 0 {
-		return nil
-	}
-
-	// Determine number of goroutines
-	numGoroutines := runtime.NumCPU()
-	chunkSize := (length + numGoroutines - 1) / numGoroutines // Calculate chunk size
-	var wg sync.WaitGroup
-
-	for i := 0; i < numGoroutines; i++ {
-		start := i * chunkSize
-		end := start + chunkSize
-		if start >= length {
-			break // Ensure we don't start beyond the slice length
-		}
-		if end > length {
-			end = length // Adjust end index to stay within bounds
-		}
-
-		wg.Add(1)
-		go func(start, end int) {
-			defer wg.Done()
-			for j := start; j < end; j++ {
-				if j >= length {
-					// Double-check bounds to prevent any unexpected issues
-					break
-				}
-				if condition(series.Float[j]) {
-					for i, column := range df.Columns {
-						if column.DataType == "float" {
-							df.Columns[i].Float = append(column.Float[:j], column.Float[j+1:]...)
-						} else {
-							df.Columns[i].String = append(column.String[:j], column.String[j+1:]...)
-						}
-					}
-				}
-			}
-		}(start, end)
+	mask, err := series.MaskFloat(condition)
+	if err != nil {
+		return err
 	}
-
-	// Closing channel after all goroutines finish
-	go func() {
-		wg.Wait()
-	}()
-	return nil
+	return df.filterMask(MaskNot(mask))
 }
 
-func (df *DataFrame) FilterString(identifier any, condition func(value string) bool) error {
+// FilterString deletes the rows where condition is true for the values of
+// a string column, the other rows keep their order
+func (df *DataFrame) FilterString(identifier any, condition func(value string) bool) (err error) {
+	defer df.track("FilterString", identifier, condition)(&err)
 	var series *Series
-	var err error
 	series, err = df.GetColumnDynamic(identifier)
 
 	if err != nil {
 		return fmt.Errorf("failed to retrieve column to filter string %v: %w", identifier, err)
 	}
 
-	if series.DataType != "float" {
+	if series.DataType != "string" {
 		return fmt.Errorf("column %v is not of type string; actual type is %q", identifier, series.DataType)
 	}
 
-	length := len(series.Float) // Use the actual length of the float slice
-	if length == 0 {
-		return nil
-	}
-
-	// Determine number of goroutines
-	numGoroutines := runtime.NumCPU()
-	chunkSize := (length + numGoroutines - 1) / numGoroutines // Calculate chunk size
-	var wg sync.WaitGroup
-
-	for i := 0; i < numGoroutines; i++ {
-		start := i * chunkSize
-		end := start + chunkSize
-		if start >= length {
-			break // Ensure we don't start beyond the slice length
-		}
-		if end > length {
-			end = length // Adjust end index to stay within bounds
-		}
-
-		wg.Add(1)
-		go func(start, end int) {
-			defer wg.Done()
-			for j := start; j < end; j++ {
-				if j >= length {
-					// Double-check bounds to prevent any unexpected issues
-					break
-				}
-				if condition(series.String[j]) {
-					for i, column := range df.Columns {
-						if column.DataType == "float" {
-							df.Columns[i].Float = append(column.Float[:j], column.Float[j+1:]...)
-						} else {
-							df.Columns[i].String = append(column.String[:j], column.String[j+1:]...)
-						}
-					}
-				}
-			}
-		}(start, end)
+	mask, err := series.MaskString(condition)
+	if err != nil {
+		return err
 	}
-
-	// Closing channel after all goroutines finish
-	go func() {
-		wg.Wait()
-	}()
-	return nil
+	return df.filterMask(MaskNot(mask))
 }
 
-func (df *DataFrame) ApplyFloat(identifier any, operation func(float64) float64) error {
-	var err error
+func (df *DataFrame) ApplyFloat(identifier any, operation func(float64) float64) (err error) {
+	defer df.track("ApplyFloat", identifier, operation)(&err)
 	// Retrieve the series
 	series, err := df.GetColumnDynamic(identifier)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve column to apply float %v: %w", identifier, err)
 	}
+	series.invalidateIndexes()
 
 	if series.DataType != "float" {
 		return fmt.Errorf("column %v is not of type float; actual type is %q", identifier, series.DataType)
@@ -148,7 +73,7 @@ func (df *DataFrame) ApplyFloat(identifier any, operation func(float64) float64)
 	}
 
 	// Determine the number of goroutines based on available CPUs
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	chunkSize := (numElements + numGoroutines - 1) / numGoroutines
 
 	var wg sync.WaitGroup
@@ -175,13 +100,14 @@ func (df *DataFrame) ApplyFloat(identifier any, operation func(float64) float64)
 	return nil
 }
 
-func (df *DataFrame) ApplyString(identifier any, operation func(string) string) error {
-	var err error
+func (df *DataFrame) ApplyString(identifier any, operation func(string) string) (err error) {
+	defer df.track("ApplyString", identifier, operation)(&err)
 	// Retrieve the series
 	series, err := df.GetColumnDynamic(identifier)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve column to apply string %v: %w", identifier, err)
 	}
+	series.invalidateIndexes()
 
 	if series.DataType != "string" {
 		return fmt.Errorf("column %v is not of type string; actual type is %q", identifier, series.DataType)
@@ -194,7 +120,7 @@ func (df *DataFrame) ApplyString(identifier any, operation func(string) string)
 	}
 
 	// Determine the number of goroutines based on available CPUs
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	chunkSize := (numElements + numGoroutines - 1) / numGoroutines
 
 	var wg sync.WaitGroup
@@ -221,7 +147,8 @@ func (df *DataFrame) ApplyString(identifier any, operation func(string) string)
 	return nil
 }
 
-func (df *DataFrame) ReplaceWholeWord(identifier any, old, new string) error {
+func (df *DataFrame) ReplaceWholeWord(identifier any, old, new string) (err error) {
+	defer df.track("ReplaceWholeWord", identifier, old, new)(&err)
 	// Retrieve the column by name
 	series, err := df.GetColumnDynamic(identifier)
 	if err != nil {
@@ -233,7 +160,8 @@ func (df *DataFrame) ReplaceWholeWord(identifier any, old, new string) error {
 	return nil
 }
 
-func (df *DataFrame) Replace(identifier any, old, new any) error {
+func (df *DataFrame) Replace(identifier any, old, new any) (err error) {
+	defer df.track("Replace", identifier, old, new)(&err)
 	// Retrieve the column by name
 	series, err := df.GetColumnDynamic(identifier)
 	if err != nil {
@@ -250,6 +178,110 @@ func (df *DataFrame) Replace(identifier any, old, new any) error {
 	return nil
 }
 
+// ReplaceMany applies a dictionary of replacements in a single pass, see
+// Series.ReplaceMany
+func (df *DataFrame) ReplaceMany(identifier any, replacements map[string]string) (err error) {
+	defer df.track("ReplaceMany", identifier, replacements)(&err)
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to replace in column %v: %w", identifier, err)
+	}
+	return series.ReplaceMany(replacements)
+}
+
+func (df *DataFrame) ReplacePatterns(identifier any, rules []PatternReplacement) (err error) {
+	defer df.track("ReplacePatterns", identifier, rules)(&err)
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to replace in column %v: %w", identifier, err)
+	}
+	return series.ReplacePatterns(rules)
+}
+
+func (df *DataFrame) MaskEmail(identifier any) (err error) {
+	defer df.track("MaskEmail", identifier)(&err)
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to mask emails in column %v: %w", identifier, err)
+	}
+	return series.MaskEmail()
+}
+
+// HashColumn replaces the values of a column with their salted SHA-256 hash
+func (df *DataFrame) HashColumn(identifier any, salt string) (err error) {
+	// The salt is a secret, it is not recorded in the history
+	defer df.track("HashColumn", identifier)(&err)
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to hash column %v: %w", identifier, err)
+	}
+	series.HashValues(salt)
+	return nil
+}
+
+func (df *DataFrame) RedactPattern(identifier any, pattern, replacement string) (err error) {
+	defer df.track("RedactPattern", identifier, pattern, replacement)(&err)
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to redact column %v: %w", identifier, err)
+	}
+	return series.RedactPattern(pattern, replacement)
+}
+
+func (df *DataFrame) GeneralizeNumeric(identifier any, width float64) (err error) {
+	defer df.track("GeneralizeNumeric", identifier, width)(&err)
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to generalize column %v: %w", identifier, err)
+	}
+	return series.GeneralizeNumeric(width)
+}
+
+func (df *DataFrame) GeneralizeString(identifier any, keep int) (err error) {
+	defer df.track("GeneralizeString", identifier, keep)(&err)
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to generalize column %v: %w", identifier, err)
+	}
+	return series.GeneralizeString(keep)
+}
+
+// KAnonymity returns the size of the smallest group of rows sharing the
+// values of the quasi-identifier columns, the frame is k-anonymous for
+// every k up to it
+func (df *DataFrame) KAnonymity(identifiers ...any) (int, error) {
+	groups, err := df.groupBy(identifiers)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute k-anonymity: %w", err)
+	}
+	smallest := 0
+	for i, rows := range groups.GetGroupIndexes() {
+		if i == 0 || len(rows) < smallest {
+			smallest = len(rows)
+		}
+	}
+	return smallest, nil
+}
+
+// SuppressRareGroups removes the rows whose quasi-identifier values are
+// shared by less than k rows, so the rest of the frame is k-anonymous
+func (df *DataFrame) SuppressRareGroups(k int, identifiers ...any) (err error) {
+	defer df.track("SuppressRareGroups", k, identifiers)(&err)
+	groups, err := df.groupBy(identifiers)
+	if err != nil {
+		return fmt.Errorf("failed to suppress rare groups: %w", err)
+	}
+	mask := make([]bool, df.GetLength())
+	for _, rows := range groups.GetGroupIndexes() {
+		if len(rows) >= k {
+			for _, row := range rows {
+				mask[row] = true
+			}
+		}
+	}
+	return df.Filter(mask)
+}
+
 func (df *DataFrame) DropByIndex(index ...int) DataFrame {
 	var newSeries []Series
 	var oldSeries []Series
@@ -261,7 +293,7 @@ func (df *DataFrame) DropByIndex(index ...int) DataFrame {
 		}
 	}
 	df.Columns = newSeries
-	return DataFrame{oldSeries}
+	return DataFrame{Columns: oldSeries}
 }
 
 func (df *DataFrame) DropByName(name ...string) DataFrame {
@@ -275,7 +307,7 @@ func (df *DataFrame) DropByName(name ...string) DataFrame {
 		}
 	}
 	df.Columns = newSeries
-	return DataFrame{oldSeries}
+	return DataFrame{Columns: oldSeries}
 }
 
 func (df *DataFrame) DropDynamic(identifier any) (DataFrame, error) {
@@ -304,9 +336,9 @@ func (df *DataFrame) DropDynamic(identifier any) (DataFrame, error) {
 	return oldDataFrame, nil
 }
 
-func (df *DataFrame) ConvertStringToFloat(identifiers ...any) error {
+func (df *DataFrame) ConvertStringToFloat(identifiers ...any) (err error) {
+	defer df.track("ConvertStringToFloat", identifiers)(&err)
 	var check *Series
-	var err error
 
 	for _, identifier := range identifiers {
 		check, err = df.GetColumnDynamic(identifier)
@@ -318,9 +350,9 @@ func (df *DataFrame) ConvertStringToFloat(identifiers ...any) error {
 	return nil
 }
 
-func (df *DataFrame) ConvertFloatToString(identifiers ...any) error {
+func (df *DataFrame) ConvertFloatToString(identifiers ...any) (err error) {
+	defer df.track("ConvertFloatToString", identifiers)(&err)
 	var check *Series
-	var err error
 
 	for _, identifier := range identifiers {
 		check, err = df.GetColumnDynamic(identifier)
@@ -332,8 +364,8 @@ func (df *DataFrame) ConvertFloatToString(identifiers ...any) error {
 	return nil
 }
 
-func (df *DataFrame) SplitColumn(identifier any, delimiter string, newColumnNames []string) error {
-	var err error
+func (df *DataFrame) SplitColumn(identifier any, delimiter string, newColumnNames []string) (err error) {
+	defer df.track("SplitColumn", identifier, delimiter, newColumnNames)(&err)
 	var column *Series
 	column, err = df.GetColumnDynamic(identifier)
 	if err != nil {
@@ -348,7 +380,7 @@ func (df *DataFrame) SplitColumn(identifier any, delimiter string, newColumnName
 	}
 
 	numElements := column.GetLength()
-	numGoroutines := runtime.NumCPU() // Use number of available CPUs for parallelism
+	numGoroutines := workerCount() // Use number of available CPUs for parallelism
 	chunkSize := (numElements + numGoroutines - 1) / numGoroutines
 
 	// Create slices to hold the new column values
@@ -357,6 +389,7 @@ func (df *DataFrame) SplitColumn(identifier any, delimiter string, newColumnName
 		splitValues[i] = make([]string, numElements)
 	}
 
+	values := column.strings()
 	var wg sync.WaitGroup
 
 	// Split the work among goroutines
@@ -374,7 +407,7 @@ func (df *DataFrame) SplitColumn(identifier any, delimiter string, newColumnName
 		go func(start, end int) {
 			defer wg.Done()
 			for i := start; i < end; i++ {
-				parts := strings.Split(column.String[i], delimiter)
+				parts := strings.Split(values[i], delimiter)
 				for j := 0; j < len(newColumnNames); j++ {
 					if j < len(parts) {
 						splitValues[j][i] = parts[j]
@@ -401,10 +434,10 @@ func (df *DataFrame) SplitColumn(identifier any, delimiter string, newColumnName
 	return nil
 }
 
-func (df *DataFrame) JoinColumns(identifier1, identifier2 any, delimiter, newColumnName string) error {
+func (df *DataFrame) JoinColumns(identifier1, identifier2 any, delimiter, newColumnName string) (err error) {
+	defer df.track("JoinColumns", identifier1, identifier2, delimiter, newColumnName)(&err)
 	var column1 *Series
 	var column2 *Series
-	var err error
 	// Retrieve the columns to be joined
 	column1, err = df.GetColumnDynamic(identifier1)
 	if err != nil {
@@ -422,9 +455,10 @@ func (df *DataFrame) JoinColumns(identifier1, identifier2 any, delimiter, newCol
 
 	numElements := column1.GetLength()
 	joinedValues := make([]string, numElements)
+	values1, values2 := column1.strings(), column2.strings()
 
 	// Use goroutines to join columns in parallel for large datasets
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	if numGoroutines > numElements {
 		numGoroutines = numElements // Limit the number of goroutines to the number of elements
 	}
@@ -443,7 +477,7 @@ func (df *DataFrame) JoinColumns(identifier1, identifier2 any, delimiter, newCol
 		go func(start, end int) {
 			defer wg.Done()
 			for i := start; i < end; i++ {
-				joinedValues[i] = column1.String[i] + delimiter + column2.String[i]
+				joinedValues[i] = values1[i] + delimiter + values2[i]
 			}
 		}(start, end)
 	}
@@ -460,7 +494,9 @@ func (df *DataFrame) JoinColumns(identifier1, identifier2 any, delimiter, newCol
 	return nil
 }
 
-func (df *DataFrame) SliceRows(low int, high int) error {
+func (df *DataFrame) SliceRows(low int, high int) (err error) {
+	defer df.track("SliceRows", low, high)(&err)
+	df.invalidateIndexes()
 	if low < 0 || high >= df.GetNumberOfColumns() {
 		return fmt.Errorf("out of range")
 	}
@@ -492,14 +528,22 @@ func (df *DataFrame) SelectRows(indices []int) (DataFrame, error) {
 		selected.Columns[i] = Series{
 			Name:     col.Name,
 			DataType: col.DataType,
+			Metadata: copyMetadata(col.Metadata),
+			stats:    &statsCache{},
 		}
 		if col.DataType == "float" {
 			for _, index := range indices {
 				selected.Columns[i].Float = append(selected.Columns[i].Float, col.Float[index])
 			}
 		} else { // For "string" or other types
+			values := col.strings()
 			for _, index := range indices {
-				selected.Columns[i].String = append(selected.Columns[i].String, col.String[index])
+				selected.Columns[i].String = append(selected.Columns[i].String, values[index])
+			}
+			if col.IsCompressed() {
+				if err := selected.Columns[i].Compress(); err != nil {
+					return DataFrame{}, err
+				}
 			}
 		}
 	}
@@ -507,7 +551,8 @@ func (df *DataFrame) SelectRows(indices []int) (DataFrame, error) {
 	return selected, nil
 }
 
-func (df *DataFrame) SliceColumns(low, high int) error {
+func (df *DataFrame) SliceColumns(low, high int) (err error) {
+	defer df.track("SliceColumns", low, high)(&err)
 	if low < 0 || high >= df.GetNumberOfColumns() {
 		return fmt.Errorf("out of range")
 	}
@@ -529,9 +574,10 @@ func (df *DataFrame) MergeDataFrame(otherDf DataFrame) error {
 	return nil
 }
 
-func (df *DataFrame) Concatenate(otherDf DataFrame) error {
+func (df *DataFrame) Concatenate(otherDf DataFrame) (err error) {
+	defer df.track("Concatenate", otherDf.GetShape())(&err)
+	df.invalidateIndexes()
 	var newColumn Series
-	var err error
 
 	otherNames := otherDf.GetColumnNames()
 	names := df.GetColumnNames()
@@ -568,12 +614,61 @@ func (df *DataFrame) Concatenate(otherDf DataFrame) error {
 		if series.DataType == "float" {
 			series.Float = append(series.Float, otherSeries.Float...)
 		} else if series.DataType == "string" {
-			series.String = append(series.String, otherSeries.String...)
+			series.String = append(series.String, otherSeries.strings()...)
 		}
 	}
 	return nil
 }
 
+// unionFrames stacks the rows of frames with the union of their columns in
+// order of appearance. Missing columns are filled with NaN and a column that
+// is a string in any frame becomes a string column.
+func unionFrames(frames []DataFrame) DataFrame {
+	var names []string
+	types := map[string]string{}
+	total := 0
+	for _, frame := range frames {
+		total += frame.GetLength()
+		for _, series := range frame.Columns {
+			if _, ok := types[series.Name]; !ok {
+				names = append(names, series.Name)
+				types[series.Name] = series.DataType
+			}
+			if series.DataType == "string" {
+				types[series.Name] = "string"
+			}
+		}
+	}
+
+	result := DataFrame{Columns: make([]Series, len(names))}
+	for i, name := range names {
+		series := Series{Name: name, DataType: types[name]}
+		if series.DataType == "float" {
+			series.Float = make([]float64, 0, total)
+		} else {
+			series.String = make([]string, 0, total)
+		}
+		for _, frame := range frames {
+			length := frame.GetLength()
+			source, err := frame.GetColumnByName(name)
+			for row := 0; row < length; row++ {
+				switch {
+				case err != nil && series.DataType == "float":
+					series.Float = append(series.Float, math.NaN())
+				case err != nil:
+					series.String = append(series.String, "NaN")
+				case series.DataType == "float":
+					series.Float = append(series.Float, source.Float[row])
+				default:
+					series.String = append(series.String, source.GetValueAsString(row))
+				}
+			}
+		}
+		result.Columns[i] = series
+	}
+	return result
+}
+
 func (df *DataFrame) DuplicateColumn(identifiers ...any) error {
 	var ptr *Series
 	var series Series
@@ -594,11 +689,11 @@ func (df *DataFrame) DuplicateColumn(identifiers ...any) error {
 	return nil
 }
 
-func (df *DataFrame) MathBase(identifier1, identifier2 any, newColumnName string, operation func(float64, float64) float64) error {
+func (df *DataFrame) MathBase(identifier1, identifier2 any, newColumnName string, operation func(float64, float64) float64) (err error) {
+	defer df.track("MathBase", identifier1, identifier2, newColumnName, operation)(&err)
 	var newColumn Series
 	var series1 *Series
 	var series2 *Series
-	var err error
 
 	series1, err = df.GetColumnDynamic(identifier1)
 	if err != nil {
@@ -611,6 +706,9 @@ func (df *DataFrame) MathBase(identifier1, identifier2 any, newColumnName string
 	if !(series1.DataType == "float") || !(series2.DataType == "float") {
 		return fmt.Errorf("math operation only supports floating point values")
 	}
+	if err = sameLength("math operation", series1, series2); err != nil {
+		return err
+	}
 	size := series1.GetLength()
 	if size == 0 {
 		return nil
@@ -622,7 +720,7 @@ func (df *DataFrame) MathBase(identifier1, identifier2 any, newColumnName string
 		Float:    make([]float64, size),
 	}
 
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	chunkSize := (size + numGoroutines - 1) / numGoroutines
 
 	var wg sync.WaitGroup
@@ -671,11 +769,13 @@ func (df *DataFrame) Division(identifier1, identifier2 any, newColumnName string
 	return df.MathBase(identifier1, identifier2, newColumnName, func(x, y float64) float64 { return x / y })
 }
 
-func (df *DataFrame) SetFloatValue(identifier any, rowIndex int, newValue float64) error {
+func (df *DataFrame) SetFloatValue(identifier any, rowIndex int, newValue float64) (err error) {
+	defer df.track("SetFloatValue", identifier, rowIndex, newValue)(&err)
 	series, err := df.GetColumnDynamic(identifier)
 	if err != nil {
 		return fmt.Errorf("failed to set value for column %v: %w", identifier, err)
 	}
+	series.invalidateIndexes()
 	if series.DataType != "float" {
 		return fmt.Errorf("column %v only supports floating point values", identifier)
 	}
@@ -683,11 +783,13 @@ func (df *DataFrame) SetFloatValue(identifier any, rowIndex int, newValue float6
 	return nil
 }
 
-func (df *DataFrame) SetStringValue(identifier any, rowIndex int, newValue string) error {
+func (df *DataFrame) SetStringValue(identifier any, rowIndex int, newValue string) (err error) {
+	defer df.track("SetStringValue", identifier, rowIndex, newValue)(&err)
 	series, err := df.GetColumnDynamic(identifier)
 	if err != nil {
 		return fmt.Errorf("failed to set value for column %v: %w", identifier, err)
 	}
+	series.invalidateIndexes()
 	if series.DataType != "string" {
 		return fmt.Errorf("column %v only supports string values", identifier)
 	}
@@ -695,11 +797,13 @@ func (df *DataFrame) SetStringValue(identifier any, rowIndex int, newValue strin
 	return nil
 }
 
-func (df *DataFrame) SetValue(identifier any, rowIndex int, newValue any) error {
+func (df *DataFrame) SetValue(identifier any, rowIndex int, newValue any) (err error) {
+	defer df.track("SetValue", identifier, rowIndex, newValue)(&err)
 	series, err := df.GetColumnDynamic(identifier)
 	if err != nil {
 		return fmt.Errorf("failed to set value for column %v: %w", identifier, err)
 	}
+	series.invalidateIndexes()
 	if series.DataType == "string" {
 		newS, err := interfaceConvertToString(newValue)
 		if err != nil {
@@ -735,7 +839,7 @@ func (df *DataFrame) GetStringValue(identifier, rowIndex int) (string, error) {
 	if series.DataType != "string" {
 		return "", fmt.Errorf("column %q only supports string tasks", identifier)
 	}
-	return series.String[rowIndex], nil
+	return series.GetValueString(rowIndex), nil
 }
 
 func (df *DataFrame) GetValue(identifier any, rowIndex int) (any, error) {
@@ -746,10 +850,12 @@ func (df *DataFrame) GetValue(identifier any, rowIndex int) (any, error) {
 	if series.DataType == "float" {
 		return series.Float[rowIndex], nil
 	}
-	return series.String[rowIndex], nil
+	return series.GetValueString(rowIndex), nil
 }
 
 func (df *DataFrame) Expand(size int, defaultFloat float64, defaultString string) {
+	defer df.track("Expand", size, defaultFloat, defaultString)(nil)
+	df.invalidateIndexes()
 	for i, series := range df.Columns {
 		if series.DataType == "string" {
 			temp := make([]string, size)
@@ -757,18 +863,18 @@ func (df *DataFrame) Expand(size int, defaultFloat float64, defaultString string
 				temp[n] = defaultString
 			}
 			df.Columns[i].String = append(series.String, temp...)
-			return
+			continue
 		}
 		temp := make([]float64, size)
 		for n := range temp {
 			temp[n] = defaultFloat
 		}
 		df.Columns[i].Float = append(series.Float, temp...)
-		return
 	}
 }
 
 func (df *DataFrame) SwapRows(index1, index2 int) error {
+	df.invalidateIndexes()
 	size := df.GetLength()
 	if index1 < 0 || index1 >= size || index2 < 0 || index2 >= size {
 		return fmt.Errorf("row index out of bounds")
@@ -787,14 +893,18 @@ func (df *DataFrame) SwapRows(index1, index2 int) error {
 }
 
 // Sort QuickSort sorts the array in place using the QuickSort algorithm
-func (df *DataFrame) Sort(identifier any, internal ...int) error {
+func (df *DataFrame) Sort(identifier any, internal ...int) (err error) {
+	defer df.track("Sort", identifier, internal)(&err)
 	var low int
 	var high int
-	var p int
-	var err error
+	var progress *progressTracker
 	if len(internal) == 0 {
+		// Rows are swapped in place, compressed columns are decompressed first
+		df.invalidateIndexes()
 		low = 0
 		high = df.GetLength() - 1
+		progress = startProgress("sort", df.GetLength())
+		defer progress.finish()
 	} else {
 		low = internal[0]
 		high = internal[1]
@@ -803,6 +913,28 @@ func (df *DataFrame) Sort(identifier any, internal ...int) error {
 	if err != nil {
 		return fmt.Errorf("error sorting %v: %w", identifier, err)
 	}
+	if len(internal) == 0 && series.DataType == "float" && df.GetLength() >= radixSortThreshold {
+		// Large float columns are sorted with radix sort in one pass
+		indices := radixSortIndices(series.Float, false, false)
+		defer intPool.put(indices)
+		df.invalidateIndexes()
+		for i, column := range df.Columns {
+			df.Columns[i] = seriesTake(column, indices)
+		}
+		progress.add(len(indices))
+		return nil
+	}
+	return df.sortRange(series, low, high, progress)
+}
+
+// sortRange sorts rows low to high, progress counts the rows that reached
+// their final position
+func (df *DataFrame) sortRange(series *Series, low, high int, progress *progressTracker) error {
+	var p int
+	var err error
+	if low == high {
+		progress.add(1)
+	}
 	if low < high {
 		// Partition the array
 		if series.DataType == "float" {
@@ -817,12 +949,14 @@ func (df *DataFrame) Sort(identifier any, internal ...int) error {
 			}
 		}
 
+		progress.add(1)
+
 		// Recursively sort the sub-arrays
-		err = df.Sort(identifier, low, p-1)
+		err = df.sortRange(series, low, p-1, progress)
 		if err != nil {
 			return fmt.Errorf("error sorting dataframe")
 		}
-		err = df.Sort(identifier, p+1, high)
+		err = df.sortRange(series, p+1, high, progress)
 		if err != nil {
 			return fmt.Errorf("error sorting dataframe")
 		}
@@ -878,3 +1012,22 @@ func (df *DataFrame) PartitionFloat(column *Series, low, high int) (int, error)
 	}
 	return i + 1, nil
 }
+
+// Shuffle reorders the rows randomly, the same seed always gives the same
+// order and a seed of 0 uses the current time
+func (df *DataFrame) Shuffle(seed int) (err error) {
+	defer df.track("Shuffle", seed)(&err)
+	var rng *rand.Rand
+	if seed != 0 {
+		rng = rand.New(rand.NewSource(int64(seed)))
+	} else {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	shuffled, err := df.SelectRows(rng.Perm(df.GetLength()))
+	if err != nil {
+		return fmt.Errorf("failed to shuffle rows: %w", err)
+	}
+	df.invalidateIndexes()
+	df.Columns = shuffled.Columns
+	return nil
+}
@@ -0,0 +1,135 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// KNN returns the k rows nearest to queryRow over the float columns of df,
+// ordered by distance. metric is "euclidean", "cosine" or "manhattan". Rows
+// with NaN values are skipped.
+func KNN(df DataFrame, queryRow []float64, k int, metric string) ([]int, []float64, error) {
+	_, columns := df.floatColumns()
+	if len(columns) != len(queryRow) {
+		return nil, nil, fmt.Errorf("query has %d values but the dataframe has %d float columns", len(queryRow), len(columns))
+	}
+	if k <= 0 {
+		return nil, nil, fmt.Errorf("k must be greater than 0")
+	}
+	distance, err := knnMetric(metric)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	length := df.GetLength()
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	partials := make([][]knnCandidate, numGoroutines)
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end, g int) {
+			defer wg.Done()
+			buffer := make([]float64, len(columns))
+			var local []knnCandidate
+			for i := start; i < end; i++ {
+				complete := true
+				for j, column := range columns {
+					buffer[j] = column[i]
+					if math.IsNaN(buffer[j]) {
+						complete = false
+						break
+					}
+				}
+				if !complete {
+					continue
+				}
+				local = knnInsert(local, knnCandidate{row: i, distance: distance(queryRow, buffer)}, k)
+			}
+			partials[g] = local
+		}(start, end, g)
+	}
+	wg.Wait()
+
+	var nearest []knnCandidate
+	for _, partial := range partials {
+		for _, candidate := range partial {
+			nearest = knnInsert(nearest, candidate, k)
+		}
+	}
+	indexes := make([]int, len(nearest))
+	distances := make([]float64, len(nearest))
+	for i, candidate := range nearest {
+		indexes[i] = candidate.row
+		distances[i] = candidate.distance
+	}
+	return indexes, distances, nil
+}
+
+type knnCandidate struct {
+	row      int
+	distance float64
+}
+
+// knnInsert keeps the k closest candidates sorted, ties go to the lower row
+func knnInsert(nearest []knnCandidate, candidate knnCandidate, k int) []knnCandidate {
+	less := func(a, b knnCandidate) bool {
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		return a.row < b.row
+	}
+	if len(nearest) == k && !less(candidate, nearest[k-1]) {
+		return nearest
+	}
+	position := sort.Search(len(nearest), func(i int) bool {
+		return less(candidate, nearest[i])
+	})
+	if len(nearest) < k {
+		nearest = append(nearest, knnCandidate{})
+	}
+	copy(nearest[position+1:], nearest[position:])
+	nearest[position] = candidate
+	return nearest
+}
+
+func knnMetric(metric string) (func(a, b []float64) float64, error) {
+	switch metric {
+	case "", "euclidean":
+		return func(a, b []float64) float64 {
+			return math.Sqrt(squaredDistance(a, b))
+		}, nil
+	case "manhattan":
+		return func(a, b []float64) float64 {
+			var sum float64
+			for i := range a {
+				sum += math.Abs(a[i] - b[i])
+			}
+			return sum
+		}, nil
+	case "cosine":
+		return func(a, b []float64) float64 {
+			var dot, normA, normB float64
+			for i := range a {
+				dot += a[i] * b[i]
+				normA += a[i] * a[i]
+				normB += b[i] * b[i]
+			}
+			if normA == 0 || normB == 0 {
+				return 1
+			}
+			return 1 - dot/math.Sqrt(normA*normB)
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported metric %q, use \"euclidean\", \"cosine\" or \"manhattan\"", metric)
+}
@@ -0,0 +1,136 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// KeyInterval groups a date column, or a float column of Unix seconds, by
+// consecutive intervals of any length counted from the Unix epoch, like
+// 5*time.Minute. The key is named column_interval and holds the start of the
+// interval in UTC, values that are not dates are grouped under "NaN".
+func KeyInterval(identifier any, interval time.Duration) GroupKey {
+	return GroupKey{name: fmt.Sprintf("%v_interval", identifier), compute: func(df *DataFrame) (Series, error) {
+		if interval <= 0 {
+			return Series{}, fmt.Errorf("interval must be positive, got %v", interval)
+		}
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return Series{}, fmt.Errorf("failed to retrieve date column %v: %w", identifier, err)
+		}
+		values := make([]string, series.GetLength())
+		for row, start := range intervalStarts(seriesTimestamps(series), interval) {
+			if math.IsNaN(start) {
+				values[row] = "NaN"
+				continue
+			}
+			values[row] = time.Unix(0, int64(start*1e9)).UTC().Format("2006-01-02 15:04:05")
+		}
+		return NewStringSeries(series.Name+"_interval", values), nil
+	}}
+}
+
+// intervalStarts floors every timestamp to the start of its interval
+func intervalStarts(timestamps []float64, interval time.Duration) []float64 {
+	seconds := interval.Seconds()
+	starts := make([]float64, len(timestamps))
+	for i, timestamp := range timestamps {
+		starts[i] = math.Floor(timestamp/seconds) * seconds
+	}
+	return starts
+}
+
+// OHLC aggregates trades into bars of the given interval with the columns
+// start, open, high, low, close, volume and trades. open and close are the
+// prices of the earliest and the latest trade of the bar, volume is the sum
+// of volumeIdentifier and is left out when it is nil. Bars are sorted by
+// start and intervals without trades are skipped, as are rows whose time or
+// price is NaN.
+func (df *DataFrame) OHLC(timeIdentifier, priceIdentifier, volumeIdentifier any, interval time.Duration) (DataFrame, error) {
+	timeSeries, err := df.GetColumnDynamic(timeIdentifier)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to retrieve time column %v: %w", timeIdentifier, err)
+	}
+	price, err := df.GetColumnDynamic(priceIdentifier)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to retrieve price column %v: %w", priceIdentifier, err)
+	}
+	if price.DataType != "float" {
+		return DataFrame{}, fmt.Errorf("price column %v is not of type float", priceIdentifier)
+	}
+	var volume *Series
+	if volumeIdentifier != nil {
+		if volume, err = df.GetColumnDynamic(volumeIdentifier); err != nil {
+			return DataFrame{}, fmt.Errorf("failed to retrieve volume column %v: %w", volumeIdentifier, err)
+		}
+		if volume.DataType != "float" {
+			return DataFrame{}, fmt.Errorf("volume column %v is not of type float", volumeIdentifier)
+		}
+	}
+	groupBy, err := df.GroupBy(KeyInterval(timeIdentifier, interval))
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to group trades: %w", err)
+	}
+
+	timestamps := seriesTimestamps(timeSeries)
+	starts := intervalStarts(timestamps, interval)
+	type bar struct {
+		start                         float64
+		open, high, low, close, total float64
+		first, last, trades           float64
+	}
+	bars := make([]bar, 0, groupBy.GetNumberOfGroups())
+	for _, rows := range groupBy.GetGroupIndexes() {
+		current := bar{start: starts[rows[0]], high: math.Inf(-1), low: math.Inf(1), first: math.Inf(1), last: math.Inf(-1)}
+		if math.IsNaN(current.start) {
+			continue
+		}
+		for _, row := range rows {
+			value := price.Float[row]
+			if math.IsNaN(value) {
+				continue
+			}
+			current.trades++
+			current.high = math.Max(current.high, value)
+			current.low = math.Min(current.low, value)
+			if timestamps[row] < current.first {
+				current.first, current.open = timestamps[row], value
+			}
+			if timestamps[row] >= current.last {
+				current.last, current.close = timestamps[row], value
+			}
+			if volume != nil && !math.IsNaN(volume.Float[row]) {
+				current.total += volume.Float[row]
+			}
+		}
+		if current.trades > 0 {
+			bars = append(bars, current)
+		}
+	}
+	sort.Slice(bars, func(i, j int) bool { return bars[i].start < bars[j].start })
+
+	columns := make([][]float64, 6)
+	startValues := make([]string, len(bars))
+	for i := range columns {
+		columns[i] = make([]float64, len(bars))
+	}
+	for i, current := range bars {
+		startValues[i] = time.Unix(0, int64(current.start*1e9)).UTC().Format("2006-01-02 15:04:05")
+		columns[0][i], columns[1][i], columns[2][i] = current.open, current.high, current.low
+		columns[3][i], columns[4][i], columns[5][i] = current.close, current.total, current.trades
+	}
+	result := DataFrame{Columns: []Series{
+		NewStringSeries("start", startValues),
+		NewFloatSeries("open", columns[0]),
+		NewFloatSeries("high", columns[1]),
+		NewFloatSeries("low", columns[2]),
+		NewFloatSeries("close", columns[3]),
+	}}
+	if volume != nil {
+		result.Columns = append(result.Columns, NewFloatSeries("volume", columns[4]))
+	}
+	result.Columns = append(result.Columns, NewFloatSeries("trades", columns[5]))
+	return result, nil
+}
@@ -0,0 +1,252 @@
+package grizzly
+
+import (
+	"fmt"
+	"io/fs"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// hiveNullPartition is the directory value Hive uses for null partitions
+const hiveNullPartition = "__HIVE_DEFAULT_PARTITION__"
+
+func escapePartition(value string) string {
+	return strings.ReplaceAll(url.PathEscape(value), "=", "%3D")
+}
+
+// nextPartFile returns the first part-NNNN file name not used in directory
+func nextPartFile(directory, extension string) string {
+	for part := 0; ; part++ {
+		path := filepath.Join(directory, fmt.Sprintf("part-%04d.%s", part, extension))
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+	}
+}
+
+// WritePartitioned writes df to directory in a Hive style layout, one
+// subdirectory per value of every partition column like
+// "year=2024/country=PE/part-0000.csv". The partition columns are stored in
+// the directory names instead of the files. format is a registered
+// extension. Files already in the directories are kept and the new ones get
+// the next part number, so a table can be written in several batches.
+func (df *DataFrame) WritePartitioned(directory, format string, partitionBy ...string) error {
+	format = normalizeExtension(format)
+	registered, _, err := getFormat("." + format)
+	if err != nil {
+		return err
+	}
+	if registered.writer == nil {
+		return fmt.Errorf("format %q can not be written", format)
+	}
+	columns := make([]*Series, len(partitionBy))
+	for i, name := range partitionBy {
+		if columns[i], err = df.GetColumnByName(name); err != nil {
+			return fmt.Errorf("failed to retrieve partition column %q: %w", name, err)
+		}
+	}
+
+	// Rows of every partition in order of first appearance
+	var paths []string
+	partitions := map[string][]int{}
+	segments := make([]string, len(columns))
+	for row := 0; row < df.GetLength(); row++ {
+		for i, series := range columns {
+			value := series.GetValueAsString(row)
+			if value == "NaN" {
+				value = hiveNullPartition
+			}
+			segments[i] = escapePartition(series.Name) + "=" + escapePartition(value)
+		}
+		path := filepath.Join(append([]string{directory}, segments...)...)
+		if _, ok := partitions[path]; !ok {
+			paths = append(paths, path)
+		}
+		partitions[path] = append(partitions[path], row)
+	}
+	if len(partitionBy) == 0 && df.GetLength() == 0 {
+		paths = append(paths, directory)
+	}
+
+	for _, path := range paths {
+		part, err := df.SelectRows(partitions[path])
+		if err != nil {
+			return err
+		}
+		for _, name := range partitionBy {
+			part.DropByName(name)
+		}
+		if err = os.MkdirAll(path, 0o755); err != nil {
+			return fmt.Errorf("failed to create partition directory: %w", err)
+		}
+		if err = part.Write(nextPartFile(path, format)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partitionFile is a data file of a partitioned directory with the values of
+// its partition columns
+type partitionFile struct {
+	path   string
+	values map[string]string
+}
+
+// ReadPartitioned reads a directory written by WritePartitioned, or any Hive
+// style layout of registered formats, adding the partition columns from the
+// directory names. Partitions where a filter that only references partition
+// columns is false are not read, and every filter is applied to the rows
+// read. Partition values are read as floats when every value of the column
+// is a plainly written number. Files starting with "." or "_" are ignored.
+func ReadPartitioned(directory string, filters ...Expr) (DataFrame, error) {
+	var files []partitionFile
+	var names []string
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") || strings.HasPrefix(entry.Name(), "_") {
+			if entry.IsDir() && path != directory {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if registered, _, err := getFormat(path); err != nil || registered.reader == nil {
+			return nil
+		}
+		relative, err := filepath.Rel(directory, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		file := partitionFile{path: path, values: map[string]string{}}
+		for _, segment := range strings.Split(filepath.ToSlash(relative), "/") {
+			name, value, ok := strings.Cut(segment, "=")
+			if !ok {
+				continue
+			}
+			if name, err = url.PathUnescape(name); err != nil {
+				return fmt.Errorf("invalid partition directory %q: %w", segment, err)
+			}
+			if value, err = url.PathUnescape(value); err != nil {
+				return fmt.Errorf("invalid partition directory %q: %w", segment, err)
+			}
+			if value == hiveNullPartition {
+				value = "NaN"
+			}
+			if !arrayContainsString(names, name) {
+				names = append(names, name)
+			}
+			file.values[name] = value
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	// A partition column is float when every value is a number written the
+	// way floats are formatted, so codes like "007" stay strings
+	numeric := map[string]bool{}
+	for _, name := range names {
+		numeric[name] = true
+		for _, file := range files {
+			if value, ok := file.values[name]; ok && value != "NaN" {
+				number, err := strconv.ParseFloat(value, 64)
+				if err != nil || strconv.FormatFloat(number, 'f', -1, 64) != value {
+					numeric[name] = false
+					break
+				}
+			}
+		}
+	}
+	partitionColumns := func(file partitionFile, length int) []Series {
+		columns := make([]Series, len(names))
+		for i, name := range names {
+			value, ok := file.values[name]
+			if !ok {
+				value = "NaN"
+			}
+			if numeric[name] {
+				number := math.NaN()
+				if value != "NaN" {
+					number, _ = strconv.ParseFloat(value, 64)
+				}
+				columns[i] = NewFloatSeries(name, arrayResizeFloat(nil, length, number))
+			} else {
+				columns[i] = NewStringSeries(name, arrayResizeString(nil, length, value))
+			}
+		}
+		return columns
+	}
+
+	// Prune with the filters that only reference partition columns
+	var pruning []Expr
+	for _, filter := range filters {
+		prunable := true
+		for _, name := range filter.columns() {
+			if !arrayContainsString(names, name) {
+				prunable = false
+			}
+		}
+		if prunable {
+			pruning = append(pruning, filter)
+		}
+	}
+	kept := files[:0]
+	for _, file := range files {
+		keep := true
+		values := DataFrame{Columns: partitionColumns(file, 1)}
+		for _, filter := range pruning {
+			result, err := values.Evaluate(filter)
+			if err != nil {
+				return DataFrame{}, fmt.Errorf("failed to prune partitions: %w", err)
+			}
+			mask, err := result.ToMask()
+			if err != nil {
+				return DataFrame{}, fmt.Errorf("failed to prune partitions: %w", err)
+			}
+			if !mask[0] {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			kept = append(kept, file)
+		}
+	}
+
+	paths := make([]string, len(kept))
+	byPath := make(map[string]partitionFile, len(kept))
+	for i, file := range kept {
+		paths[i] = file.path
+		byPath[file.path] = file
+	}
+	frames, err := readFiles(paths, func(path string) (DataFrame, error) {
+		frame, err := Read(path)
+		if err != nil {
+			return DataFrame{}, err
+		}
+		frame.Columns = append(frame.Columns, partitionColumns(byPath[path], frame.GetLength())...)
+		return frame, nil
+	})
+	if err != nil {
+		return DataFrame{}, err
+	}
+
+	result := unionFrames(frames)
+	for _, filter := range filters {
+		if err = result.Filter(filter); err != nil {
+			return DataFrame{}, err
+		}
+	}
+	return result, nil
+}
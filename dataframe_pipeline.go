@@ -0,0 +1,369 @@
+package grizzly
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// PipelineStep learns its parameters in Fit and applies them in Transform.
+// Kind identifies the step when a fitted pipeline is serialized.
+type PipelineStep interface {
+	Kind() string
+	Fit(df *DataFrame) error
+	Transform(df *DataFrame) error
+}
+
+// Pipeline runs named steps in order, every step is fitted over the output
+// of the previous ones
+type Pipeline struct {
+	names []string
+	steps []PipelineStep
+}
+
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+func (pipeline *Pipeline) AddStep(name string, step PipelineStep) error {
+	if arrayContainsString(pipeline.names, name) {
+		return fmt.Errorf("pipeline already has a step named %q", name)
+	}
+	pipeline.names = append(pipeline.names, name)
+	pipeline.steps = append(pipeline.steps, step)
+	return nil
+}
+
+func (pipeline *Pipeline) GetStepNames() []string {
+	return append([]string{}, pipeline.names...)
+}
+
+// Fit learns the parameters of every step without modifying df
+func (pipeline *Pipeline) Fit(df DataFrame) error {
+	_, err := pipeline.FitTransform(df)
+	return err
+}
+
+func (pipeline *Pipeline) FitTransform(df DataFrame) (DataFrame, error) {
+	work := df.Copy()
+	for i, step := range pipeline.steps {
+		if err := step.Fit(&work); err != nil {
+			return DataFrame{}, fmt.Errorf("failed to fit step %q: %w", pipeline.names[i], err)
+		}
+		if err := step.Transform(&work); err != nil {
+			return DataFrame{}, fmt.Errorf("failed to transform step %q: %w", pipeline.names[i], err)
+		}
+	}
+	return work, nil
+}
+
+// Transform applies the fitted steps to a copy of df
+func (pipeline *Pipeline) Transform(df DataFrame) (DataFrame, error) {
+	work := df.Copy()
+	for i, step := range pipeline.steps {
+		if err := step.Transform(&work); err != nil {
+			return DataFrame{}, fmt.Errorf("failed to transform step %q: %w", pipeline.names[i], err)
+		}
+	}
+	return work, nil
+}
+
+type pipelineStepJSON struct {
+	Name   string          `json:"name"`
+	Kind   string          `json:"kind"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// ToJSON serializes the fitted parameters. Custom function steps only keep
+// their name and must be supplied again when loading.
+func (pipeline *Pipeline) ToJSON() ([]byte, error) {
+	encoded := make([]pipelineStepJSON, len(pipeline.steps))
+	for i, step := range pipeline.steps {
+		encoded[i] = pipelineStepJSON{Name: pipeline.names[i], Kind: step.Kind()}
+		if _, custom := step.(*FuncStep); custom {
+			continue
+		}
+		params, err := json.Marshal(step)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize step %q: %w", pipeline.names[i], err)
+		}
+		encoded[i].Params = params
+	}
+	return json.MarshalIndent(encoded, "", "  ")
+}
+
+// LoadPipelineJSON rebuilds a fitted pipeline. custom maps the names of the
+// custom function steps to their implementation.
+func LoadPipelineJSON(data []byte, custom map[string]PipelineStep) (*Pipeline, error) {
+	var encoded []pipelineStepJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("failed to read pipeline: %w", err)
+	}
+	pipeline := NewPipeline()
+	for _, item := range encoded {
+		var step PipelineStep
+		switch item.Kind {
+		case "impute":
+			step = &ImputeStep{}
+		case "scale":
+			step = &ScaleStep{}
+		case "encode":
+			step = &EncodeStep{}
+		case "func":
+			provided, ok := custom[item.Name]
+			if !ok {
+				return nil, fmt.Errorf("custom step %q was not provided", item.Name)
+			}
+			step = provided
+		default:
+			return nil, fmt.Errorf("unknown step kind %q", item.Kind)
+		}
+		if item.Kind != "func" {
+			if err := json.Unmarshal(item.Params, step); err != nil {
+				return nil, fmt.Errorf("failed to read step %q: %w", item.Name, err)
+			}
+		}
+		if err := pipeline.AddStep(item.Name, step); err != nil {
+			return nil, err
+		}
+	}
+	return pipeline, nil
+}
+
+// ImputeStep fills NaN values of float columns. Strategy is "mean",
+// "median" or "constant" (using Value).
+type ImputeStep struct {
+	Columns  []string           `json:"columns"`
+	Strategy string             `json:"strategy"`
+	Value    float64            `json:"value"`
+	Fitted   map[string]float64 `json:"fitted"`
+}
+
+func (step *ImputeStep) Kind() string {
+	return "impute"
+}
+
+func (step *ImputeStep) Fit(df *DataFrame) error {
+	step.Fitted = make(map[string]float64, len(step.Columns))
+	for _, name := range step.Columns {
+		series, err := df.GetColumnByName(name)
+		if err != nil {
+			return err
+		}
+		if series.DataType != "float" {
+			return fmt.Errorf("column %q is not of type float", name)
+		}
+		clean := NewFloatSeries(name, append([]float64{}, series.Float...))
+		clean.DropNaN()
+		switch step.Strategy {
+		case "constant":
+			step.Fitted[name] = step.Value
+		case "mean", "":
+			step.Fitted[name], err = clean.GetMean()
+		case "median":
+			step.Fitted[name], err = clean.GetMedian()
+		default:
+			return fmt.Errorf("unsupported impute strategy %q", step.Strategy)
+		}
+		if err != nil {
+			return fmt.Errorf("column %q has no values to impute from: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (step *ImputeStep) Transform(df *DataFrame) error {
+	for _, name := range step.Columns {
+		value, ok := step.Fitted[name]
+		if !ok {
+			return fmt.Errorf("column %q was not fitted", name)
+		}
+		if err := df.FillNaN(value, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScaleStep scales float columns with Method "standard" (zero mean and unit
+// variance) or "minmax" (range [0, 1]). Fitted keeps the offset and scale.
+type ScaleStep struct {
+	Columns []string              `json:"columns"`
+	Method  string                `json:"method"`
+	Fitted  map[string][2]float64 `json:"fitted"`
+}
+
+func (step *ScaleStep) Kind() string {
+	return "scale"
+}
+
+func (step *ScaleStep) Fit(df *DataFrame) error {
+	step.Fitted = make(map[string][2]float64, len(step.Columns))
+	for _, name := range step.Columns {
+		series, err := df.GetColumnByName(name)
+		if err != nil {
+			return err
+		}
+		if series.DataType != "float" {
+			return fmt.Errorf("column %q must be a float column", name)
+		}
+		values, _ := nonNullFloats(series)
+		if len(values) == 0 {
+			return fmt.Errorf("column %q has no values to scale from", name)
+		}
+		var offset, scale float64
+		switch step.Method {
+		case "standard", "":
+			offset = arrayMean(values)
+			scale = math.Sqrt(arrayVariance(values, offset))
+		case "minmax":
+			offset = arrayMin(values)
+			scale = arrayMax(values) - offset
+		default:
+			return fmt.Errorf("unsupported scale method %q", step.Method)
+		}
+		if scale == 0 {
+			scale = 1
+		}
+		step.Fitted[name] = [2]float64{offset, scale}
+	}
+	return nil
+}
+
+func (step *ScaleStep) Transform(df *DataFrame) error {
+	for _, name := range step.Columns {
+		params, ok := step.Fitted[name]
+		if !ok {
+			return fmt.Errorf("column %q was not fitted", name)
+		}
+		err := df.ApplyFloat(name, func(value float64) float64 {
+			return (value - params[0]) / params[1]
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodeStep encodes string columns with Method "label" (the position of the
+// category, -1 when unseen) or "onehot" (one float column per category named
+// column_category, the source column is dropped).
+type EncodeStep struct {
+	Columns []string            `json:"columns"`
+	Method  string              `json:"method"`
+	Fitted  map[string][]string `json:"fitted"`
+}
+
+func (step *EncodeStep) Kind() string {
+	return "encode"
+}
+
+func (step *EncodeStep) Fit(df *DataFrame) error {
+	step.Fitted = make(map[string][]string, len(step.Columns))
+	if step.Method != "label" && step.Method != "onehot" && step.Method != "" {
+		return fmt.Errorf("unsupported encode method %q", step.Method)
+	}
+	for _, name := range step.Columns {
+		series, err := df.GetColumnByName(name)
+		if err != nil {
+			return err
+		}
+		if series.DataType != "string" {
+			return fmt.Errorf("column %q is not of type string", name)
+		}
+		categories := arrayUniqueValuesString(series.strings())
+		categories = ParallelSortString(categories)
+		step.Fitted[name] = categories
+	}
+	return nil
+}
+
+func (step *EncodeStep) Transform(df *DataFrame) error {
+	for _, name := range step.Columns {
+		categories, ok := step.Fitted[name]
+		if !ok {
+			return fmt.Errorf("column %q was not fitted", name)
+		}
+		series, err := df.GetColumnByName(name)
+		if err != nil {
+			return err
+		}
+		if series.DataType != "string" {
+			return fmt.Errorf("column %q is not of type string", name)
+		}
+		position := make(map[string]int, len(categories))
+		for i, category := range categories {
+			position[category] = i
+		}
+		texts := series.strings()
+
+		if step.Method == "onehot" {
+			for _, category := range categories {
+				values := make([]float64, series.GetLength())
+				for row, value := range texts {
+					if value == category {
+						values[row] = 1
+					}
+				}
+				if err = df.CreateFloatColumn(name+"_"+category, values); err != nil {
+					return err
+				}
+			}
+			df.DropByName(name)
+			continue
+		}
+
+		labels := make([]float64, series.GetLength())
+		for row, value := range texts {
+			if index, found := position[value]; found {
+				labels[row] = float64(index)
+			} else {
+				labels[row] = -1
+			}
+		}
+		series.invalidateIndexes()
+		series.Float = labels
+		series.String = nil
+		series.DataType = "float"
+	}
+	return nil
+}
+
+// FuncStep wraps custom code. FitFunc is optional, the step can not be
+// serialized so it must be supplied again to LoadPipelineJSON.
+type FuncStep struct {
+	FitFunc       func(df *DataFrame) error
+	TransformFunc func(df *DataFrame) error
+}
+
+func (step *FuncStep) Kind() string {
+	return "func"
+}
+
+func (step *FuncStep) Fit(df *DataFrame) error {
+	if step.FitFunc == nil {
+		return nil
+	}
+	return step.FitFunc(df)
+}
+
+func (step *FuncStep) Transform(df *DataFrame) error {
+	if step.TransformFunc == nil {
+		return fmt.Errorf("custom step has no transform function")
+	}
+	return step.TransformFunc(df)
+}
+
+// String describes the steps of the pipeline
+func (pipeline *Pipeline) String() string {
+	description := "Pipeline("
+	for i, name := range pipeline.names {
+		if i > 0 {
+			description += ", "
+		}
+		description += strconv.Quote(name) + ":" + pipeline.steps[i].Kind()
+	}
+	return description + ")"
+}
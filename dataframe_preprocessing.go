@@ -6,8 +6,6 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
-	"runtime"
-	"sort"
 	"sync"
 	"time"
 )
@@ -16,8 +14,8 @@ import (
 #Data Cleaning#
 #############*/
 
-func (df *DataFrame) FillNaN(newValue float64, identifiers ...any) error {
-	var err error
+func (df *DataFrame) FillNaN(newValue float64, identifiers ...any) (err error) {
+	defer df.track("FillNaN", newValue, identifiers)(&err)
 	if len(identifiers) != 0 {
 		var series *Series
 		for _, identifier := range identifiers {
@@ -35,8 +33,8 @@ func (df *DataFrame) FillNaN(newValue float64, identifiers ...any) error {
 	return nil
 }
 
-func (df *DataFrame) DropNaN(identifiers ...any) error {
-	var err error
+func (df *DataFrame) DropNaN(identifiers ...any) (err error) {
+	defer df.track("DropNaN", identifiers)(&err)
 	if len(identifiers) != 0 {
 		var series *Series
 		for _, identifier := range identifiers {
@@ -54,10 +52,10 @@ func (df *DataFrame) DropNaN(identifiers ...any) error {
 	return nil
 }
 
-func (df *DataFrame) RemoveOutliersZScore(identifier any, threshold float64) error {
+func (df *DataFrame) RemoveOutliersZScore(identifier any, threshold float64) (err error) {
+	defer df.track("RemoveOutliersZScore", identifier, threshold)(&err)
 	var series *Series
 	var zScoreSeries Series
-	var err error
 	series, err = df.GetColumnDynamic(identifier)
 	if err != nil {
 		return err
@@ -96,8 +94,8 @@ func (df *DataFrame) RemoveOutliersZScore(identifier any, threshold float64) err
 	return nil
 }
 
-func (df *DataFrame) RemoveOutliersIQR(identifier any) error {
-	var err error
+func (df *DataFrame) RemoveOutliersIQR(identifier any) (err error) {
+	defer df.track("RemoveOutliersIQR", identifier)(&err)
 	var series *Series
 
 	series, err = df.GetColumnDynamic(identifier)
@@ -111,7 +109,7 @@ func (df *DataFrame) RemoveOutliersIQR(identifier any) error {
 	if err != nil {
 		return err
 	}
-
+	series, _ = df.GetColumnDynamic(identifier)
 	q1 := arrayCalculatePercentile(series.Float, 25)
 	q3 := arrayCalculatePercentile(series.Float, 75)
 	iqr := q3 - q1
@@ -133,6 +131,8 @@ func (df *DataFrame) RemoveOutliersIQR(identifier any) error {
 }
 
 func (df *DataFrame) RemoveDuplicates() {
+	defer df.track("RemoveDuplicates")(nil)
+	df.invalidateIndexes()
 	if len(df.Columns) == 0 {
 		return // No data
 	}
@@ -143,27 +143,30 @@ func (df *DataFrame) RemoveDuplicates() {
 		rowCount = len(df.Columns[0].String)
 	}
 
-	numGoroutines := runtime.NumCPU() // Number of concurrent workers
+	numGoroutines := workerCount() // Number of concurrent workers
 	chunkSize := (rowCount + numGoroutines - 1) / numGoroutines
 
-	globalSeen := sync.Map{}  // Concurrent map to track unique rows
-	uniqueIndices := []int{}  // Indices of unique rows
-	indicesMu := sync.Mutex{} // Mutex to protect uniqueIndices
-	var wg sync.WaitGroup     // WaitGroup to manage goroutines
+	// Every chunk keeps its first occurrences, then the chunks are merged in
+	// order so the first occurrence of every row is kept
+	chunkKeys := make([][]string, numGoroutines)
+	chunkIndices := make([][]int, numGoroutines)
+	var wg sync.WaitGroup // WaitGroup to manage goroutines
 
 	for i := 0; i < numGoroutines; i++ {
 		start := i * chunkSize
 		end := start + chunkSize
+		if start >= rowCount {
+			break
+		}
 		if end > rowCount {
 			end = rowCount
 		}
 
 		wg.Add(1)
-		go func(start, end int) {
+		go func(chunk, start, end int) {
 			defer wg.Done()
 
-			localUniqueIndices := []int{}
-
+			localSeen := map[string]bool{}
 			for idx := start; idx < end; idx++ {
 				row := make([]interface{}, len(df.Columns))
 
@@ -179,27 +182,31 @@ func (df *DataFrame) RemoveDuplicates() {
 				// Serialize the row to JSON for unique identification
 				rowKey, err := json.Marshal(row)
 				if err != nil {
-					fmt.Println("Error serializing row:", err)
+					getLogger().Warn("failed to serialize row", "row", idx, "error", err)
 					continue
 				}
 
-				// Check if the row is globally unique
-				if _, loaded := globalSeen.LoadOrStore(string(rowKey), true); !loaded {
-					localUniqueIndices = append(localUniqueIndices, idx)
+				if key := string(rowKey); !localSeen[key] {
+					localSeen[key] = true
+					chunkKeys[chunk] = append(chunkKeys[chunk], key)
+					chunkIndices[chunk] = append(chunkIndices[chunk], idx)
 				}
 			}
-
-			// Merge local unique indices into global uniqueIndices
-			indicesMu.Lock()
-			uniqueIndices = append(uniqueIndices, localUniqueIndices...)
-			indicesMu.Unlock()
-		}(start, end)
+		}(i, start, end)
 	}
 
 	wg.Wait() // Wait for all goroutines to complete
 
-	// Sort uniqueIndices to maintain row order (optional but recommended)
-	sort.Ints(uniqueIndices)
+	globalSeen := map[string]bool{}
+	uniqueIndices := []int{} // Indices of unique rows, in row order
+	for chunk, keys := range chunkKeys {
+		for k, key := range keys {
+			if !globalSeen[key] {
+				globalSeen[key] = true
+				uniqueIndices = append(uniqueIndices, chunkIndices[chunk][k])
+			}
+		}
+	}
 
 	// Create new slices for unique data
 	for j := range df.Columns {
@@ -223,10 +230,10 @@ func (df *DataFrame) RemoveDuplicates() {
 #Feature Scaling#
 ###############*/
 
-func (df *DataFrame) Normalize(identifiers ...any) error {
+func (df *DataFrame) Normalize(identifiers ...any) (err error) {
+	defer df.track("Normalize", identifiers)(&err)
 	var minV, maxV float64
 	var series *Series
-	var err error
 
 	for _, identifier := range identifiers {
 		// Retrieve the series (column) by identifier
@@ -234,6 +241,7 @@ func (df *DataFrame) Normalize(identifiers ...any) error {
 		if err != nil {
 			return fmt.Errorf("error retrieving column '%v': %w", identifier, err)
 		}
+		series.invalidateIndexes()
 
 		// Ensure the column is numeric
 		if series.DataType == "string" {
@@ -263,16 +271,17 @@ func (df *DataFrame) Normalize(identifiers ...any) error {
 	return nil // Successfully normalized
 }
 
-func (df *DataFrame) Standardize(identifiers ...any) error {
+func (df *DataFrame) Standardize(identifiers ...any) (err error) {
+	defer df.track("Standardize", identifiers)(&err)
 	var mean, stdDev float64
 	var series *Series
-	var err error
 
 	for _, identifier := range identifiers {
 		series, err = df.GetColumnDynamic(identifier)
 		if err != nil {
 			return fmt.Errorf("error retrieving column '%v': %w", identifier, err)
 		}
+		series.invalidateIndexes()
 		// Ensure the column is numeric
 		if series.DataType == "string" {
 			return fmt.Errorf("column '%v' is a string column. Please select a float column", identifier)
@@ -303,9 +312,9 @@ func (df *DataFrame) Standardize(identifiers ...any) error {
 #Encoding Categorical Variables#
 ################################
 */
-func (df *DataFrame) OneHotEncode(identifiers ...any) error {
+func (df *DataFrame) OneHotEncode(identifiers ...any) (err error) {
+	defer df.track("OneHotEncode", identifiers)(&err)
 	var series *Series
-	var err error
 	var categories []string
 	var lastIndex int
 	categoryIndex := make(map[string]int)
@@ -315,7 +324,7 @@ func (df *DataFrame) OneHotEncode(identifiers ...any) error {
 	}
 
 	numElements := df.GetLength()
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	chunkSize := (numElements + numGoroutines - 1) / numGoroutines
 
 	for _, identifier := range identifiers {
@@ -330,7 +339,8 @@ func (df *DataFrame) OneHotEncode(identifiers ...any) error {
 		lastIndex = df.GetNumberOfColumns() - 1
 
 		// Get unique categories from the column
-		categories = arrayUniqueValuesString(series.String)
+		values := series.strings()
+		categories = arrayUniqueValuesString(values)
 
 		// Create new float columns for each category with independent slices
 		for _, category := range categories {
@@ -358,7 +368,7 @@ func (df *DataFrame) OneHotEncode(identifiers ...any) error {
 			go func(start, end int) {
 				defer wg.Done()
 				for i := start; i < end; i++ {
-					category := series.String[i]           // Get the category for this row
+					category := values[i]                  // Get the category for this row
 					columnIndex := categoryIndex[category] // Find the column index for the category
 					df.Columns[columnIndex].Float[i] = 1.0 // Set the value to 1.0
 				}
@@ -371,9 +381,10 @@ func (df *DataFrame) OneHotEncode(identifiers ...any) error {
 	return nil
 }
 
-func (df *DataFrame) LabelEncode(identifiers ...any) error {
+func (df *DataFrame) LabelEncode(identifiers ...any) (err error) {
+	defer df.track("LabelEncode", identifiers)(&err)
 	var internalIndex int
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	length := df.GetLength()
 	if length == 0 {
 		return fmt.Errorf("dataframe is empty")
@@ -385,13 +396,14 @@ func (df *DataFrame) LabelEncode(identifiers ...any) error {
 		if err != nil {
 			return fmt.Errorf("error retrieving column '%v': %w", identifier, err)
 		}
+		series.invalidateIndexes()
 
 		var equivalentMap map[interface{}]float64
 		var nanLabel float64 = -1 // Special label for NaN values
 
 		if series.DataType == "string" {
 			uniqueValues := arrayUniqueValuesString(series.String)
-			uniqueValues = parallelSortString(uniqueValues) // Sort in-place
+			uniqueValues = ParallelSortString(uniqueValues) // Sort in-place
 			equivalentMap = make(map[interface{}]float64, len(uniqueValues))
 			for index, value := range uniqueValues {
 				equivalentMap[value] = float64(index)
@@ -507,9 +519,9 @@ func PearsonCorrelation(x, y []float64) (float64, error) {
 
 }
 
-func (df *DataFrame) SelectByCorrelation(targetIdentifier any, threshold float64) error {
+func (df *DataFrame) SelectByCorrelation(targetIdentifier any, threshold float64) (err error) {
+	defer df.track("SelectByCorrelation", targetIdentifier, threshold)(&err)
 	var targetSeries *Series
-	var err error
 	var selectedColumns []string
 	var correlation float64
 
@@ -548,7 +560,8 @@ func (df *DataFrame) SelectByCorrelation(targetIdentifier any, threshold float64
 	return nil
 }
 
-func (df *DataFrame) VarianceThreshold(threshold float64) error {
+func (df *DataFrame) VarianceThreshold(threshold float64) (err error) {
+	defer df.track("VarianceThreshold", threshold)(&err)
 	if df.GetLength() == 0 {
 		return fmt.Errorf("dataframe is empty")
 	}
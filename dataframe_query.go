@@ -0,0 +1,252 @@
+package grizzly
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseExpr reads an expression written as text, like
+//
+//	price * quantity > 100 and (region == "eu" or not isnan(discount))
+//
+// Columns are bare names or quoted with backticks when they have spaces,
+// strings use double or single quotes. The operators are or, and, not, the
+// comparisons == (or =), !=, >, >=, <, <= and contains, then + - * / and
+// the functions isnan, abs and timestamp, from the lowest precedence to the
+// highest.
+func ParseExpr(text string) (Expr, error) {
+	tokens, err := tokenizeExpr(text)
+	if err != nil {
+		return Expr{}, fmt.Errorf("failed to parse %q: %w", text, err)
+	}
+	parser := &exprParser{tokens: tokens}
+	expr, err := parser.or()
+	if err == nil && parser.position < len(tokens) {
+		err = fmt.Errorf("unexpected %q", tokens[parser.position].text)
+	}
+	if err != nil {
+		return Expr{}, fmt.Errorf("failed to parse %q: %w", text, err)
+	}
+	return expr, nil
+}
+
+type exprToken struct {
+	kind string // "name", "column", "number", "string" or "symbol"
+	text string
+}
+
+var exprSymbols = []string{"==", "!=", ">=", "<=", ">", "<", "=", "+", "-", "*", "/", "(", ")"}
+
+func tokenizeExpr(text string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: "name", text: string(runes[start:i])})
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				(runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E')) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: "number", text: string(runes[start:i])})
+		case r == '"' || r == '\'' || r == '`':
+			var value strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && r != '`' {
+					i++
+				}
+				value.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated %c", r)
+			}
+			i++
+			kind := "string"
+			if r == '`' {
+				kind = "column"
+			}
+			tokens = append(tokens, exprToken{kind: kind, text: value.String()})
+		default:
+			matched := false
+			for _, symbol := range exprSymbols {
+				if strings.HasPrefix(string(runes[i:min(i+2, len(runes))]), symbol) {
+					tokens = append(tokens, exprToken{kind: "symbol", text: symbol})
+					i += len(symbol)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+type exprParser struct {
+	tokens   []exprToken
+	position int
+}
+
+// accept consumes the next token when it is one of the symbols or keywords
+func (parser *exprParser) accept(options ...string) (string, bool) {
+	if parser.position >= len(parser.tokens) {
+		return "", false
+	}
+	token := parser.tokens[parser.position]
+	if token.kind != "symbol" && token.kind != "name" {
+		return "", false
+	}
+	for _, option := range options {
+		if token.kind == "symbol" && token.text == option || token.kind == "name" && strings.EqualFold(token.text, option) {
+			parser.position++
+			return option, true
+		}
+	}
+	return "", false
+}
+
+func (parser *exprParser) or() (Expr, error) {
+	left, err := parser.and()
+	for err == nil {
+		if _, ok := parser.accept("or"); !ok {
+			break
+		}
+		var right Expr
+		right, err = parser.and()
+		left = left.Or(right)
+	}
+	return left, err
+}
+
+func (parser *exprParser) and() (Expr, error) {
+	left, err := parser.not()
+	for err == nil {
+		if _, ok := parser.accept("and"); !ok {
+			break
+		}
+		var right Expr
+		right, err = parser.not()
+		left = left.And(right)
+	}
+	return left, err
+}
+
+func (parser *exprParser) not() (Expr, error) {
+	if _, ok := parser.accept("not"); ok {
+		operand, err := parser.not()
+		return operand.Not(), err
+	}
+	return parser.comparison()
+}
+
+func (parser *exprParser) comparison() (Expr, error) {
+	left, err := parser.additive()
+	if err != nil {
+		return Expr{}, err
+	}
+	op, ok := parser.accept("==", "!=", ">=", "<=", ">", "<", "=", "contains")
+	if !ok {
+		return left, nil
+	}
+	right, err := parser.additive()
+	if op == "=" {
+		op = "=="
+	}
+	return left.binary(op, right), err
+}
+
+func (parser *exprParser) additive() (Expr, error) {
+	left, err := parser.term()
+	for err == nil {
+		op, ok := parser.accept("+", "-")
+		if !ok {
+			break
+		}
+		var right Expr
+		right, err = parser.term()
+		left = left.binary(op, right)
+	}
+	return left, err
+}
+
+func (parser *exprParser) term() (Expr, error) {
+	left, err := parser.unary()
+	for err == nil {
+		op, ok := parser.accept("*", "/")
+		if !ok {
+			break
+		}
+		var right Expr
+		right, err = parser.unary()
+		left = left.binary(op, right)
+	}
+	return left, err
+}
+
+func (parser *exprParser) unary() (Expr, error) {
+	if _, ok := parser.accept("-"); ok {
+		operand, err := parser.unary()
+		return Lit(0).Sub(operand), err
+	}
+	return parser.primary()
+}
+
+func (parser *exprParser) primary() (Expr, error) {
+	if parser.position >= len(parser.tokens) {
+		return Expr{}, fmt.Errorf("unexpected end of expression")
+	}
+	if _, ok := parser.accept("("); ok {
+		expr, err := parser.or()
+		if err != nil {
+			return Expr{}, err
+		}
+		if _, ok = parser.accept(")"); !ok {
+			return Expr{}, fmt.Errorf("missing )")
+		}
+		return expr, nil
+	}
+	token := parser.tokens[parser.position]
+	parser.position++
+	switch token.kind {
+	case "number":
+		value, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return Expr{}, fmt.Errorf("invalid number %q", token.text)
+		}
+		return Lit(value), nil
+	case "string":
+		return Lit(token.text), nil
+	case "column":
+		return Col(token.text), nil
+	case "name":
+		function := strings.ToLower(token.text)
+		if function == "isnan" || function == "abs" || function == "timestamp" {
+			if _, ok := parser.accept("("); ok {
+				operand, err := parser.or()
+				if err != nil {
+					return Expr{}, err
+				}
+				if _, ok = parser.accept(")"); !ok {
+					return Expr{}, fmt.Errorf("missing ) after %s", token.text)
+				}
+				return operand.unary(function), nil
+			}
+		}
+		return Col(token.text), nil
+	}
+	return Expr{}, fmt.Errorf("unexpected %q", token.text)
+}
@@ -0,0 +1,179 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// WideToLong parses column families like sales_2021, sales_2022 into long
+// form. Every row is repeated once per suffix, the suffix goes to column j and
+// the values to one column per stub. Columns outside the families are kept.
+func (df *DataFrame) WideToLong(stubnames []string, i string, j string, sep string) (DataFrame, error) {
+	if len(stubnames) == 0 {
+		return DataFrame{}, fmt.Errorf("at least one stubname is required")
+	}
+	if !df.ContainsColumn(i) {
+		return DataFrame{}, fmt.Errorf("failed to reshape to long: column %q not found", i)
+	}
+	if df.ContainsColumn(j) {
+		return DataFrame{}, fmt.Errorf("failed to reshape to long: column %q already exists", j)
+	}
+
+	// Find the suffixes of every stub in column order
+	var suffixes []string
+	families := make(map[string]map[string]*Series, len(stubnames))
+	var kept []*Series
+	for c := range df.Columns {
+		column := &df.Columns[c]
+		matched := false
+		for _, stub := range stubnames {
+			prefix := stub + sep
+			if !strings.HasPrefix(column.Name, prefix) || len(column.Name) == len(prefix) {
+				continue
+			}
+			suffix := column.Name[len(prefix):]
+			if families[stub] == nil {
+				families[stub] = make(map[string]*Series)
+			}
+			families[stub][suffix] = column
+			if !arrayContainsString(suffixes, suffix) {
+				suffixes = append(suffixes, suffix)
+			}
+			matched = true
+			break
+		}
+		if !matched {
+			kept = append(kept, column)
+		}
+	}
+	if len(suffixes) == 0 {
+		return DataFrame{}, fmt.Errorf("no columns match the stubnames %v", stubnames)
+	}
+
+	length := df.GetLength()
+	total := length * len(suffixes)
+	var result DataFrame
+
+	for _, column := range kept {
+		rows := make([]int, total)
+		for r := 0; r < length; r++ {
+			for s := range suffixes {
+				rows[r*len(suffixes)+s] = r
+			}
+		}
+		result.Columns = append(result.Columns, seriesTake(*column, rows))
+	}
+
+	suffixValues := make([]string, total)
+	for r := 0; r < length; r++ {
+		copy(suffixValues[r*len(suffixes):], suffixes)
+	}
+	suffixSeries := NewStringSeries(j, suffixValues)
+	if len(arrayGetNonFloatValues(suffixes)) == 0 {
+		suffixSeries.ConvertStringToFloat()
+	}
+	result.Columns = append(result.Columns, suffixSeries)
+
+	for _, stub := range stubnames {
+		family := families[stub]
+		isFloat := true
+		for _, column := range family {
+			if column.DataType != "float" {
+				isFloat = false
+			}
+		}
+		if isFloat {
+			values := make([]float64, total)
+			for r := 0; r < length; r++ {
+				for s, suffix := range suffixes {
+					if column, ok := family[suffix]; ok {
+						values[r*len(suffixes)+s] = column.Float[r]
+					} else {
+						values[r*len(suffixes)+s] = math.NaN()
+					}
+				}
+			}
+			result.Columns = append(result.Columns, NewFloatSeries(stub, values))
+			continue
+		}
+		values := make([]string, total)
+		for r := 0; r < length; r++ {
+			for s, suffix := range suffixes {
+				if column, ok := family[suffix]; ok {
+					values[r*len(suffixes)+s] = column.GetValueAsString(r)
+				} else {
+					values[r*len(suffixes)+s] = "NaN"
+				}
+			}
+		}
+		result.Columns = append(result.Columns, NewStringSeries(stub, values))
+	}
+	return result, nil
+}
+
+// LongToWide is the reverse of WideToLong, one row is created per value of i
+// and one column per value and suffix, named value + sep + suffix.
+func (df *DataFrame) LongToWide(i string, j string, values []string, sep string) (DataFrame, error) {
+	idSeries, err := df.GetColumnByName(i)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to reshape to wide: %w", err)
+	}
+	suffixSeries, err := df.GetColumnByName(j)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to reshape to wide: %w", err)
+	}
+	valueSeries := make([]*Series, len(values))
+	for v, name := range values {
+		valueSeries[v], err = df.GetColumnByName(name)
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to reshape to wide: %w", err)
+		}
+	}
+
+	// Ids and suffixes keep their order of appearance
+	var ids []int
+	idPosition := make(map[string]int)
+	var suffixes []string
+	suffixPosition := make(map[string]int)
+	for r := 0; r < df.GetLength(); r++ {
+		id := idSeries.GetValueAsString(r)
+		if _, ok := idPosition[id]; !ok {
+			idPosition[id] = len(ids)
+			ids = append(ids, r)
+		}
+		suffix := suffixSeries.GetValueAsString(r)
+		if _, ok := suffixPosition[suffix]; !ok {
+			suffixPosition[suffix] = len(suffixes)
+			suffixes = append(suffixes, suffix)
+		}
+	}
+
+	result := DataFrame{Columns: []Series{seriesTake(*idSeries, ids)}}
+	for v, name := range values {
+		source := valueSeries[v]
+		for _, suffix := range suffixes {
+			var column Series
+			if source.DataType == "float" {
+				column = NewFloatSeries(name+sep+suffix, arrayResizeFloat(nil, len(ids), math.NaN()))
+			} else {
+				column = NewStringSeries(name+sep+suffix, arrayResizeString(nil, len(ids), "NaN"))
+			}
+			result.Columns = append(result.Columns, column)
+		}
+	}
+
+	for r := 0; r < df.GetLength(); r++ {
+		row := idPosition[idSeries.GetValueAsString(r)]
+		s := suffixPosition[suffixSeries.GetValueAsString(r)]
+		for v := range values {
+			target := &result.Columns[1+v*len(suffixes)+s]
+			if target.DataType == "float" {
+				target.Float[row] = valueSeries[v].Float[r]
+			} else {
+				target.String[row] = valueSeries[v].GetValueString(r)
+			}
+		}
+	}
+	return result, nil
+}
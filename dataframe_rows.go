@@ -0,0 +1,341 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// InsertAt inserts value before row index, an index equal to the length
+// appends it. Float series take numbers, string series take strings or
+// numbers, and nil inserts a null.
+func (series *Series) InsertAt(index int, value any) error {
+	if index < 0 || index > series.GetLength() {
+		return fmt.Errorf("insert position %d out of range for length %d", index, series.GetLength())
+	}
+	if err := series.Decompress(); err != nil {
+		return err
+	}
+	if series.DataType == "float" {
+		number := math.NaN()
+		if value != nil {
+			converted, err := interfaceConvertToFloat(value)
+			if err != nil {
+				return fmt.Errorf("cannot insert %v into float series %q: %w", value, series.Name, err)
+			}
+			number = converted
+		}
+		series.invalidateIndexes()
+		series.Float = slices.Insert(series.Float, index, number)
+		return nil
+	}
+	text := "NaN"
+	if value != nil {
+		converted, err := interfaceConvertToString(value)
+		if err != nil {
+			return fmt.Errorf("cannot insert %v into string series %q: %w", value, series.Name, err)
+		}
+		text = converted
+	}
+	series.invalidateIndexes()
+	series.String = slices.Insert(series.String, index, text)
+	return nil
+}
+
+// deleteRows removes sorted unique rows, moving the blocks between them
+// down in place
+func (series *Series) deleteRows(rows []int) {
+	series.invalidateIndexes()
+	if series.DataType == "float" {
+		series.Float = deleteBlocks(series.Float, rows)
+	} else {
+		series.String = deleteBlocks(series.String, rows)
+	}
+}
+
+func deleteBlocks[T any](values []T, rows []int) []T {
+	if len(rows) == 0 {
+		return values
+	}
+	write := rows[0]
+	for i, row := range rows {
+		end := len(values)
+		if i+1 < len(rows) {
+			end = rows[i+1]
+		}
+		write += copy(values[write:], values[row+1:end])
+	}
+	clear(values[write:])
+	return values[:write]
+}
+
+// DeleteRows removes the rows at the given positions from every column.
+// Repeated positions are removed once and the remaining rows keep their
+// order.
+func (df *DataFrame) DeleteRows(indexes []int) (err error) {
+	defer df.track("DeleteRows", indexes)(&err)
+	if err = df.checkLengths(); err != nil {
+		return err
+	}
+	length := df.GetLength()
+	rows := append([]int(nil), indexes...)
+	sort.Ints(rows)
+	rows = slices.Compact(rows)
+	if len(rows) > 0 && (rows[0] < 0 || rows[len(rows)-1] >= length) {
+		return fmt.Errorf("row indexes must be between 0 and %d", length-1)
+	}
+	for i := range df.Columns {
+		if err = df.Columns[i].Decompress(); err != nil {
+			return err
+		}
+	}
+	for i := range df.Columns {
+		df.Columns[i].deleteRows(rows)
+	}
+	return nil
+}
+
+// InsertRows inserts the rows of rows before row at, an at equal to the
+// length appends them. rows must have the same column names, in any order.
+// Numbers inserted into string columns are formatted and strings inserted
+// into float columns must be numbers or "NaN".
+func (df *DataFrame) InsertRows(at int, rows DataFrame) (err error) {
+	defer df.track("InsertRows", at, rows.GetShape())(&err)
+	if err = df.checkLengths(); err != nil {
+		return err
+	}
+	if err = rows.checkLengths(); err != nil {
+		return fmt.Errorf("rows to insert: %w", err)
+	}
+	if at < 0 || at > df.GetLength() {
+		return fmt.Errorf("insert position %d out of range for length %d", at, df.GetLength())
+	}
+	if len(rows.Columns) != len(df.Columns) {
+		return fmt.Errorf("rows have %d columns, the DataFrame has %d", len(rows.Columns), len(df.Columns))
+	}
+	// Convert every column before inserting so a failure leaves df unchanged
+	floats := make([][]float64, len(df.Columns))
+	texts := make([][]string, len(df.Columns))
+	for i := range df.Columns {
+		series := &df.Columns[i]
+		source, err := rows.GetColumnByName(series.Name)
+		if err != nil {
+			return fmt.Errorf("rows are missing column %q: %w", series.Name, err)
+		}
+		if series.DataType == "string" {
+			texts[i] = make([]string, source.GetLength())
+			for row := range texts[i] {
+				texts[i][row] = source.GetValueAsString(row)
+			}
+			continue
+		}
+		if source.DataType == "float" {
+			floats[i] = source.Float
+			continue
+		}
+		floats[i] = make([]float64, source.GetLength())
+		for row, value := range source.strings() {
+			number, ok := tryConvertToFloat(value)
+			if !ok {
+				return fmt.Errorf("cannot insert %q into float column %q", value, series.Name)
+			}
+			floats[i][row] = number
+		}
+	}
+	for i := range df.Columns {
+		if err = df.Columns[i].Decompress(); err != nil {
+			return err
+		}
+	}
+	for i := range df.Columns {
+		series := &df.Columns[i]
+		series.invalidateIndexes()
+		if series.DataType == "float" {
+			series.Float = slices.Insert(series.Float, at, floats[i]...)
+		} else {
+			series.String = slices.Insert(series.String, at, texts[i]...)
+		}
+	}
+	return nil
+}
+
+// checkLengths is the invariant of row operations, every column must have
+// as many rows as the first one
+func (df *DataFrame) checkLengths() error {
+	if len(df.Columns) == 0 {
+		return nil
+	}
+	length := df.Columns[0].GetLength()
+	for i := range df.Columns[1:] {
+		series := &df.Columns[i+1]
+		if series.GetLength() != length {
+			return fmt.Errorf("column %q has %d rows, column %q has %d", series.Name, series.GetLength(), df.Columns[0].Name, length)
+		}
+	}
+	return nil
+}
+
+// Transaction runs operation on a copy of df and keeps its columns only when
+// it returns nil and every column still has the same number of rows, so row
+// operations done column by column inside it, like Series.DropNaN, can not
+// leave df half changed or out of sync.
+func (df *DataFrame) Transaction(operation func(tx *DataFrame) error) (err error) {
+	defer df.track("Transaction", operation)(&err)
+	tx := DataFrame{Columns: make([]Series, len(df.Columns)), key: df.key, config: df.config}
+	for i := range df.Columns {
+		tx.Columns[i] = df.Columns[i].Copy()
+	}
+	if err = operation(&tx); err != nil {
+		return fmt.Errorf("transaction rolled back: %w", err)
+	}
+	if err = tx.checkLengths(); err != nil {
+		return fmt.Errorf("transaction rolled back: %w", err)
+	}
+	df.invalidateIndexes()
+	df.Columns, df.key = tx.Columns, tx.key
+	return nil
+}
+
+// At returns the value of a cell, a float64 for float columns and a string
+// for string columns. Nulls are NaN and "NaN".
+func (df *DataFrame) At(row int, identifier any) (any, error) {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cell of column %v: %w", identifier, err)
+	}
+	if row < 0 || row >= series.GetLength() {
+		return nil, fmt.Errorf("row %d out of range for length %d", row, series.GetLength())
+	}
+	if series.DataType == "float" {
+		return series.Float[row], nil
+	}
+	return series.GetValueString(row), nil
+}
+
+// AtAs is At with the type of the value known at compile time, it fails when
+// the column holds the other type
+func AtAs[T float64 | string](df *DataFrame, row int, identifier any) (T, error) {
+	var zero T
+	value, err := df.At(row, identifier)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("column %v holds %T values, not %T", identifier, value, zero)
+	}
+	return typed, nil
+}
+
+// SetAt changes the value of a cell. Float columns take numbers and string
+// columns take strings, other types fail instead of being converted, and nil
+// sets a null. Views created by Slice keep their values.
+func (df *DataFrame) SetAt(row int, identifier any, value any) (err error) {
+	defer df.track("SetAt", row, identifier, value)(&err)
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to set cell of column %v: %w", identifier, err)
+	}
+	if row < 0 || row >= series.GetLength() {
+		return fmt.Errorf("row %d out of range for length %d", row, series.GetLength())
+	}
+	if series.DataType == "float" {
+		number := math.NaN()
+		switch typed := value.(type) {
+		case nil:
+		case float64, float32, int, int32, int64:
+			number, _ = interfaceConvertToFloat(typed)
+		default:
+			return fmt.Errorf("cannot set %T value in float column %v", value, identifier)
+		}
+		series.invalidateIndexes()
+		series.Float[row] = number
+		return nil
+	}
+	text := "NaN"
+	switch typed := value.(type) {
+	case nil:
+	case string:
+		text = typed
+	default:
+		return fmt.Errorf("cannot set %T value in string column %v", value, identifier)
+	}
+	series.invalidateIndexes()
+	series.String[row] = text
+	return nil
+}
+
+// SetWhere assigns value to the rows of a column where condition is true.
+// condition is an Expr, an expression as text, a []bool or a float Series of
+// 0 and 1, like Filter. value is a single value, nil for nulls, or a Series
+// with one value per row whose values are taken from the same rows. Float
+// columns take numbers and string columns strings. A missing column is
+// created with nulls in the other rows.
+func (df *DataFrame) SetWhere(condition any, identifier any, value any) (err error) {
+	defer df.track("SetWhere", condition, identifier, value)(&err)
+	mask, err := df.conditionMask(condition)
+	if err != nil {
+		return fmt.Errorf("failed to set values: %w", err)
+	}
+
+	// Values are read through get so single values and series share the loop
+	var dataType string
+	var getFloat func(row int) float64
+	var getString func(row int) string
+	switch typed := value.(type) {
+	case nil:
+		getFloat = func(int) float64 { return math.NaN() }
+		getString = func(int) string { return "NaN" }
+	case string:
+		dataType = "string"
+		getString = func(int) string { return typed }
+	case float64, float32, int, int32, int64:
+		number, _ := interfaceConvertToFloat(typed)
+		dataType = "float"
+		getFloat = func(int) float64 { return number }
+	case Series:
+		if typed.GetLength() != len(mask) {
+			return fmt.Errorf("value series has length %d, DataFrame length is %d", typed.GetLength(), len(mask))
+		}
+		dataType = typed.DataType
+		if dataType == "float" {
+			getFloat = func(row int) float64 { return typed.Float[row] }
+		} else {
+			values := typed.strings()
+			getString = func(row int) string { return values[row] }
+		}
+	default:
+		return fmt.Errorf("unsupported value type %T", value)
+	}
+
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		name, isName := identifier.(string)
+		if !isName || dataType == "" {
+			return fmt.Errorf("failed to set values of column %v: %w", identifier, err)
+		}
+		created := NewFloatSeries(name, arrayResizeFloat(nil, len(mask), math.NaN()))
+		if dataType == "string" {
+			created = NewStringSeries(name, arrayResizeString(nil, len(mask), "NaN"))
+		}
+		df.Columns = append(df.Columns, created)
+		series = &df.Columns[len(df.Columns)-1]
+	}
+	if dataType != "" && dataType != series.DataType {
+		return fmt.Errorf("cannot set %s values in %s column %v", dataType, series.DataType, identifier)
+	}
+
+	series.invalidateIndexes()
+	for row, selected := range mask {
+		if !selected {
+			continue
+		}
+		if series.DataType == "float" {
+			series.Float[row] = getFloat(row)
+		} else {
+			series.String[row] = getString(row)
+		}
+	}
+	return nil
+}
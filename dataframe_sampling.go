@@ -0,0 +1,305 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// seededRand returns a generator seeded with randomState, 0 seeds from the clock
+func seededRand(randomState int) *rand.Rand {
+	if randomState != 0 {
+		return rand.New(rand.NewSource(int64(randomState)))
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// sampleWeights reads weights as nil for uniform weights, a column
+// identifier, a float Series or a []float64 with one value per row. NaN
+// weights are read as 0 so null rows are never drawn.
+func (df *DataFrame) sampleWeights(weights any) ([]float64, error) {
+	length := df.GetLength()
+	var values []float64
+	switch value := weights.(type) {
+	case nil:
+		return arrayResizeFloat(nil, length, 1), nil
+	case []float64:
+		values = value
+	case Series:
+		if value.DataType != "float" {
+			return nil, fmt.Errorf("weights series %q is not of type float", value.Name)
+		}
+		values = value.Float
+	default:
+		series, err := df.GetColumnDynamic(weights)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve weights column %v: %w", weights, err)
+		}
+		if series.DataType != "float" {
+			return nil, fmt.Errorf("weights column %v is not of type float", weights)
+		}
+		values = series.Float
+	}
+	if len(values) != length {
+		return nil, fmt.Errorf("weights length %d does not match DataFrame length %d", len(values), length)
+	}
+	result := make([]float64, length)
+	for i, weight := range values {
+		if weight < 0 || math.IsInf(weight, 1) {
+			return nil, fmt.Errorf("weight %v at row %d must be finite and not negative", weight, i)
+		}
+		if !math.IsNaN(weight) {
+			result[i] = weight
+		}
+	}
+	return result, nil
+}
+
+// Sample draws n rows with probability proportional to weights, which is nil
+// for uniform sampling, a column identifier, a float Series or a []float64.
+// Weights do not need to add up to 1 and rows with a zero or NaN weight are
+// never drawn. With replace a row can be drawn more than once. Rows are
+// returned in the order they were drawn.
+func (df *DataFrame) Sample(n int, weights any, replace bool, randomState int) (DataFrame, error) {
+	if n < 0 {
+		return DataFrame{}, fmt.Errorf("sample size must not be negative, got %d", n)
+	}
+	values, err := df.sampleWeights(weights)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	rng := seededRand(randomState)
+
+	var rows []int
+	if replace {
+		rows, err = sampleWithReplacement(values, n, rng)
+	} else {
+		rows, err = sampleWithoutReplacement(values, n, rng)
+	}
+	if err != nil {
+		return DataFrame{}, err
+	}
+	return df.SelectRows(rows)
+}
+
+// sampleWithReplacement draws n rows by binary search over the cumulative
+// weights
+func sampleWithReplacement(weights []float64, n int, rng *rand.Rand) ([]int, error) {
+	cumulative := make([]float64, len(weights))
+	total := 0.0
+	for i, weight := range weights {
+		total += weight
+		cumulative[i] = total
+	}
+	if n > 0 && total == 0 {
+		return nil, fmt.Errorf("cannot sample from rows whose weights add up to 0")
+	}
+	rows := make([]int, n)
+	for i := range rows {
+		target := rng.Float64() * total
+		// The first row whose cumulative weight passes target, rows with a
+		// zero weight share the cumulative value of the row before and are
+		// skipped
+		rows[i] = sort.Search(len(cumulative), func(j int) bool { return cumulative[j] > target })
+	}
+	return rows, nil
+}
+
+// sampleWithoutReplacement keeps the n rows with the largest keys u^(1/w)
+// where u is uniform in (0, 1), which draws rows one after another with
+// probability proportional to their weight among the rows left
+// (Efraimidis-Spirakis). Keys are compared as log(u)/w to avoid underflow.
+func sampleWithoutReplacement(weights []float64, n int, rng *rand.Rand) ([]int, error) {
+	candidates := make([]int, 0, len(weights))
+	for i, weight := range weights {
+		if weight > 0 {
+			candidates = append(candidates, i)
+		}
+	}
+	if n > len(candidates) {
+		return nil, fmt.Errorf("cannot sample %d rows without replacement from %d rows with a positive weight", n, len(candidates))
+	}
+	keys := make([]float64, len(weights))
+	for _, row := range candidates {
+		u := rng.Float64()
+		for u == 0 {
+			u = rng.Float64()
+		}
+		keys[row] = math.Log(u) / weights[row]
+	}
+	sort.SliceStable(candidates, func(a, b int) bool { return keys[candidates[a]] > keys[candidates[b]] })
+	return candidates[:n], nil
+}
+
+// strata returns the rows of every combination of values of the columns in
+// stratifyBy, a single stratum with every row when stratifyBy is empty
+func (df *DataFrame) strata(stratifyBy []any) ([][]int, error) {
+	if len(stratifyBy) == 0 {
+		rows := make([]int, df.GetLength())
+		for i := range rows {
+			rows[i] = i
+		}
+		return [][]int{rows}, nil
+	}
+	groupBy, err := df.groupBy(stratifyBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stratify: %w", err)
+	}
+	return groupBy.groups, nil
+}
+
+// allocate splits total between the strata in proportion to their sizes with
+// the largest remainder method, so every stratum gets its exact share rounded
+// up or down and the shares add up to total
+func allocate(strata [][]int, total int) []int {
+	length := 0
+	for _, rows := range strata {
+		length += len(rows)
+	}
+	shares := make([]int, len(strata))
+	if length == 0 {
+		return shares
+	}
+	remainders := make([]float64, len(strata))
+	assigned := 0
+	for i, rows := range strata {
+		exact := float64(total) * float64(len(rows)) / float64(length)
+		shares[i] = int(exact)
+		remainders[i] = exact - float64(shares[i])
+		assigned += shares[i]
+	}
+	order := make([]int, len(strata))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for _, i := range order[:total-assigned] {
+		shares[i]++
+	}
+	return shares
+}
+
+// StratifiedSplit is TrainTestSplit keeping the proportion of every
+// combination of values of the stratifyBy columns in both subsets. Every
+// stratum sends its share of testSize to the test set rounded up or down, so
+// proportions match within one row per stratum. Rows keep their original
+// order in both subsets.
+func StratifiedSplit(df DataFrame, testSize float64, randomState int, stratifyBy ...any) (DataFrame, DataFrame, error) {
+	if testSize < 0 || testSize > 1 {
+		return DataFrame{}, DataFrame{}, fmt.Errorf("testSize must be between 0 and 1")
+	}
+	strata, err := df.strata(stratifyBy)
+	if err != nil {
+		return DataFrame{}, DataFrame{}, err
+	}
+	rng := seededRand(randomState)
+	shares := allocate(strata, int(math.Round(float64(df.GetLength())*testSize)))
+
+	var trainIndices, testIndices []int
+	for i, rows := range strata {
+		shuffled := append([]int(nil), rows...)
+		rng.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+		testIndices = append(testIndices, shuffled[:shares[i]]...)
+		trainIndices = append(trainIndices, shuffled[shares[i]:]...)
+	}
+	sort.Ints(trainIndices)
+	sort.Ints(testIndices)
+
+	trainSet, err := df.SelectRows(trainIndices)
+	if err != nil {
+		return DataFrame{}, DataFrame{}, err
+	}
+	testSet, err := df.SelectRows(testIndices)
+	if err != nil {
+		return DataFrame{}, DataFrame{}, err
+	}
+	return trainSet, testSet, nil
+}
+
+// Fold holds the rows of one cross-validation fold
+type Fold struct {
+	Train []int
+	Test  []int
+}
+
+// Split selects the train and test rows of the fold from df
+func (fold Fold) Split(df DataFrame) (DataFrame, DataFrame, error) {
+	trainSet, err := df.SelectRows(fold.Train)
+	if err != nil {
+		return DataFrame{}, DataFrame{}, err
+	}
+	testSet, err := df.SelectRows(fold.Test)
+	if err != nil {
+		return DataFrame{}, DataFrame{}, err
+	}
+	return trainSet, testSet, nil
+}
+
+// KFold splits the rows of df into k folds of sizes differing by at most one
+// row, every fold is the test set once and the other rows are its train set.
+// Without stratifyBy folds are consecutive blocks of rows, or of shuffled rows
+// when shuffle is set. With stratifyBy the rows of every combination of values
+// of those columns are dealt in turn to the folds, so every fold keeps their
+// proportions. randomState seeds the shuffle, 0 seeds from the clock. Rows are
+// sorted within every fold.
+func KFold(df DataFrame, k int, shuffle bool, randomState int, stratifyBy ...any) ([]Fold, error) {
+	length := df.GetLength()
+	if k < 2 || k > length {
+		return nil, fmt.Errorf("number of folds must be between 2 and the number of rows %d, got %d", length, k)
+	}
+	strata, err := df.strata(stratifyBy)
+	if err != nil {
+		return nil, err
+	}
+	var rng *rand.Rand
+	if shuffle {
+		rng = seededRand(randomState)
+	}
+
+	assignment := make([]int, length)
+	if len(stratifyBy) == 0 {
+		rows := strata[0]
+		if rng != nil {
+			rng.Shuffle(len(rows), func(a, b int) { rows[a], rows[b] = rows[b], rows[a] })
+		}
+		// The first length%k folds hold one extra row
+		position := 0
+		for fold := 0; fold < k; fold++ {
+			size := length / k
+			if fold < length%k {
+				size++
+			}
+			for _, row := range rows[position : position+size] {
+				assignment[row] = fold
+			}
+			position += size
+		}
+	} else {
+		// Dealing continues across strata so fold sizes stay balanced
+		position := 0
+		for _, rows := range strata {
+			dealt := append([]int(nil), rows...)
+			if rng != nil {
+				rng.Shuffle(len(dealt), func(a, b int) { dealt[a], dealt[b] = dealt[b], dealt[a] })
+			}
+			for _, row := range dealt {
+				assignment[row] = position % k
+				position++
+			}
+		}
+	}
+
+	folds := make([]Fold, k)
+	for row, fold := range assignment {
+		for i := range folds {
+			if i == fold {
+				folds[i].Test = append(folds[i].Test, row)
+			} else {
+				folds[i].Train = append(folds[i].Train, row)
+			}
+		}
+	}
+	return folds, nil
+}
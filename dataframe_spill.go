@@ -0,0 +1,582 @@
+package grizzly
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// SortWith and Merge keep their row numbers in temporary files when they
+// would need more than Config.MemoryLimit: SortWith sorts runs of rows that
+// fit in the limit and merges them from disk, and Merge splits the keys by
+// hash in partitions whose index fits in the limit and joins one partition
+// at a time. Only the row numbers are spilled, the columns and the result
+// are still held in memory: SortWith reorders one column at a time so it
+// needs one extra column on top of the DataFrame, and Merge builds its
+// result from disk after the partitions are joined.
+
+const (
+	// sortRowBytes estimates the memory of SortIndices per row: the
+	// permutation, its merge buffer and the null flags
+	sortRowBytes = 17
+	// indexRowBytes estimates the memory of a hash index per row
+	indexRowBytes = 48
+	// pairRowBytes is the memory of a pair of rows of a merge
+	pairRowBytes = 16
+	// spillMinRows keeps runs and partitions from getting too small when
+	// the limit is tiny, every one of them holds a file buffer while merged
+	spillMinRows = 1024
+	// spillMaxPartitions bounds the partitions of a merge, so partitions
+	// may exceed the limit when it is tiny compared to the keys
+	spillMaxPartitions = 4096
+	// spillBucketRows is the rows buffered per partition while the rows
+	// are grouped by partition
+	spillBucketRows = 64
+)
+
+// spillFile is a temporary file of integers, written in order and read back
+// in sections by several readers at once
+type spillFile struct {
+	file   *os.File
+	writer *bufio.Writer
+	length int // Integers written
+	buffer [8]byte
+}
+
+func newSpillFile() (*spillFile, error) {
+	file, err := os.CreateTemp(GetConfig().TempDir, "grizzly-spill-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spill file: %w", err)
+	}
+	return &spillFile{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+func (spill *spillFile) write(values ...int) error {
+	for _, value := range values {
+		binary.LittleEndian.PutUint64(spill.buffer[:], uint64(value))
+		if _, err := spill.writer.Write(spill.buffer[:]); err != nil {
+			return fmt.Errorf("failed to write spill file: %w", err)
+		}
+	}
+	spill.length += len(values)
+	return nil
+}
+
+// section returns a reader of the integers from start to end, the buffered
+// integers are written first
+func (spill *spillFile) section(start, end int) (*spillReader, error) {
+	if err := spill.writer.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write spill file: %w", err)
+	}
+	section := io.NewSectionReader(spill.file, int64(start)*8, int64(end-start)*8)
+	return &spillReader{reader: bufio.NewReader(section), remaining: end - start}, nil
+}
+
+// remove closes and deletes the file
+func (spill *spillFile) remove() {
+	spill.file.Close()
+	os.Remove(spill.file.Name())
+}
+
+type spillReader struct {
+	reader    *bufio.Reader
+	remaining int
+	buffer    [8]byte
+}
+
+// next reads the next integers into values, false at the end of the section
+func (reader *spillReader) next(values []int) (bool, error) {
+	if reader.remaining < len(values) {
+		return false, nil
+	}
+	for i := range values {
+		if _, err := io.ReadFull(reader.reader, reader.buffer[:]); err != nil {
+			return false, fmt.Errorf("failed to read spill file: %w", err)
+		}
+		values[i] = int(int64(binary.LittleEndian.Uint64(reader.buffer[:])))
+	}
+	reader.remaining -= len(values)
+	return true, nil
+}
+
+// spillHead is the current row of a sorted section being merged
+type spillHead struct {
+	row    []int
+	source int
+	reader *spillReader
+}
+
+// spillHeap merges sorted sections, ties go to the earlier section
+type spillHeap struct {
+	heads []spillHead
+	less  func(a, b []int) bool
+}
+
+func (h *spillHeap) Len() int { return len(h.heads) }
+
+func (h *spillHeap) Less(i, j int) bool {
+	a, b := h.heads[i], h.heads[j]
+	if h.less(a.row, b.row) {
+		return true
+	}
+	return !h.less(b.row, a.row) && a.source < b.source
+}
+
+func (h *spillHeap) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+
+func (h *spillHeap) Push(x any) { h.heads = append(h.heads, x.(spillHead)) }
+
+func (h *spillHeap) Pop() any {
+	head := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return head
+}
+
+// mergeSections writes the rows of the sorted sections of spill to output in
+// order, every row is made of width integers
+func mergeSections(spill *spillFile, bounds []int, width int, less func(a, b []int) bool, output *spillFile) error {
+	merging := &spillHeap{less: less}
+	for s := 0; s+1 < len(bounds); s++ {
+		reader, err := spill.section(bounds[s], bounds[s+1])
+		if err != nil {
+			return err
+		}
+		head := spillHead{row: make([]int, width), source: s, reader: reader}
+		found, err := reader.next(head.row)
+		if err != nil {
+			return err
+		}
+		if found {
+			merging.heads = append(merging.heads, head)
+		}
+	}
+	heap.Init(merging)
+	for merging.Len() > 0 {
+		head := merging.heads[0]
+		if err := output.write(head.row...); err != nil {
+			return err
+		}
+		found, err := head.reader.next(head.row)
+		if err != nil {
+			return err
+		}
+		if found {
+			heap.Fix(merging, 0)
+		} else {
+			heap.Pop(merging)
+		}
+	}
+	return nil
+}
+
+// spillTake copies rows of series like seriesTake, reading them from reader
+// with width integers per row of which column is used. When fallback is not
+// nil, rows without a value take the value of fallback at the other integer
+// of the pair, like the key column of a merge.
+func spillTake(series Series, fallback *Series, reader *spillReader, length, width, column int) (Series, error) {
+	row := make([]int, width)
+	var result Series
+	if series.DataType == "float" {
+		values := make([]float64, length)
+		for i := range values {
+			if _, err := reader.next(row); err != nil {
+				return Series{}, err
+			}
+			switch {
+			case row[column] >= 0:
+				values[i] = series.Float[row[column]]
+			case fallback != nil && row[1-column] >= 0:
+				values[i] = fallback.Float[row[1-column]]
+			default:
+				values[i] = math.NaN()
+			}
+		}
+		result = NewFloatSeries(series.Name, values)
+	} else {
+		source := series.strings()
+		var other []string
+		if fallback != nil {
+			other = fallback.strings()
+		}
+		values := make([]string, length)
+		for i := range values {
+			if _, err := reader.next(row); err != nil {
+				return Series{}, err
+			}
+			switch {
+			case row[column] >= 0:
+				values[i] = source[row[column]]
+			case fallback != nil && row[1-column] >= 0:
+				values[i] = other[row[1-column]]
+			default:
+				values[i] = "NaN"
+			}
+		}
+		result = NewStringSeries(series.Name, values)
+	}
+	result.Metadata = copyMetadata(series.Metadata)
+	if series.IsCompressed() {
+		result.Compress()
+	}
+	return result, nil
+}
+
+// spillSortIndices writes the permutation that sorts the series to a spill
+// file, the caller removes it. Runs of rows that fit in limit are sorted in
+// memory and merged from disk, earlier runs win ties so a stable sort keeps
+// the order of equal rows.
+func (series *Series) spillSortIndices(options SortOptions, limit int) (*spillFile, error) {
+	less, err := series.sortLess(options)
+	if err != nil {
+		return nil, err
+	}
+	length := series.GetLength()
+	runRows := max(limit/sortRowBytes, spillMinRows)
+	runs, err := newSpillFile()
+	if err != nil {
+		return nil, err
+	}
+	defer runs.remove()
+
+	run := make([]int, 0, min(runRows, length))
+	bounds := []int{0}
+	for start := 0; start < length; start += runRows {
+		run = run[:0]
+		for row := start; row < min(start+runRows, length); row++ {
+			run = append(run, row)
+		}
+		if options.Stable {
+			sort.SliceStable(run, func(i, j int) bool { return less(run[i], run[j]) })
+		} else {
+			sort.Slice(run, func(i, j int) bool { return less(run[i], run[j]) })
+		}
+		if err = runs.write(run...); err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, runs.length)
+	}
+	run = nil
+
+	sorted, err := newSpillFile()
+	if err != nil {
+		return nil, err
+	}
+	err = mergeSections(runs, bounds, 1, func(a, b []int) bool { return less(a[0], b[0]) }, sorted)
+	if err != nil {
+		sorted.remove()
+		return nil, err
+	}
+	return sorted, nil
+}
+
+// spillSort is SortWith for DataFrames whose permutation does not fit in
+// limit. Every column is replaced as soon as its sorted copy is read, so
+// only one extra column is held at a time; a failure to read the spill file
+// leaves the columns before it sorted and the error says so.
+func (df *DataFrame) spillSort(series *Series, options SortOptions, limit int) error {
+	sorted, err := series.spillSortIndices(options, limit)
+	if err != nil {
+		return err
+	}
+	defer sorted.remove()
+	length := series.GetLength()
+	for i := range df.Columns {
+		reader, err := sorted.section(0, length)
+		if err == nil {
+			var column Series
+			if column, err = spillTake(df.Columns[i], nil, reader, length, 1, 0); err == nil {
+				// The old values are not modified, so shared series
+				// keep them
+				df.Columns[i] = column
+				continue
+			}
+		}
+		if i > 0 {
+			return fmt.Errorf("only the first %d columns were sorted: %w", i, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// spillMerge is merge for keys whose index or pairs do not fit in limit. The
+// right keys are split by hash in partitions whose index fits in limit, the
+// rows of both sides are grouped by partition on disk in one pass, and the
+// left rows of every partition are matched reading only the rows of the
+// partition, writing the pairs to disk. The passes are merged by left row
+// and the unmatched right rows go last, so the rows come in the same order
+// as in memory.
+func (df *DataFrame) spillMerge(otherDf DataFrame, series, keys *Series, how string, options JoinOptions, limit int) (DataFrame, error) {
+	switch options.Validate {
+	case "", "one_to_one", "one_to_many", "many_to_one":
+	default:
+		return DataFrame{}, fmt.Errorf("unsupported validation %q", options.Validate)
+	}
+	partitionRows := max(limit/2/indexRowBytes, spillMinRows)
+	partitions := min((keys.GetLength()+partitionRows-1)/partitionRows, spillMaxPartitions)
+	partitions = max(partitions, 1)
+	seed := maphash.MakeSeed()
+	leftRows, leftBounds, err := spillPartitions(series.keyPartitions(seed, partitions), partitions)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	defer leftRows.remove()
+	rightRows, rightBounds, err := spillPartitions(keys.keyPartitions(seed, partitions), partitions)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	defer rightRows.remove()
+
+	leftKeys, rightKeys := series.strings(), keys.strings()
+	passes, err := newSpillFile()
+	if err != nil {
+		return DataFrame{}, err
+	}
+	defer passes.remove()
+	matched := make([]bool, keys.GetLength())
+	bounds := []int{0}
+	progress := startProgress("join", series.GetLength())
+	defer progress.finish()
+	for p := 0; p < partitions; p++ {
+		pass := joinPass{series: series, keys: keys, how: how, validate: options.Validate, matched: matched, pairs: passes}
+		pass.left = func() (*spillReader, error) { return leftRows.section(leftBounds[p], leftBounds[p+1]) }
+		pass.right = func() (*spillReader, error) { return rightRows.section(rightBounds[p], rightBounds[p+1]) }
+		if series.DataType == "float" {
+			err = joinPartition(&pass, series.Float, keys.Float)
+		} else {
+			err = joinPartition(&pass, leftKeys, rightKeys)
+		}
+		if err != nil {
+			return DataFrame{}, err
+		}
+		progress.add(pass.rows)
+		bounds = append(bounds, passes.length)
+	}
+
+	pairs, err := newSpillFile()
+	if err != nil {
+		return DataFrame{}, err
+	}
+	defer pairs.remove()
+	err = mergeSections(passes, bounds, 2, func(a, b []int) bool { return a[0] < b[0] }, pairs)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	if how == "right" || how == "outer" {
+		for j, found := range matched {
+			if found {
+				continue
+			}
+			if err = pairs.write(-1, j); err != nil {
+				return DataFrame{}, err
+			}
+		}
+	}
+
+	length := pairs.length / 2
+	take := func(column Series, right bool) (Series, error) {
+		reader, err := pairs.section(0, pairs.length)
+		if err != nil {
+			return Series{}, err
+		}
+		if right {
+			return spillTake(column, nil, reader, length, 2, 1)
+		}
+		return spillTake(column, nil, reader, length, 2, 0)
+	}
+	takeKey := func(column Series) (Series, error) {
+		reader, err := pairs.section(0, pairs.length)
+		if err != nil {
+			return Series{}, err
+		}
+		return spillTake(column, keys, reader, length, 2, 0)
+	}
+	indicator := func() ([]string, error) {
+		reader, err := pairs.section(0, pairs.length)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, length)
+		row := make([]int, 2)
+		for i := range values {
+			if _, err = reader.next(row); err != nil {
+				return nil, err
+			}
+			values[i] = mergeIndicator(row[0], row[1])
+		}
+		return values, nil
+	}
+	return mergeColumns(df, otherDf, series.Name, options, take, takeKey, indicator)
+}
+
+// keyPartitions assigns every value of the series to one of the partitions
+// by hash, equal values always get the same partition
+func (series *Series) keyPartitions(seed maphash.Seed, partitions int) []uint16 {
+	result := make([]uint16, series.GetLength())
+	if series.DataType == "float" {
+		var buffer [8]byte
+		for i, value := range series.Float {
+			if value == 0 {
+				// -0 and 0 are the same key
+				value = 0
+			}
+			binary.LittleEndian.PutUint64(buffer[:], math.Float64bits(value))
+			result[i] = uint16(maphash.Bytes(seed, buffer[:]) % uint64(partitions))
+		}
+		return result
+	}
+	for i, value := range series.strings() {
+		result[i] = uint16(maphash.String(seed, value) % uint64(partitions))
+	}
+	return result
+}
+
+// spillPartitions writes the rows grouped by their partition to a spill file,
+// in order within every partition, and returns where each partition starts
+// with the end of the file last. Rows are buffered per partition and written
+// at the position of their partition, so memory stays bounded.
+func spillPartitions(partitionOf []uint16, partitions int) (*spillFile, []int, error) {
+	bounds := make([]int, partitions+1)
+	for _, p := range partitionOf {
+		bounds[p+1]++
+	}
+	for p := 0; p < partitions; p++ {
+		bounds[p+1] += bounds[p]
+	}
+	spill, err := newSpillFile()
+	if err != nil {
+		return nil, nil, err
+	}
+	next := append([]int{}, bounds[:partitions]...)
+	buffers := make([]byte, partitions*spillBucketRows*8)
+	counts := make([]int, partitions)
+	flush := func(p int) error {
+		buffer := buffers[p*spillBucketRows*8 : (p*spillBucketRows+counts[p])*8]
+		if _, err := spill.file.WriteAt(buffer, int64(next[p])*8); err != nil {
+			return fmt.Errorf("failed to write spill file: %w", err)
+		}
+		next[p] += counts[p]
+		counts[p] = 0
+		return nil
+	}
+	for row, p := range partitionOf {
+		position := (int(p)*spillBucketRows + counts[p]) * 8
+		binary.LittleEndian.PutUint64(buffers[position:], uint64(row))
+		counts[p]++
+		if counts[p] == spillBucketRows {
+			if err = flush(int(p)); err != nil {
+				spill.remove()
+				return nil, nil, err
+			}
+		}
+	}
+	for p := range counts {
+		if err = flush(p); err != nil {
+			spill.remove()
+			return nil, nil, err
+		}
+	}
+	spill.length = len(partitionOf)
+	return spill, bounds, nil
+}
+
+// joinPass holds the state shared by the partitions of spillMerge
+type joinPass struct {
+	series, keys *Series
+	how          string
+	validate     string
+	matched      []bool
+	pairs        *spillFile
+	left, right  func() (*spillReader, error) // Rows of the partition
+	rows         int                          // Left rows of the partition
+}
+
+// eachRow calls fn with every row read by the reader returned by rows
+func eachRow(rows func() (*spillReader, error), fn func(row int) error) error {
+	reader, err := rows()
+	if err != nil {
+		return err
+	}
+	row := make([]int, 1)
+	for {
+		found, err := reader.next(row)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return nil
+		}
+		if err = fn(row[0]); err != nil {
+			return err
+		}
+	}
+}
+
+// joinPartition writes the pairs of the left rows of the partition in the
+// order of the left rows, with the right rows of each key in order
+func joinPartition[K comparable](pass *joinPass, left, right []K) error {
+	index := map[K][]int{}
+	err := eachRow(pass.right, func(j int) error {
+		index[right[j]] = append(index[right[j]], j)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if pass.validate == "one_to_one" || pass.validate == "one_to_many" {
+		counts := map[K]int{}
+		err = eachRow(pass.left, func(i int) error {
+			counts[left[i]]++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		err = eachRow(pass.left, func(i int) error {
+			if counts[left[i]] > 1 {
+				return fmt.Errorf("merge is not %s, left keys: key %q is repeated %d times", pass.validate, pass.series.GetValueAsString(i), counts[left[i]])
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if pass.validate == "one_to_one" || pass.validate == "many_to_one" {
+		err = eachRow(pass.right, func(j int) error {
+			if len(index[right[j]]) > 1 {
+				return fmt.Errorf("merge is not %s, right keys: key %q is repeated %d times", pass.validate, pass.keys.GetValueAsString(j), len(index[right[j]]))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return eachRow(pass.left, func(i int) error {
+		key := left[i]
+		pass.rows++
+		matches := index[key]
+		if len(matches) == 0 {
+			if pass.how == "left" || pass.how == "outer" {
+				return pass.pairs.write(i, -1)
+			}
+			return nil
+		}
+		for _, match := range matches {
+			if err := pass.pairs.write(i, match); err != nil {
+				return err
+			}
+			pass.matched[match] = true
+		}
+		return nil
+	})
+}
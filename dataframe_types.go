@@ -0,0 +1,133 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// TypeInference is the type that best describes a sample of values.
+// Confidence is the share of non-null values that can be read as Type.
+type TypeInference struct {
+	Type       string
+	Confidence float64
+	Nulls      int
+}
+
+// boolValues are the tokens read as booleans, compared in lower case
+var boolValues = map[string]float64{
+	"true": 1, "false": 0, "t": 1, "f": 0, "yes": 1, "no": 0, "y": 1, "n": 0,
+}
+
+func parseBool(value string) (float64, bool) {
+	number, ok := boolValues[strings.ToLower(strings.TrimSpace(value))]
+	return number, ok
+}
+
+// InferType reports the most specific type among "bool", "int", "float" and
+// "datetime" that the largest share of the non-null values of sample can be
+// read as. When less than half of the values fit any of them the type is
+// "string". Nulls are the tokens of the Config.
+func InferType(sample []string) TypeInference {
+	nulls := GetConfig().nullValues()
+	var counts [4]int
+	total := 0
+	result := TypeInference{}
+	for _, value := range sample {
+		if nulls[value] {
+			result.Nulls++
+			continue
+		}
+		total++
+		if _, ok := parseBool(value); ok {
+			counts[0]++
+		}
+		if number, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			counts[2]++
+			if number == math.Trunc(number) && !math.IsInf(number, 0) {
+				counts[1]++
+			}
+		}
+		if _, ok := parseDateTime(value); ok {
+			counts[3]++
+		}
+	}
+	if total == 0 {
+		result.Type = "string"
+		return result
+	}
+
+	best := 0
+	for i, count := range counts {
+		if count > counts[best] {
+			best = i
+		}
+	}
+	share := float64(counts[best]) / float64(total)
+	if share < 0.5 {
+		result.Type = "string"
+		result.Confidence = 1 - share
+		return result
+	}
+	result.Type = []string{"bool", "int", "float", "datetime"}[best]
+	result.Confidence = share
+	return result
+}
+
+// InferTypes returns a DataFrame with the inferred type of every column,
+// with the columns "column", "type", "confidence" and "nulls". Only the
+// first sampleRows rows are read, all of them when it is 0 or less.
+func (df *DataFrame) InferTypes(sampleRows int) DataFrame {
+	length := df.GetLength()
+	if sampleRows <= 0 || sampleRows > length {
+		sampleRows = length
+	}
+	names := make([]string, len(df.Columns))
+	types := make([]string, len(df.Columns))
+	confidences := make([]float64, len(df.Columns))
+	nulls := make([]float64, len(df.Columns))
+	for i, series := range df.Columns {
+		sample := make([]string, sampleRows)
+		for row := range sample {
+			sample[row] = series.GetValueAsString(row)
+		}
+		inference := InferType(sample)
+		names[i] = series.Name
+		types[i] = inference.Type
+		confidences[i] = inference.Confidence
+		nulls[i] = float64(inference.Nulls)
+	}
+	return DataFrame{Columns: []Series{
+		NewStringSeries("column", names),
+		NewStringSeries("type", types),
+		NewFloatSeries("confidence", confidences),
+		NewFloatSeries("nulls", nulls),
+	}}
+}
+
+// ConvertTypes casts the columns to the types in the map, like the ones
+// reported by InferTypes, with Cast in strict mode. Every column is checked
+// before any of them changes.
+func (df *DataFrame) ConvertTypes(types map[string]string) (err error) {
+	defer df.track("ConvertTypes", types)(&err)
+	converted := make(map[string]Series, len(types))
+	for name, dtype := range types {
+		series, err := df.GetColumnByName(name)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve column to convert %q: %w", name, err)
+		}
+		copied := series.Copy()
+		if _, err = copied.Cast(dtype, CastOptions{Strict: true}); err != nil {
+			return err
+		}
+		converted[name] = copied
+	}
+	df.invalidateIndexes()
+	for i, series := range df.Columns {
+		if replacement, ok := converted[series.Name]; ok {
+			df.Columns[i] = replacement
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// Rule is a condition every row must meet, it can reference several
+// columns, like Col("end").Timestamp().Ge(Col("start").Timestamp()) or
+// Col("qty").Mul(Col("price")).Near(Col("total"), 0.01). Rows where the
+// condition is false or NaN violate the rule.
+type Rule struct {
+	Name      string
+	Condition Expr
+}
+
+// Validate checks every rule in parallel and returns a DataFrame with one
+// row per violation and the columns "rule", "row" and "values", values holds
+// the columns referenced by the rule as "name=value" pairs. An empty
+// DataFrame with the same columns means every row is valid.
+func (df *DataFrame) Validate(rules []Rule) (DataFrame, error) {
+	violations := make([][]int, len(rules))
+	errs := make([]error, len(rules))
+	var wg sync.WaitGroup
+
+	// The rules read a private copy of the columns, decompressed once and
+	// with the statistics of the referenced columns computed, so no rule
+	// writes anything shared with the others
+	view := DataFrame{Columns: append([]Series{}, df.decompressedColumns()...), config: df.config}
+	for _, rule := range rules {
+		for _, name := range rule.Condition.columns() {
+			if series, err := view.GetColumnByName(name); err == nil && series.DataType == "float" {
+				series.statistics()
+			}
+		}
+	}
+
+	for i, rule := range rules {
+		wg.Add(1)
+		go func(i int, rule Rule) {
+			defer wg.Done()
+			result, err := view.Evaluate(rule.Condition)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to check rule %q: %w", rule.Name, err)
+				return
+			}
+			if result.DataType != "float" {
+				errs[i] = fmt.Errorf("rule %q is not a boolean expression", rule.Name)
+				return
+			}
+			for row, value := range result.Float {
+				if value == 0 || math.IsNaN(value) {
+					violations[i] = append(violations[i], row)
+				}
+			}
+		}(i, rule)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return DataFrame{}, err
+		}
+	}
+
+	names := []string{}
+	values := []string{}
+	rows := []float64{}
+	for i, rule := range rules {
+		columns := rule.Condition.columns()
+		for _, row := range violations[i] {
+			pairs := make([]string, len(columns))
+			for j, name := range columns {
+				series, _ := view.GetColumnByName(name)
+				pairs[j] = name + "=" + series.GetValueAsString(row)
+			}
+			names = append(names, rule.Name)
+			rows = append(rows, float64(row))
+			values = append(values, strings.Join(pairs, ", "))
+		}
+	}
+	return DataFrame{Columns: []Series{
+		NewStringSeries("rule", names),
+		NewFloatSeries("row", rows),
+		NewStringSeries("values", values),
+	}}, nil
+}
@@ -0,0 +1,117 @@
+package grizzly
+
+import (
+	"fmt"
+)
+
+// version is a committed state of a DataFrame. Stored series are shared views
+// of the columns (see Series.Slice) and are never modified, so committing
+// and checking out do not copy values and versions share the columns that
+// did not change between them.
+type version struct {
+	tag     string
+	columns []Series
+}
+
+type versionStore struct {
+	versions []version
+}
+
+func (store *versionStore) copy() *versionStore {
+	if store == nil {
+		return nil
+	}
+	return &versionStore{versions: append([]version{}, store.versions...)}
+}
+
+func (store *versionStore) find(tag string) (version, bool) {
+	for _, stored := range store.versions {
+		if stored.tag == tag {
+			return stored, true
+		}
+	}
+	return version{}, false
+}
+
+// versionView returns a view of the values of series, both are marked as
+// shared so the first change to series copies its values
+func versionView(series *Series) Series {
+	view := *series
+	view.Metadata = copyMetadata(series.Metadata)
+	view.Float = series.Float[:len(series.Float):len(series.Float)]
+	view.String = series.String[:len(series.String):len(series.String)]
+	view.stats = &statsCache{}
+	series.shared = true
+	view.shared = true
+	return view
+}
+
+// Commit stores the current state of df under tag so it can be restored with
+// Checkout. Versions are kept in memory as views of the columns, the values
+// are only copied when a column is modified after the commit.
+func (df *DataFrame) Commit(tag string) error {
+	if df.versions == nil {
+		df.versions = &versionStore{}
+	}
+	if _, ok := df.versions.find(tag); ok {
+		return fmt.Errorf("version %q already exists", tag)
+	}
+	columns := make([]Series, len(df.Columns))
+	for i := range df.Columns {
+		columns[i] = versionView(&df.Columns[i])
+	}
+	df.versions.versions = append(df.versions.versions, version{tag: tag, columns: columns})
+	return nil
+}
+
+// Checkout replaces the columns of df with views of the ones committed under
+// tag, versions committed after it are kept
+func (df *DataFrame) Checkout(tag string) (err error) {
+	defer df.track("Checkout", tag)(&err)
+	stored, err := df.version(tag)
+	if err != nil {
+		return err
+	}
+	df.Columns = make([]Series, len(stored.columns))
+	for i, series := range stored.columns {
+		// Stored series are already marked as shared
+		df.Columns[i] = series
+		df.Columns[i].Metadata = copyMetadata(series.Metadata)
+	}
+	return nil
+}
+
+// Versions returns the committed tags from the oldest to the newest
+func (df *DataFrame) Versions() []string {
+	if df.versions == nil {
+		return nil
+	}
+	tags := make([]string, len(df.versions.versions))
+	for i, stored := range df.versions.versions {
+		tags[i] = stored.tag
+	}
+	return tags
+}
+
+// DiffVersions compares two committed versions with DiffRows, matching rows
+// by the key columns
+func (df *DataFrame) DiffVersions(a, b string, keyIdentifiers ...any) (RowDiff, error) {
+	old, err := df.version(a)
+	if err != nil {
+		return RowDiff{}, err
+	}
+	current, err := df.version(b)
+	if err != nil {
+		return RowDiff{}, err
+	}
+	return DiffRows(DataFrame{Columns: old.columns}, DataFrame{Columns: current.columns}, keyIdentifiers...)
+}
+
+func (df *DataFrame) version(tag string) (version, error) {
+	if df.versions != nil {
+		if stored, ok := df.versions.find(tag); ok {
+			return stored, nil
+		}
+	}
+	return version{}, fmt.Errorf("version %q does not exist", tag)
+}
@@ -0,0 +1,117 @@
+package grizzly
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// encryptionMagic starts the files written by WriteEncrypted, followed by
+// the nonce and the AES-GCM sealed data
+var encryptionMagic = []byte("GRZENC1\n")
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key, use 16, 24 or 32 bytes: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func sealData(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := append(append([]byte{}, encryptionMagic...), nonce...)
+	return gcm.Seal(sealed, nonce, plaintext, nil), nil
+}
+
+func openData(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, encryptionMagic) || len(data) < len(encryptionMagic)+gcm.NonceSize() {
+		return nil, fmt.Errorf("data is not encrypted by grizzly")
+	}
+	data = data[len(encryptionMagic):]
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data, wrong key or corrupted data: %w", err)
+	}
+	return plaintext, nil
+}
+
+// WriteEncrypted serializes df with a registered format, like "csv", and
+// writes it encrypted with AES-GCM to a path or an io.Writer. key is 16, 24
+// or 32 bytes for AES-128, AES-192 or AES-256.
+func (df *DataFrame) WriteEncrypted(destination any, format string, key []byte) error {
+	registered, extension, err := getFormat("." + normalizeExtension(format))
+	if err != nil {
+		return err
+	}
+	if registered.writer == nil {
+		return fmt.Errorf("format %q can not be written", extension)
+	}
+	var buffer bytes.Buffer
+	if err = registered.writer.Write(&buffer, df); err != nil {
+		return fmt.Errorf("failed to serialize %q: %w", extension, err)
+	}
+	sealed, err := sealData(key, buffer.Bytes())
+	if err != nil {
+		return err
+	}
+	output, closeDestination, err := openDestination(destination)
+	if err != nil {
+		return err
+	}
+	if _, err = output.Write(sealed); err != nil {
+		closeDestination()
+		return fmt.Errorf("failed to write encrypted data: %w", err)
+	}
+	return closeDestination()
+}
+
+// ReadEncrypted decrypts data written by WriteEncrypted and parses it with a
+// registered format. Data modified after it was written is rejected.
+func ReadEncrypted(source any, format string, key []byte) (DataFrame, error) {
+	registered, extension, err := getFormat("." + normalizeExtension(format))
+	if err != nil {
+		return DataFrame{}, err
+	}
+	if registered.reader == nil {
+		return DataFrame{}, fmt.Errorf("format %q can not be read", extension)
+	}
+	input, closeSource, err := openSource(source)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	defer closeSource()
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to read encrypted data: %w", err)
+	}
+	plaintext, err := openData(key, data)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	return registered.reader.Read(bytes.NewReader(plaintext))
+}
+
+// WriteCSVEncrypted is WriteCSV encrypted with AES-GCM
+func (df *DataFrame) WriteCSVEncrypted(destination any, key []byte) error {
+	return df.WriteEncrypted(destination, "csv", key)
+}
+
+// ReadCSVEncrypted reads a CSV file written by WriteCSVEncrypted
+func ReadCSVEncrypted(source any, key []byte) (DataFrame, error) {
+	return ReadEncrypted(source, "csv", key)
+}
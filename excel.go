@@ -0,0 +1,305 @@
+package grizzly
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExcelOptions styles the sheet written by WriteExcel. NumberFormats and
+// ColorScales are keyed by column name, number formats are Excel format
+// codes like "#,##0.00" or "0.0%".
+type ExcelOptions struct {
+	SheetName     string
+	NumberFormats map[string]string
+	ColorScales   map[string]ColorScale
+	FreezeHeader  bool
+	AutoWidth     bool
+}
+
+// ColorScale shades the cells of a float column from the Min color for the
+// lowest value to the Max color for the highest, through Mid at the median
+// when it is set. Colors are hex RGB like "F8696B" or "#F8696B".
+type ColorScale struct {
+	Min string
+	Mid string
+	Max string
+}
+
+// RedYellowGreen is the usual scale where low values are red and high ones green
+var RedYellowGreen = ColorScale{Min: "F8696B", Mid: "FFEB84", Max: "63BE7B"}
+
+func init() {
+	formats["xlsx"] = format{
+		writer: WriterFunc(func(output io.Writer, df *DataFrame) error {
+			return df.WriteExcel(output, ExcelOptions{FreezeHeader: true, AutoWidth: true})
+		}),
+	}
+}
+
+// WriteExcel writes df as the only sheet of an xlsx workbook to a path or an
+// io.Writer. The header row is bold, NaN values are left empty and float
+// columns are written as numbers.
+func (df *DataFrame) WriteExcel(destination any, options ExcelOptions) error {
+	if err := df.validateExcelOptions(&options); err != nil {
+		return err
+	}
+	output, closeDestination, err := openDestination(destination)
+	if err != nil {
+		return err
+	}
+	if err = df.writeExcel(output, options); err != nil {
+		closeDestination()
+		return err
+	}
+	return closeDestination()
+}
+
+func (df *DataFrame) validateExcelOptions(options *ExcelOptions) error {
+	if options.SheetName == "" {
+		options.SheetName = "Sheet1"
+	}
+	if utf8.RuneCountInString(options.SheetName) > 31 || strings.ContainsAny(options.SheetName, `[]:*?/\`) {
+		return fmt.Errorf("invalid sheet name %q, it must have at most 31 characters and none of []:*?/\\", options.SheetName)
+	}
+	for name := range options.NumberFormats {
+		if !isNameRepeated(df.Columns, name) {
+			return fmt.Errorf("number format for column %q not found", name)
+		}
+	}
+	for name, scale := range options.ColorScales {
+		series, err := df.GetColumnByName(name)
+		if err != nil {
+			return fmt.Errorf("color scale for column %q: %w", name, err)
+		}
+		if series.DataType != "float" {
+			return fmt.Errorf("color scale for column %q requires a float column", name)
+		}
+		if scale.Min == "" || scale.Max == "" {
+			return fmt.Errorf("color scale for column %q requires Min and Max colors", name)
+		}
+		for _, color := range []string{scale.Min, scale.Mid, scale.Max} {
+			if color == "" {
+				continue
+			}
+			if _, err := excelColor(color); err != nil {
+				return fmt.Errorf("color scale for column %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// excelColor converts a hex RGB color to the ARGB form of Excel
+func excelColor(color string) (string, error) {
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 {
+		return "", fmt.Errorf("invalid color %q, expected hex RGB like F8696B", color)
+	}
+	if _, err := strconv.ParseUint(hex, 16, 32); err != nil {
+		return "", fmt.Errorf("invalid color %q, expected hex RGB like F8696B", color)
+	}
+	return "FF" + strings.ToUpper(hex), nil
+}
+
+// excelColumn returns the letters of the column at index, A for 0 and AA for 26
+func excelColumn(index int) string {
+	var letters []byte
+	for index++; index > 0; index = (index - 1) / 26 {
+		letters = append([]byte{byte('A' + (index-1)%26)}, letters...)
+	}
+	return string(letters)
+}
+
+func excelEscape(text string) string {
+	var builder strings.Builder
+	xml.EscapeText(&builder, []byte(text))
+	return builder.String()
+}
+
+func (df *DataFrame) writeExcel(output io.Writer, options ExcelOptions) error {
+	archive := zip.NewWriter(output)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="` + excelEscape(options.SheetName) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`},
+	}
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", file.name, err)
+		}
+		if _, err = io.WriteString(writer, file.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	// Style 0 is the default and 1 the bold header, every number format
+	// gets its own style after them
+	styles := make([]int, len(df.Columns))
+	var codes []string
+	for i, series := range df.Columns {
+		code, ok := options.NumberFormats[series.Name]
+		if !ok {
+			continue
+		}
+		position := len(codes)
+		for j, existing := range codes {
+			if existing == code {
+				position = j
+			}
+		}
+		if position == len(codes) {
+			codes = append(codes, code)
+		}
+		styles[i] = position + 2
+	}
+	writer, err := archive.Create("xl/styles.xml")
+	if err != nil {
+		return fmt.Errorf("failed to create styles: %w", err)
+	}
+	if _, err = io.WriteString(writer, excelStyles(codes)); err != nil {
+		return fmt.Errorf("failed to write styles: %w", err)
+	}
+
+	writer, err = archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("failed to create worksheet: %w", err)
+	}
+	if err = df.writeExcelSheet(writer, options, styles); err != nil {
+		return err
+	}
+	if err = archive.Close(); err != nil {
+		return fmt.Errorf("failed to write workbook: %w", err)
+	}
+	return nil
+}
+
+// excelStyles lists the fonts, fills and borders every workbook needs, the
+// bold header style and one style per number format code
+func excelStyles(codes []string) string {
+	var builder strings.Builder
+	builder.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(codes) > 0 {
+		fmt.Fprintf(&builder, `<numFmts count="%d">`, len(codes))
+		for i, code := range codes {
+			// Custom number formats start at 164, lower ids are built in
+			fmt.Fprintf(&builder, `<numFmt numFmtId="%d" formatCode="%s"/>`, 164+i, excelEscape(code))
+		}
+		builder.WriteString(`</numFmts>`)
+	}
+	builder.WriteString(`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>`)
+	builder.WriteString(`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>`)
+	builder.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	builder.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(&builder, `<cellXfs count="%d"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>`, len(codes)+2)
+	for i := range codes {
+		fmt.Fprintf(&builder, `<xf numFmtId="%d" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`, 164+i)
+	}
+	builder.WriteString(`</cellXfs><cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles></styleSheet>`)
+	return builder.String()
+}
+
+func (df *DataFrame) writeExcelSheet(output io.Writer, options ExcelOptions, styles []int) error {
+	writer := bufio.NewWriter(output)
+	length := df.GetLength()
+	config := df.getConfig()
+	letters := make([]string, len(df.Columns))
+	for i := range df.Columns {
+		letters[i] = excelColumn(i)
+	}
+
+	writer.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if options.FreezeHeader {
+		writer.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/><selection pane="bottomLeft"/></sheetView></sheetViews>`)
+	}
+	if options.AutoWidth && len(df.Columns) > 0 {
+		writer.WriteString(`<cols>`)
+		for i := range df.Columns {
+			// Widths are in characters, values are measured as printed with
+			// a margin for the filter button and capped for long texts
+			values := 0
+			for row := 0; row < length; row++ {
+				values = max(values, utf8.RuneCountInString(formatValue(&df.Columns[i], row, config)))
+			}
+			if styles[i] != 0 {
+				// Room for the separators and decimals added by the format
+				values += 4
+			}
+			width := max(utf8.RuneCountInString(df.Columns[i].Name), values)
+			fmt.Fprintf(writer, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, min(width+2, 80))
+		}
+		writer.WriteString(`</cols>`)
+	}
+
+	writer.WriteString(`<sheetData><row r="1">`)
+	for i, series := range df.Columns {
+		fmt.Fprintf(writer, `<c r="%s1" s="1" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, letters[i], excelEscape(series.Name))
+	}
+	writer.WriteString(`</row>`)
+	var number []byte
+	for row := 0; row < length; row++ {
+		fmt.Fprintf(writer, `<row r="%d">`, row+2)
+		for i := range df.Columns {
+			series := &df.Columns[i]
+			if series.DataType == "float" {
+				value := series.Float[row]
+				if math.IsNaN(value) || math.IsInf(value, 0) {
+					continue
+				}
+				number = strconv.AppendFloat(number[:0], value, 'g', -1, 64)
+				fmt.Fprintf(writer, `<c r="%s%d" s="%d"><v>%s</v></c>`, letters[i], row+2, styles[i], number)
+				continue
+			}
+			value := series.GetValueString(row)
+			if value == "NaN" {
+				continue
+			}
+			fmt.Fprintf(writer, `<c r="%s%d" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, letters[i], row+2, styles[i], excelEscape(value))
+		}
+		writer.WriteString(`</row>`)
+	}
+	writer.WriteString(`</sheetData>`)
+
+	priority := 1
+	for i, series := range df.Columns {
+		scale, ok := options.ColorScales[series.Name]
+		if !ok || length == 0 {
+			continue
+		}
+		fmt.Fprintf(writer, `<conditionalFormatting sqref="%s2:%s%d"><cfRule type="colorScale" priority="%d"><colorScale><cfvo type="min"/>`, letters[i], letters[i], length+1, priority)
+		colors := []string{scale.Min, scale.Max}
+		if scale.Mid != "" {
+			writer.WriteString(`<cfvo type="percentile" val="50"/>`)
+			colors = []string{scale.Min, scale.Mid, scale.Max}
+		}
+		writer.WriteString(`<cfvo type="max"/>`)
+		for _, color := range colors {
+			argb, _ := excelColor(color)
+			fmt.Fprintf(writer, `<color rgb="%s"/>`, argb)
+		}
+		writer.WriteString(`</colorScale></cfRule></conditionalFormatting>`)
+		priority++
+	}
+	writer.WriteString(`</worksheet>`)
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write worksheet: %w", err)
+	}
+	return nil
+}
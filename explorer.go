@@ -0,0 +1,218 @@
+package grizzly
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Explorer is an http.Handler serving a small UI to browse the DataFrames
+// registered in it, with paging, sorting by a column, filtering with
+// ParseExpr expressions and CSV download. It is meant for debugging services
+// that hold frames in memory, mount it with
+//
+//	http.Handle("/debug/frames/", http.StripPrefix("/debug/frames", explorer))
+//
+// Frames are read on every request without copying them, so they must not
+// be modified while a request is served.
+type Explorer struct {
+	mutex  sync.RWMutex
+	frames map[string]*DataFrame
+}
+
+// NewExplorer returns an Explorer without frames
+func NewExplorer() *Explorer {
+	return &Explorer{frames: map[string]*DataFrame{}}
+}
+
+// Register makes df available under name, replacing a frame with the same name
+func (explorer *Explorer) Register(name string, df *DataFrame) {
+	explorer.mutex.Lock()
+	defer explorer.mutex.Unlock()
+	explorer.frames[name] = df
+}
+
+// Unregister removes the frame registered under name
+func (explorer *Explorer) Unregister(name string) {
+	explorer.mutex.Lock()
+	defer explorer.mutex.Unlock()
+	delete(explorer.frames, name)
+}
+
+// explorerView is the page of a frame shown by the Explorer
+type explorerView struct {
+	Names      []string
+	Frame      string
+	Filter     string
+	Sort       string
+	Descending bool
+	Error      string
+	Columns    []Series
+	Records    [][]string
+	Rows       int
+	Total      int
+	Page       int
+	Pages      int
+	PageSize   int
+}
+
+// Link returns the query string of the view with some parameters changed
+func (view explorerView) Link(changes ...any) template.URL {
+	values := url.Values{}
+	values.Set("frame", view.Frame)
+	values.Set("filter", view.Filter)
+	values.Set("sort", view.Sort)
+	values.Set("desc", strconv.FormatBool(view.Descending))
+	values.Set("page", strconv.Itoa(view.Page))
+	values.Set("size", strconv.Itoa(view.PageSize))
+	for i := 0; i+1 < len(changes); i += 2 {
+		values.Set(fmt.Sprint(changes[i]), fmt.Sprint(changes[i+1]))
+	}
+	return template.URL("?" + values.Encode())
+}
+
+func (explorer *Explorer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	explorer.mutex.RLock()
+	names := make([]string, 0, len(explorer.frames))
+	for name := range explorer.frames {
+		names = append(names, name)
+	}
+	df, found := explorer.frames[query.Get("frame")]
+	explorer.mutex.RUnlock()
+	sort.Strings(names)
+
+	view := explorerView{Names: names, Frame: query.Get("frame"), Filter: query.Get("filter"), Sort: query.Get("sort"), PageSize: 50}
+	view.Descending, _ = strconv.ParseBool(query.Get("desc"))
+	if size, err := strconv.Atoi(query.Get("size")); err == nil && size > 0 {
+		view.PageSize = min(size, 10000)
+	}
+	view.Page, _ = strconv.Atoi(query.Get("page"))
+	if view.Frame != "" && !found {
+		writer.WriteHeader(http.StatusNotFound)
+		view.Error = fmt.Sprintf("frame %q is not registered", view.Frame)
+	}
+	if !found {
+		explorerPage.Execute(writer, view)
+		return
+	}
+
+	rows, err := explorerRows(df, view.Filter, view.Sort, view.Descending)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		view.Error = err.Error()
+		view.Columns = df.Columns
+		explorerPage.Execute(writer, view)
+		return
+	}
+	if query.Get("format") == "csv" {
+		selected, err := df.SelectRows(rows)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", view.Frame+".csv"))
+		selected.WriteCSV(writer)
+		return
+	}
+
+	view.Total, view.Rows = df.GetLength(), len(rows)
+	view.Pages = max(1, (len(rows)+view.PageSize-1)/view.PageSize)
+	view.Page = max(0, min(view.Page, view.Pages-1))
+	start := view.Page * view.PageSize
+	page, err := df.SelectRows(rows[start:min(start+view.PageSize, len(rows))])
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	view.Columns = df.Columns
+	view.Records = page.TemplateRecords()
+	explorerPage.Execute(writer, view)
+}
+
+// explorerRows returns the rows where filter is true ordered by the column
+// sortBy, every row in order when they are empty
+func explorerRows(df *DataFrame, filter string, sortBy string, descending bool) ([]int, error) {
+	var mask []bool
+	if filter != "" {
+		var err error
+		if mask, err = df.conditionMask(filter); err != nil {
+			return nil, err
+		}
+	}
+	var order []int
+	if sortBy != "" {
+		series, err := df.GetColumnByName(sortBy)
+		if err != nil {
+			return nil, err
+		}
+		if order, err = series.SortIndices(SortOptions{Descending: descending, Stable: true}); err != nil {
+			return nil, err
+		}
+	} else {
+		order = make([]int, df.GetLength())
+		for i := range order {
+			order[i] = i
+		}
+	}
+	if mask == nil {
+		return order, nil
+	}
+	rows := make([]int, 0, len(order))
+	for _, row := range order {
+		if mask[row] {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+var explorerPage = template.Must(template.New("explorer").Funcs(template.FuncMap{
+	"inc": func(value int) int { return value + 1 },
+	"dec": func(value int) int { return value - 1 },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{if .Frame}}{{.Frame}} - {{end}}Grizzly explorer</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+nav a { margin-right: 1em; }
+table { border-collapse: collapse; font-size: 13px; }
+th, td { border: 1px solid #ccc; padding: 2px 6px; }
+th { background: #eee; position: sticky; top: 0; }
+td.float { text-align: right; font-family: monospace; }
+.error { color: #b00; }
+input[name=filter] { width: 40em; }
+</style>
+</head>
+<body>
+<nav>{{range .Names}}<a href="?frame={{.}}">{{.}}</a>{{else}}No frames registered{{end}}</nav>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+{{if .Columns}}
+<h2>{{.Frame}}</h2>
+<form>
+<input type="hidden" name="frame" value="{{.Frame}}">
+<input type="hidden" name="sort" value="{{.Sort}}">
+<input type="hidden" name="desc" value="{{.Descending}}">
+<input type="hidden" name="size" value="{{.PageSize}}">
+<input name="filter" value="{{.Filter}}" placeholder='price > 10 and region == "eu"'>
+<button>Filter</button>
+<a href="{{.Link "format" "csv"}}">Download CSV</a>
+</form>
+{{if not .Error}}<p>{{.Rows}} of {{.Total}} rows, page {{.Page | inc}} of {{.Pages}}
+{{if gt .Page 0}}<a href="{{.Link "page" (.Page | dec)}}">previous</a>{{end}}
+{{if lt (.Page | inc) .Pages}}<a href="{{.Link "page" (.Page | inc)}}">next</a>{{end}}</p>{{end}}
+<table>
+<tr>{{$view := .}}{{range .Columns}}<th><a href="{{$view.Link "sort" .Name "desc" (and (eq $view.Sort .Name) (not $view.Descending)) "page" 0}}">{{.Name}}</a>{{if eq $view.Sort .Name}}{{if $view.Descending}} &#9660;{{else}} &#9650;{{end}}{{end}}<br><small>{{.DataType}}</small></th>{{end}}</tr>
+{{range .Records}}<tr>{{range $i, $value := .}}<td{{if eq (index $view.Columns $i).DataType "float"}} class="float"{{end}}>{{$value}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
@@ -1,10 +1,13 @@
 package grizzly
 
 import (
+	"bufio"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"os"
-	"runtime"
 	"strconv"
 	"sync"
 )
@@ -35,8 +38,11 @@ func (df *DataFrame) ExportToCSV(filePath string) error {
 	}
 
 	// Determine the number of goroutines based on available CPUs
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	chunkSize := (numRows + numGoroutines - 1) / numGoroutines // Ceiling division
+	config := df.getConfig()
+
+	columns := df.decompressedColumns()
 
 	// Channel for errors
 	errorChan := make(chan error)
@@ -47,17 +53,17 @@ func (df *DataFrame) ExportToCSV(filePath string) error {
 		defer wg.Done()
 		for i := start; i < end; i++ {
 			row := make([]string, numCols)
-			for j, col := range df.Columns {
+			for j, col := range columns {
 				switch col.DataType {
 				case "float":
 					if i < len(col.Float) {
-						row[j] = strconv.FormatFloat(col.Float[i], 'f', -1, 64)
+						row[j] = formatValue(&col, i, config)
 					} else {
 						row[j] = ""
 					}
 				case "string":
-					if i < len(col.String) {
-						row[j] = col.String[i]
+					if i < col.GetLength() {
+						row[j] = formatValue(&col, i, config)
 					} else {
 						row[j] = ""
 					}
@@ -105,9 +111,47 @@ func (df *DataFrame) ExportToCSVSimple(filePath string) error {
 	}
 	defer file.Close()
 
+	return df.WriteCSV(file)
+}
+
+// openDestination accepts a file path or an io.Writer, close must be called when done
+func openDestination(destination any) (io.Writer, func() error, error) {
+	switch value := destination.(type) {
+	case string:
+		file, err := os.Create(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create file: %w", err)
+		}
+		return file, file.Close, nil
+	case io.Writer:
+		return value, func() error { return nil }, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported destination type %T, expected a path or an io.Writer", destination)
+}
+
+// WriteCSV writes the header and rows in order to a path or an io.Writer,
+// floats and nulls are written as set in the Config
+func (df *DataFrame) WriteCSV(destination any) error {
+	return df.WriteDelimited(destination, ',')
+}
+
+// WriteDelimited is WriteCSV with a custom field delimiter, like '\t'
+func (df *DataFrame) WriteDelimited(destination any, delimiter rune) error {
+	output, closeDestination, err := openDestination(destination)
+	if err != nil {
+		return err
+	}
+	if err = df.writeDelimited(output, delimiter); err != nil {
+		closeDestination()
+		return err
+	}
+	return closeDestination()
+}
+
+func (df *DataFrame) writeDelimited(output io.Writer, delimiter rune) error {
 	// Initialize CSV writer
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	writer := csv.NewWriter(output)
+	writer.Comma = delimiter
 
 	// Determine the number of rows and columns
 	maxRows := df.GetLength()
@@ -123,21 +167,23 @@ func (df *DataFrame) ExportToCSVSimple(filePath string) error {
 	}
 
 	// Write rows
+	config := df.getConfig()
+	columns := df.decompressedColumns()
 	for i := 0; i < maxRows; i++ {
 		row := make([]string, maxColumns)
-		for j, col := range df.Columns {
+		for j, col := range columns {
 			switch col.DataType {
 			case "float":
 				if i < len(col.Float) {
-					row[j] = strconv.FormatFloat(col.Float[i], 'f', -1, 64)
+					row[j] = formatValue(&col, i, config)
 				} else {
-					row[j] = "NaN"
+					row[j] = config.NullString
 				}
 			case "string":
-				if i < len(col.String) {
-					row[j] = col.String[i]
+				if i < col.GetLength() {
+					row[j] = formatValue(&col, i, config)
 				} else {
-					row[j] = "NaN"
+					row[j] = config.NullString
 				}
 			}
 		}
@@ -148,5 +194,74 @@ func (df *DataFrame) ExportToCSVSimple(filePath string) error {
 
 	}
 
-	return nil
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the rows as a JSON array of objects to a path or an
+// io.Writer, keys in column order. Floats are numbers, NaN values and
+// infinities are null.
+func (df *DataFrame) WriteJSON(destination any) error {
+	output, closeDestination, err := openDestination(destination)
+	if err != nil {
+		return err
+	}
+	if err = df.writeJSON(output); err != nil {
+		closeDestination()
+		return err
+	}
+	return closeDestination()
+}
+
+func (df *DataFrame) writeJSON(output io.Writer) error {
+	keys := make([][]byte, len(df.Columns))
+	for i, series := range df.Columns {
+		name, err := json.Marshal(series.Name)
+		if err != nil {
+			return fmt.Errorf("failed to encode column name %q: %w", series.Name, err)
+		}
+		keys[i] = append(name, ':')
+	}
+	writer := bufio.NewWriter(output)
+	writer.WriteByte('[')
+	var buffer []byte
+	for row := 0; row < df.GetLength(); row++ {
+		buffer = buffer[:0]
+		if row > 0 {
+			buffer = append(buffer, ',')
+		}
+		buffer = append(buffer, '{')
+		for i := range df.Columns {
+			if i > 0 {
+				buffer = append(buffer, ',')
+			}
+			buffer = append(buffer, keys[i]...)
+			series := &df.Columns[i]
+			if series.DataType == "float" {
+				value := series.Float[row]
+				if math.IsNaN(value) || math.IsInf(value, 0) {
+					buffer = append(buffer, "null"...)
+				} else {
+					buffer = strconv.AppendFloat(buffer, value, 'g', -1, 64)
+				}
+				continue
+			}
+			value := series.GetValueString(row)
+			if value == "NaN" {
+				buffer = append(buffer, "null"...)
+				continue
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("failed to encode row %d: %w", row, err)
+			}
+			buffer = append(buffer, encoded...)
+		}
+		buffer = append(buffer, '}')
+		if _, err := writer.Write(buffer); err != nil {
+			return fmt.Errorf("failed to write row %d: %w", row, err)
+		}
+	}
+	writer.WriteByte(']')
+	return writer.Flush()
 }
@@ -0,0 +1,222 @@
+package grizzly
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// featureColumns resolves identifiers to float columns, every float column
+// but exclude when identifiers is empty
+func (df *DataFrame) featureColumns(identifiers []any, exclude string) ([]string, [][]float64, error) {
+	if len(identifiers) == 0 {
+		for _, series := range df.Columns {
+			if series.DataType == "float" && series.Name != exclude {
+				identifiers = append(identifiers, series.Name)
+			}
+		}
+	}
+	names := make([]string, len(identifiers))
+	columns := make([][]float64, len(identifiers))
+	for i, identifier := range identifiers {
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to retrieve feature column %v: %w", identifier, err)
+		}
+		if series.DataType != "float" {
+			return nil, nil, fmt.Errorf("feature column %q is not of type float, encode it first", series.Name)
+		}
+		names[i] = series.Name
+		columns[i] = series.Float
+	}
+	return names, columns, nil
+}
+
+// WriteLibSVM writes the rows to a path or an io.Writer in the sparse libsvm
+// format "label index:value ...", the label is the target column and indexes
+// start at 1 in the order of features. features defaults to every float
+// column but the target. Zero and NaN values are left out, so NaN features
+// are read back as missing. A NaN label is an error.
+func (df *DataFrame) WriteLibSVM(destination any, target any, features ...any) error {
+	label, err := df.GetColumnDynamic(target)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve target column %v: %w", target, err)
+	}
+	if label.DataType != "float" {
+		return fmt.Errorf("target column %q is not of type float, encode it first", label.Name)
+	}
+	_, columns, err := df.featureColumns(features, label.Name)
+	if err != nil {
+		return err
+	}
+
+	output, closeDestination, err := openDestination(destination)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(output)
+	var line []byte
+	for row, value := range label.Float {
+		if math.IsNaN(value) {
+			closeDestination()
+			return fmt.Errorf("target column %q is NaN at row %d", label.Name, row)
+		}
+		line = strconv.AppendFloat(line[:0], value, 'g', -1, 64)
+		for j, column := range columns {
+			if column[row] == 0 || math.IsNaN(column[row]) {
+				continue
+			}
+			line = append(line, ' ')
+			line = strconv.AppendInt(line, int64(j+1), 10)
+			line = append(line, ':')
+			line = strconv.AppendFloat(line, column[row], 'g', -1, 64)
+		}
+		line = append(line, '\n')
+		if _, err = writer.Write(line); err != nil {
+			closeDestination()
+			return fmt.Errorf("failed to write row %d: %w", row, err)
+		}
+	}
+	if err = writer.Flush(); err != nil {
+		closeDestination()
+		return fmt.Errorf("failed to write libsvm data: %w", err)
+	}
+	return closeDestination()
+}
+
+// tensorShape describes the binary written by WriteTensor
+type tensorShape struct {
+	DType   string   `json:"dtype"`
+	Order   string   `json:"order"`
+	Shape   []int    `json:"shape"`
+	Columns []string `json:"columns"`
+}
+
+// WriteTensor writes the columns as a flat row-major array of little endian
+// float32 to data and its description to shape as JSON, like
+// {"dtype":"float32","order":"row-major","shape":[rows,columns],"columns":[...]},
+// so it can be memory mapped by training jobs. Both are paths or io.Writer.
+// columns defaults to every float column, NaN stays NaN.
+func (df *DataFrame) WriteTensor(data any, shape any, columns ...any) error {
+	names, values, err := df.featureColumns(columns, "")
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("tensor requires at least one float column")
+	}
+	length := df.GetLength()
+
+	output, closeData, err := openDestination(data)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(output)
+	buffer := make([]byte, 0, 4*len(values))
+	for row := 0; row < length; row++ {
+		buffer = buffer[:0]
+		for _, column := range values {
+			buffer = binary.LittleEndian.AppendUint32(buffer, math.Float32bits(float32(column[row])))
+		}
+		if _, err = writer.Write(buffer); err != nil {
+			closeData()
+			return fmt.Errorf("failed to write row %d: %w", row, err)
+		}
+	}
+	if err = writer.Flush(); err != nil {
+		closeData()
+		return fmt.Errorf("failed to write tensor data: %w", err)
+	}
+	if err = closeData(); err != nil {
+		return err
+	}
+
+	description, err := json.Marshal(tensorShape{DType: "float32", Order: "row-major", Shape: []int{length, len(values)}, Columns: names})
+	if err != nil {
+		return fmt.Errorf("failed to encode tensor shape: %w", err)
+	}
+	output, closeShape, err := openDestination(shape)
+	if err != nil {
+		return err
+	}
+	if _, err = output.Write(description); err != nil {
+		closeShape()
+		return fmt.Errorf("failed to write tensor shape: %w", err)
+	}
+	return closeShape()
+}
+
+// PredictWith runs model over the feature columns in batches of at most
+// batchSize rows and appends its predictions as the float column name. Every
+// batch holds one []float32 per row with the features in order and model must
+// return one prediction per row, so bindings like an ONNX runtime session can
+// be plugged in directly. features defaults to every float column, NaN
+// features are passed as NaN. Batches run in parallel, so model must be safe
+// for concurrent use.
+func (df *DataFrame) PredictWith(model func(batch [][]float32) []float32, features []any, batchSize int, name string) (err error) {
+	defer df.track("PredictWith", model, features, batchSize, name)(&err)
+	if batchSize <= 0 {
+		return fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+	if isNameRepeated(df.Columns, name) {
+		return fmt.Errorf("cannot add predictions with repeated name: %s", name)
+	}
+	_, columns, err := df.featureColumns(features, "")
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("prediction requires at least one float feature column")
+	}
+
+	length := df.GetLength()
+	numBatches := (length + batchSize - 1) / batchSize
+	predictions := make([]float64, length)
+	errs := make([]error, numBatches)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < minInt(workerCount(), numBatches); g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				index := int(next.Add(1) - 1)
+				if index >= numBatches {
+					return
+				}
+				start := index * batchSize
+				end := minInt(start+batchSize, length)
+				// Rows of a batch share one backing array
+				values := make([]float32, (end-start)*len(columns))
+				batch := make([][]float32, end-start)
+				for i := range batch {
+					batch[i] = values[i*len(columns) : (i+1)*len(columns) : (i+1)*len(columns)]
+					for j, column := range columns {
+						batch[i][j] = float32(column[start+i])
+					}
+				}
+				output := model(batch)
+				if len(output) != len(batch) {
+					errs[index] = fmt.Errorf("model returned %d predictions for a batch of %d rows starting at row %d", len(output), len(batch), start)
+					continue
+				}
+				for i, prediction := range output {
+					predictions[start+i] = float64(prediction)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	df.Columns = append(df.Columns, NewFloatSeries(name, predictions))
+	return nil
+}
@@ -0,0 +1,103 @@
+// Package finance contains financial functions over Grizzly series of prices
+// and returns. Returns are fractions, 0.05 is 5%, and NaN values are skipped.
+package finance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Puchungualotsqui/grizzly"
+)
+
+// floats returns the values of a float series
+func floats(series grizzly.Series) ([]float64, error) {
+	if series.DataType != "float" {
+		return nil, fmt.Errorf("series %q is not a float series", series.Name)
+	}
+	return series.Floats(), nil
+}
+
+// SimpleReturns returns the series "<name>_returns" with the change of every
+// price over the previous one, p[t] / p[t-1] - 1. The first value is NaN.
+func SimpleReturns(prices grizzly.Series) (grizzly.Series, error) {
+	return periodReturns(prices, "_returns", func(previous, current float64) float64 {
+		return current/previous - 1
+	})
+}
+
+// LogReturns returns the series "<name>_log_returns" with the logarithm of
+// the change of every price, log(p[t] / p[t-1]). The first value is NaN.
+func LogReturns(prices grizzly.Series) (grizzly.Series, error) {
+	return periodReturns(prices, "_log_returns", func(previous, current float64) float64 {
+		return math.Log(current / previous)
+	})
+}
+
+func periodReturns(prices grizzly.Series, suffix string, change func(previous, current float64) float64) (grizzly.Series, error) {
+	values, err := floats(prices)
+	if err != nil {
+		return grizzly.Series{}, fmt.Errorf("failed to compute returns: %w", err)
+	}
+	result := make([]float64, len(values))
+	for i := range values {
+		if i == 0 || math.IsNaN(values[i-1]) || math.IsNaN(values[i]) {
+			result[i] = math.NaN()
+			continue
+		}
+		result[i] = change(values[i-1], values[i])
+	}
+	return grizzly.NewFloatSeries(prices.Name+suffix, result), nil
+}
+
+// CumulativeReturns compounds simple returns into the series
+// "<name>_cumulative", the total return up to every row. NaN returns keep
+// the previous total.
+func CumulativeReturns(returns grizzly.Series) (grizzly.Series, error) {
+	values, err := floats(returns)
+	if err != nil {
+		return grizzly.Series{}, fmt.Errorf("failed to compute cumulative returns: %w", err)
+	}
+	result := make([]float64, len(values))
+	growth := 1.0
+	for i, value := range values {
+		if !math.IsNaN(value) {
+			growth *= 1 + value
+		}
+		result[i] = growth - 1
+	}
+	return grizzly.NewFloatSeries(returns.Name+"_cumulative", result), nil
+}
+
+// PeriodsPerYear infers the number of periods in a year from a series of
+// dates, using the median time between consecutive dates. It is the
+// periodsPerYear argument of SharpeRatio and SortinoRatio, about 252 for
+// trading days.
+func PeriodsPerYear(dates grizzly.Series) (float64, error) {
+	df := grizzly.CreateDataFrame(dates)
+	timestamps, err := df.Evaluate(grizzly.Col(dates.Name).Timestamp())
+	if err != nil {
+		return 0, fmt.Errorf("failed to read dates: %w", err)
+	}
+	var gaps []float64
+	values := timestamps.Floats()
+	for i := 1; i < len(values); i++ {
+		gap := values[i] - values[i-1]
+		if !math.IsNaN(gap) && gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) == 0 {
+		return 0, fmt.Errorf("dates must have at least two increasing values")
+	}
+	sort.Float64s(gaps)
+	median := gaps[len(gaps)/2]
+	if len(gaps)%2 == 0 {
+		median = (gaps[len(gaps)/2-1] + median) / 2
+	}
+	if median >= 20*3600 && median <= 28*3600 {
+		// Daily data usually skips weekends and holidays
+		return 252, nil
+	}
+	return 365.25 * 24 * 3600 / median, nil
+}
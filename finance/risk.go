@@ -0,0 +1,158 @@
+package finance
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Puchungualotsqui/grizzly"
+)
+
+// Drawdowns returns the series "<name>_drawdown" with the decline of every
+// price from the highest previous price, 0.2 is 20% below the peak
+func Drawdowns(prices grizzly.Series) (grizzly.Series, error) {
+	values, err := floats(prices)
+	if err != nil {
+		return grizzly.Series{}, fmt.Errorf("failed to compute drawdowns: %w", err)
+	}
+	result := make([]float64, len(values))
+	peak := math.NaN()
+	for i, value := range values {
+		if math.IsNaN(value) {
+			result[i] = math.NaN()
+			continue
+		}
+		if math.IsNaN(peak) || value > peak {
+			peak = value
+		}
+		result[i] = 1 - value/peak
+	}
+	return grizzly.NewFloatSeries(prices.Name+"_drawdown", result), nil
+}
+
+// MaxDrawdown is the largest decline from a peak to a later trough of the
+// prices, as a positive fraction
+func MaxDrawdown(prices grizzly.Series) (float64, error) {
+	drawdowns, err := Drawdowns(prices)
+	if err != nil {
+		return 0, err
+	}
+	maximum := 0.0
+	for _, value := range drawdowns.Floats() {
+		if value > maximum {
+			maximum = value
+		}
+	}
+	return maximum, nil
+}
+
+// SharpeRatio is the mean excess return over riskFree, the risk free return
+// of one period, divided by its standard deviation and annualized with
+// periodsPerYear
+func SharpeRatio(returns grizzly.Series, riskFree float64, periodsPerYear float64) (float64, error) {
+	excess, err := excessReturns(returns, riskFree)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute Sharpe ratio: %w", err)
+	}
+	if len(excess) < 2 {
+		return 0, fmt.Errorf("failed to compute Sharpe ratio: at least two returns are required")
+	}
+	mean := 0.0
+	for _, value := range excess {
+		mean += value
+	}
+	mean /= float64(len(excess))
+	variance := 0.0
+	for _, value := range excess {
+		variance += (value - mean) * (value - mean)
+	}
+	variance /= float64(len(excess) - 1)
+	if variance == 0 {
+		return 0, fmt.Errorf("failed to compute Sharpe ratio: returns have no variance")
+	}
+	return mean / math.Sqrt(variance) * math.Sqrt(periodsPerYear), nil
+}
+
+// SortinoRatio is like SharpeRatio but only penalizes returns below target,
+// dividing the mean excess return by the downside deviation
+func SortinoRatio(returns grizzly.Series, target float64, periodsPerYear float64) (float64, error) {
+	excess, err := excessReturns(returns, target)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute Sortino ratio: %w", err)
+	}
+	if len(excess) == 0 {
+		return 0, fmt.Errorf("failed to compute Sortino ratio: no returns")
+	}
+	mean, downside := 0.0, 0.0
+	for _, value := range excess {
+		mean += value
+		if value < 0 {
+			downside += value * value
+		}
+	}
+	mean /= float64(len(excess))
+	if downside == 0 {
+		return 0, fmt.Errorf("failed to compute Sortino ratio: no returns below the target")
+	}
+	return mean / math.Sqrt(downside/float64(len(excess))) * math.Sqrt(periodsPerYear), nil
+}
+
+func excessReturns(returns grizzly.Series, base float64) ([]float64, error) {
+	values, err := floats(returns)
+	if err != nil {
+		return nil, err
+	}
+	excess := make([]float64, 0, len(values))
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			excess = append(excess, value-base)
+		}
+	}
+	return excess, nil
+}
+
+// RollingBeta returns the series "<name>_beta" with the beta of the returns
+// against the benchmark returns over the last window rows, the covariance
+// divided by the variance of the benchmark. Rows with a NaN in either series
+// are skipped and windows with less than two pairs are NaN.
+func RollingBeta(returns, benchmark grizzly.Series, window int) (grizzly.Series, error) {
+	if window < 2 {
+		return grizzly.Series{}, fmt.Errorf("window must be at least 2, got %d", window)
+	}
+	values, err := floats(returns)
+	if err != nil {
+		return grizzly.Series{}, fmt.Errorf("failed to compute rolling beta: %w", err)
+	}
+	market, err := floats(benchmark)
+	if err != nil {
+		return grizzly.Series{}, fmt.Errorf("failed to compute rolling beta: %w", err)
+	}
+	if len(values) != len(market) {
+		return grizzly.Series{}, fmt.Errorf("returns have %d rows but the benchmark has %d", len(values), len(market))
+	}
+	// Running sums over the valid pairs of the window
+	var n, sumX, sumY, sumXY, sumXX float64
+	update := func(i int, sign float64) {
+		if math.IsNaN(values[i]) || math.IsNaN(market[i]) {
+			return
+		}
+		n += sign
+		sumX += sign * market[i]
+		sumY += sign * values[i]
+		sumXY += sign * market[i] * values[i]
+		sumXX += sign * market[i] * market[i]
+	}
+	result := make([]float64, len(values))
+	for i := range values {
+		update(i, 1)
+		if i >= window {
+			update(i-window, -1)
+		}
+		variance := sumXX - sumX*sumX/n
+		if i < window-1 || n < 2 || variance <= 0 {
+			result[i] = math.NaN()
+			continue
+		}
+		result[i] = (sumXY - sumX*sumY/n) / variance
+	}
+	return grizzly.NewFloatSeries(returns.Name+"_beta", result), nil
+}
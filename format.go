@@ -0,0 +1,141 @@
+package grizzly
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FormatReader parses a DataFrame from a stream
+type FormatReader interface {
+	Read(input io.Reader) (DataFrame, error)
+}
+
+// FormatWriter serializes a DataFrame to a stream
+type FormatWriter interface {
+	Write(output io.Writer, df *DataFrame) error
+}
+
+// ReaderFunc adapts a function to FormatReader
+type ReaderFunc func(input io.Reader) (DataFrame, error)
+
+func (read ReaderFunc) Read(input io.Reader) (DataFrame, error) {
+	return read(input)
+}
+
+// WriterFunc adapts a function to FormatWriter
+type WriterFunc func(output io.Writer, df *DataFrame) error
+
+func (write WriterFunc) Write(output io.Writer, df *DataFrame) error {
+	return write(output, df)
+}
+
+type format struct {
+	reader FormatReader
+	writer FormatWriter
+}
+
+var (
+	formatsMutex sync.RWMutex
+	formats      = map[string]format{
+		"csv": {
+			reader: ReaderFunc(func(input io.Reader) (DataFrame, error) { return ReadCSV(input) }),
+			writer: WriterFunc(func(output io.Writer, df *DataFrame) error { return df.WriteCSV(output) }),
+		},
+		"tsv": {
+			reader: ReaderFunc(func(input io.Reader) (DataFrame, error) { return ReadDelimited(input, '\t') }),
+			writer: WriterFunc(func(output io.Writer, df *DataFrame) error { return df.WriteDelimited(output, '\t') }),
+		},
+		"json": {
+			writer: WriterFunc(func(output io.Writer, df *DataFrame) error { return df.WriteJSON(output) }),
+		},
+	}
+)
+
+func normalizeExtension(extension string) string {
+	return strings.ToLower(strings.TrimPrefix(extension, "."))
+}
+
+// RegisterFormat makes a file extension available to Read and Write, either
+// reader or writer may be nil for read-only or write-only formats. A format
+// registered again replaces the previous one.
+func RegisterFormat(extension string, reader FormatReader, writer FormatWriter) error {
+	extension = normalizeExtension(extension)
+	if extension == "" {
+		return fmt.Errorf("format extension can not be empty")
+	}
+	if reader == nil && writer == nil {
+		return fmt.Errorf("format %q needs a reader or a writer", extension)
+	}
+	formatsMutex.Lock()
+	defer formatsMutex.Unlock()
+	formats[extension] = format{reader: reader, writer: writer}
+	return nil
+}
+
+// GetFormats returns the registered extensions
+func GetFormats() []string {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+	extensions := make([]string, 0, len(formats))
+	for extension := range formats {
+		extensions = append(extensions, extension)
+	}
+	return ParallelSortString(extensions)
+}
+
+func getFormat(path string) (format, string, error) {
+	extension := normalizeExtension(filepath.Ext(path))
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+	registered, ok := formats[extension]
+	if !ok {
+		return format{}, extension, fmt.Errorf("no format registered for extension %q", extension)
+	}
+	return registered, extension, nil
+}
+
+// Read opens path with the format registered for its extension
+func Read(path string) (DataFrame, error) {
+	registered, extension, err := getFormat(path)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	if registered.reader == nil {
+		return DataFrame{}, fmt.Errorf("format %q can not be read", extension)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	df, err := registered.reader.Read(file)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+	return df, nil
+}
+
+// Write saves df to path with the format registered for its extension
+func (df *DataFrame) Write(path string) error {
+	registered, extension, err := getFormat(path)
+	if err != nil {
+		return err
+	}
+	if registered.writer == nil {
+		return fmt.Errorf("format %q can not be written", extension)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if err = registered.writer.Write(file, df); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %q: %w", path, err)
+	}
+	return file.Close()
+}
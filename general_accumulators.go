@@ -0,0 +1,307 @@
+package grizzly
+
+import (
+	"math"
+	"sort"
+)
+
+// Accumulators compute statistics over values received incrementally, one
+// batch at a time, and Merge partial results computed on other goroutines
+// or shards. They skip NaN values like the aggregations and their zero
+// value is ready to use. An accumulator must not be used from several
+// goroutines at once.
+
+// MeanAcc accumulates the mean of values
+type MeanAcc struct {
+	state momentState
+}
+
+// Add includes values in the mean
+func (acc *MeanAcc) Add(values ...float64) {
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			acc.state.add(value)
+		}
+	}
+}
+
+// Merge includes the values accumulated by other
+func (acc *MeanAcc) Merge(other *MeanAcc) {
+	acc.state.merge(other.state)
+}
+
+// Count returns the number of values added
+func (acc *MeanAcc) Count() int {
+	return int(acc.state.n)
+}
+
+// Result returns the mean, NaN when no value was added
+func (acc *MeanAcc) Result() float64 {
+	if acc.state.n == 0 {
+		return math.NaN()
+	}
+	return acc.state.mean
+}
+
+// VarAcc accumulates the population variance of values, with their count
+// and mean
+type VarAcc struct {
+	state momentState
+}
+
+// Add includes values in the variance
+func (acc *VarAcc) Add(values ...float64) {
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			acc.state.add(value)
+		}
+	}
+}
+
+// Merge includes the values accumulated by other
+func (acc *VarAcc) Merge(other *VarAcc) {
+	acc.state.merge(other.state)
+}
+
+// Count returns the number of values added
+func (acc *VarAcc) Count() int {
+	return int(acc.state.n)
+}
+
+// Mean returns the mean, NaN when no value was added
+func (acc *VarAcc) Mean() float64 {
+	if acc.state.n == 0 {
+		return math.NaN()
+	}
+	return acc.state.mean
+}
+
+// Result returns the population variance, NaN when no value was added
+func (acc *VarAcc) Result() float64 {
+	if acc.state.n == 0 {
+		return math.NaN()
+	}
+	return acc.state.m2 / acc.state.n
+}
+
+// Std returns the population standard deviation
+func (acc *VarAcc) Std() float64 {
+	return math.Sqrt(acc.Result())
+}
+
+// MinMaxAcc accumulates the smallest and the largest value
+type MinMaxAcc struct {
+	count    int
+	min, max float64
+}
+
+// Add includes values in the range
+func (acc *MinMaxAcc) Add(values ...float64) {
+	for _, value := range values {
+		if math.IsNaN(value) {
+			continue
+		}
+		if acc.count == 0 || value < acc.min {
+			acc.min = value
+		}
+		if acc.count == 0 || value > acc.max {
+			acc.max = value
+		}
+		acc.count++
+	}
+}
+
+// Merge includes the values accumulated by other
+func (acc *MinMaxAcc) Merge(other *MinMaxAcc) {
+	if other.count == 0 {
+		return
+	}
+	if acc.count == 0 {
+		*acc = *other
+		return
+	}
+	acc.min = math.Min(acc.min, other.min)
+	acc.max = math.Max(acc.max, other.max)
+	acc.count += other.count
+}
+
+// Count returns the number of values added
+func (acc *MinMaxAcc) Count() int {
+	return acc.count
+}
+
+// Result returns the smallest and the largest value, NaN when no value was
+// added
+func (acc *MinMaxAcc) Result() (float64, float64) {
+	if acc.count == 0 {
+		return math.NaN(), math.NaN()
+	}
+	return acc.min, acc.max
+}
+
+// centroid is a group of values of a QuantileSketch summarized by their mean
+type centroid struct {
+	mean, weight float64
+}
+
+// QuantileSketch estimates quantiles in bounded memory with a merging
+// t-digest: values are grouped in centroids that are small near the tails
+// and larger near the median, so extreme quantiles stay accurate.
+// Compression bounds the number of centroids, 100 when it is 0. Until about
+// Compression values are added every value is kept and quantiles are exact.
+type QuantileSketch struct {
+	Compression float64
+
+	centroids []centroid
+	buffer    []centroid
+	count     float64
+	min, max  float64
+}
+
+// NewQuantileSketch returns a sketch with the given compression, higher
+// values are more accurate and use more memory
+func NewQuantileSketch(compression float64) *QuantileSketch {
+	return &QuantileSketch{Compression: compression}
+}
+
+func (sketch *QuantileSketch) compression() float64 {
+	if sketch.Compression <= 0 {
+		return 100
+	}
+	return sketch.Compression
+}
+
+// Add includes values in the sketch
+func (sketch *QuantileSketch) Add(values ...float64) {
+	for _, value := range values {
+		if math.IsNaN(value) {
+			continue
+		}
+		sketch.include(centroid{value, 1})
+	}
+}
+
+func (sketch *QuantileSketch) include(point centroid) {
+	if sketch.count == 0 || point.mean < sketch.min {
+		sketch.min = point.mean
+	}
+	if sketch.count == 0 || point.mean > sketch.max {
+		sketch.max = point.mean
+	}
+	sketch.count += point.weight
+	sketch.buffer = append(sketch.buffer, point)
+	if len(sketch.buffer) >= int(5*sketch.compression()) {
+		sketch.compress()
+	}
+}
+
+// Merge includes the values summarized by other
+func (sketch *QuantileSketch) Merge(other *QuantileSketch) {
+	min, max := sketch.min, sketch.max
+	empty := sketch.count == 0
+	for _, points := range [][]centroid{other.centroids, other.buffer} {
+		for _, point := range points {
+			sketch.include(point)
+		}
+	}
+	// The extremes of other may be inside its centroids
+	if other.count > 0 {
+		sketch.min, sketch.max = other.min, other.max
+		if !empty {
+			sketch.min, sketch.max = math.Min(min, other.min), math.Max(max, other.max)
+		}
+	}
+}
+
+// compress merges the buffer into the centroids. Neighbouring centroids are
+// combined while the weight of the result stays under the size allowed at
+// its quantile, 4 n q (1 - q) / compression.
+func (sketch *QuantileSketch) compress() {
+	if len(sketch.buffer) == 0 {
+		return
+	}
+	points := append(sketch.centroids, sketch.buffer...)
+	sort.Slice(points, func(i, j int) bool { return points[i].mean < points[j].mean })
+	merged := make([]centroid, 0, len(points))
+	current := points[0]
+	before := 0.0
+	for _, point := range points[1:] {
+		weight := current.weight + point.weight
+		q := (before + weight/2) / sketch.count
+		if weight <= math.Max(1, 4*sketch.count*q*(1-q)/sketch.compression()) {
+			current.mean += (point.mean - current.mean) * point.weight / weight
+			current.weight = weight
+			continue
+		}
+		merged = append(merged, current)
+		before += current.weight
+		current = point
+	}
+	sketch.centroids = append(merged, current)
+	sketch.buffer = sketch.buffer[:0]
+}
+
+// state encodes the sketch as its count, min and max followed by the mean
+// and weight of every centroid, the state of the approx_median aggregation
+func (sketch *QuantileSketch) state() []float64 {
+	sketch.compress()
+	state := make([]float64, 0, 3+2*len(sketch.centroids))
+	state = append(state, sketch.count, sketch.min, sketch.max)
+	for _, point := range sketch.centroids {
+		state = append(state, point.mean, point.weight)
+	}
+	return state
+}
+
+// sketchFromState decodes a state written by QuantileSketch.state
+func sketchFromState(state []float64) *QuantileSketch {
+	sketch := &QuantileSketch{count: state[0], min: state[1], max: state[2]}
+	for i := 3; i+1 < len(state); i += 2 {
+		sketch.centroids = append(sketch.centroids, centroid{state[i], state[i+1]})
+	}
+	return sketch
+}
+
+// validSketchState checks the layout of a state written by
+// QuantileSketch.state
+func validSketchState(state []float64) bool {
+	return len(state) >= 3 && (len(state)-3)%2 == 0
+}
+
+// Count returns the number of values added
+func (sketch *QuantileSketch) Count() int {
+	return int(sketch.count)
+}
+
+// Result returns the estimated quantile q between 0 and 1, interpolated
+// between the centers of the centroids, NaN when no value was added
+func (sketch *QuantileSketch) Result(q float64) float64 {
+	if sketch.count == 0 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	sketch.compress()
+	q = math.Max(0, math.Min(1, q))
+	// Positions are counted like indexes of the sorted values, a centroid
+	// is centered in the positions of its values
+	position := q * (sketch.count - 1)
+	previousPosition, previousValue := 0.0, sketch.min
+	start := 0.0
+	for _, centroid := range sketch.centroids {
+		center := start + (centroid.weight-1)/2
+		if position <= center {
+			if center == previousPosition {
+				return centroid.mean
+			}
+			weight := (position - previousPosition) / (center - previousPosition)
+			return previousValue + (centroid.mean-previousValue)*weight
+		}
+		previousPosition, previousValue = center, centroid.mean
+		start += centroid.weight
+	}
+	last := sketch.count - 1
+	if last == previousPosition {
+		return sketch.max
+	}
+	weight := (position - previousPosition) / (last - previousPosition)
+	return previousValue + (sketch.max-previousValue)*weight
+}
@@ -0,0 +1,270 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Aggregation reduces float values in parallel. Partial turns a chunk of
+// values into a state, Merge combines two states and Final returns the
+// result. NaN values are removed before Partial is called. Valid reports
+// whether a state received from another process, see MergePartials, can be
+// merged; nil accepts every state.
+type Aggregation struct {
+	Partial func(values []float64) []float64
+	Merge   func(left, right []float64) []float64
+	Final   func(state []float64) float64
+	Valid   func(state []float64) bool
+}
+
+// stateWidth returns a Valid function accepting states of width floats
+func stateWidth(width int) func(state []float64) bool {
+	return func(state []float64) bool {
+		return len(state) == width
+	}
+}
+
+var (
+	aggregationsMutex sync.RWMutex
+	aggregations      = map[string]Aggregation{}
+)
+
+// aggregationParallelThreshold is the minimum number of values to split the
+// partial states across goroutines
+const aggregationParallelThreshold = 1 << 14
+
+func init() {
+	scalar := func(reduce func(values []float64) float64, merge func(a, b float64) float64) Aggregation {
+		return Aggregation{
+			Partial: func(values []float64) []float64 { return []float64{reduce(values)} },
+			Merge:   func(left, right []float64) []float64 { return []float64{merge(left[0], right[0])} },
+			Final:   func(state []float64) float64 { return state[0] },
+			Valid:   stateWidth(1),
+		}
+	}
+	add := func(a, b float64) float64 { return a + b }
+
+	aggregations["sum"] = scalar(func(values []float64) float64 {
+		var sum float64
+		for _, value := range values {
+			sum += value
+		}
+		return sum
+	}, add)
+	aggregations["count"] = scalar(func(values []float64) float64 { return float64(len(values)) }, add)
+	aggregations["product"] = scalar(func(values []float64) float64 {
+		product := 1.0
+		for _, value := range values {
+			product *= value
+		}
+		return product
+	}, func(a, b float64) float64 { return a * b })
+	aggregations["min"] = scalar(func(values []float64) float64 {
+		result := math.NaN()
+		for _, value := range values {
+			if math.IsNaN(result) || value < result {
+				result = value
+			}
+		}
+		return result
+	}, func(a, b float64) float64 {
+		if math.IsNaN(a) || b < a {
+			return b
+		}
+		return a
+	})
+	aggregations["max"] = scalar(func(values []float64) float64 {
+		result := math.NaN()
+		for _, value := range values {
+			if math.IsNaN(result) || value > result {
+				result = value
+			}
+		}
+		return result
+	}, func(a, b float64) float64 {
+		if math.IsNaN(a) || b > a {
+			return b
+		}
+		return a
+	})
+
+	moments := func(values []float64) []float64 {
+		var state momentState
+		for _, value := range values {
+			state.add(value)
+		}
+		return []float64{state.n, state.mean, state.m2}
+	}
+	mergeMoments := func(left, right []float64) []float64 {
+		state := momentState{left[0], left[1], left[2]}
+		state.merge(momentState{right[0], right[1], right[2]})
+		return []float64{state.n, state.mean, state.m2}
+	}
+	aggregations["mean"] = Aggregation{Partial: moments, Merge: mergeMoments, Valid: stateWidth(3), Final: func(state []float64) float64 {
+		if state[0] == 0 {
+			return math.NaN()
+		}
+		return state[1]
+	}}
+	aggregations["variance"] = Aggregation{Partial: moments, Merge: mergeMoments, Valid: stateWidth(3), Final: func(state []float64) float64 {
+		if state[0] == 0 {
+			return math.NaN()
+		}
+		return state[2] / state[0]
+	}}
+	aggregations["std"] = Aggregation{Partial: moments, Merge: mergeMoments, Valid: stateWidth(3), Final: func(state []float64) float64 {
+		if state[0] == 0 {
+			return math.NaN()
+		}
+		return math.Sqrt(state[2] / state[0])
+	}}
+	aggregations["median"] = valuesAggregation(func(values []float64) float64 {
+		if len(values) == 0 {
+			return math.NaN()
+		}
+		return arrayMedian(values)
+	})
+	// approx_median keeps a QuantileSketch instead of every value, so its
+	// partial states have a bounded size
+	aggregations["approx_median"] = Aggregation{
+		Partial: func(values []float64) []float64 {
+			var sketch QuantileSketch
+			sketch.Add(values...)
+			return sketch.state()
+		},
+		Merge: func(left, right []float64) []float64 {
+			sketch := sketchFromState(left)
+			sketch.Merge(sketchFromState(right))
+			return sketch.state()
+		},
+		Final: func(state []float64) float64 { return sketchFromState(state).Result(0.5) },
+		Valid: validSketchState,
+	}
+}
+
+// momentState holds the count, mean and sum of squared differences of some
+// values, updated with Welford's method and merged with Chan's formula
+type momentState struct {
+	n, mean, m2 float64
+}
+
+func (state *momentState) add(value float64) {
+	state.n++
+	delta := value - state.mean
+	state.mean += delta / state.n
+	state.m2 += delta * (value - state.mean)
+}
+
+func (state *momentState) merge(other momentState) {
+	n := state.n + other.n
+	if n == 0 {
+		return
+	}
+	delta := other.mean - state.mean
+	state.mean += delta * other.n / n
+	state.m2 += other.m2 + delta*delta*state.n*other.n/n
+	state.n = n
+}
+
+// valuesAggregation keeps every value as state, used for reducers that can
+// not be split
+func valuesAggregation(aggregate func(values []float64) float64) Aggregation {
+	return Aggregation{
+		Partial: func(values []float64) []float64 { return append([]float64{}, values...) },
+		Merge:   func(left, right []float64) []float64 { return append(left, right...) },
+		Final:   aggregate,
+	}
+}
+
+// RegisterAggregation makes a custom reducer available by name in
+// GroupBy.Agg and Describe. aggregate receives the values without NaN.
+func RegisterAggregation(name string, aggregate func(values []float64) float64) error {
+	if aggregate == nil {
+		return fmt.Errorf("aggregation %q has no function", name)
+	}
+	return RegisterParallelAggregation(name, valuesAggregation(aggregate))
+}
+
+// RegisterParallelAggregation registers a reducer with partial and merge
+// steps so large inputs are reduced across goroutines
+func RegisterParallelAggregation(name string, aggregation Aggregation) error {
+	if name == "" {
+		return fmt.Errorf("aggregation name can not be empty")
+	}
+	if aggregation.Partial == nil || aggregation.Merge == nil || aggregation.Final == nil {
+		return fmt.Errorf("aggregation %q needs Partial, Merge and Final functions", name)
+	}
+	aggregationsMutex.Lock()
+	defer aggregationsMutex.Unlock()
+	if _, exists := aggregations[name]; exists {
+		return fmt.Errorf("aggregation %q is already registered", name)
+	}
+	aggregations[name] = aggregation
+	return nil
+}
+
+// GetAggregationNames returns the names of the registered aggregations
+func GetAggregationNames() []string {
+	aggregationsMutex.RLock()
+	defer aggregationsMutex.RUnlock()
+	names := make([]string, 0, len(aggregations))
+	for name := range aggregations {
+		names = append(names, name)
+	}
+	return ParallelSortString(names)
+}
+
+func getAggregation(name string) (Aggregation, error) {
+	aggregationsMutex.RLock()
+	defer aggregationsMutex.RUnlock()
+	aggregation, ok := aggregations[name]
+	if !ok {
+		return Aggregation{}, fmt.Errorf("unknown aggregation %q", name)
+	}
+	return aggregation, nil
+}
+
+// arrayAggregate drops NaN values and reduces the rest, splitting big inputs
+// into partial states computed in parallel
+func arrayAggregate(aggregation Aggregation, data []float64) float64 {
+	values := make([]float64, 0, len(data))
+	for _, value := range data {
+		if !math.IsNaN(value) {
+			values = append(values, value)
+		}
+	}
+
+	length := len(values)
+	if length < aggregationParallelThreshold {
+		return aggregation.Final(aggregation.Partial(values))
+	}
+
+	// With Config.Deterministic the states cover fixed blocks merged in block
+	// order, so the result does not depend on the number of workers
+	var partials [][]float64
+	if GetConfig().Deterministic {
+		partials = make([][]float64, (length+deterministicBlock-1)/deterministicBlock)
+		parallelBlocks(length, func(block, start, end int) {
+			partials[block] = aggregation.Partial(values[start:end])
+		})
+	} else {
+		partials = make([][]float64, workerCount())
+		parallelChunks(length, func(chunk, start, end int) {
+			partials[chunk] = aggregation.Partial(values[start:end])
+		})
+	}
+
+	var state []float64
+	for _, partial := range partials {
+		if partial == nil {
+			continue
+		}
+		if state == nil {
+			state = partial
+		} else {
+			state = aggregation.Merge(state, partial)
+		}
+	}
+	return aggregation.Final(state)
+}
@@ -0,0 +1,123 @@
+package grizzly
+
+// ahoCorasick finds every occurrence of a set of strings in a single pass
+// over the text, whatever the number of strings
+type ahoCorasick struct {
+	// next holds the transitions of every state, missing ones are resolved
+	// through the failure links when the automaton is built
+	next    []map[byte]int
+	fail    []int
+	pattern []int // longest pattern ending at the state, or -1
+	output  []int // nearest state on the failure chain with a pattern, or -1
+	lengths []int
+}
+
+func newAhoCorasick(patterns []string) *ahoCorasick {
+	automaton := &ahoCorasick{lengths: make([]int, len(patterns))}
+	automaton.addState()
+	for i, pattern := range patterns {
+		automaton.lengths[i] = len(pattern)
+		state := 0
+		for j := 0; j < len(pattern); j++ {
+			next, ok := automaton.next[state][pattern[j]]
+			if !ok {
+				next = automaton.addState()
+				automaton.next[state][pattern[j]] = next
+			}
+			state = next
+		}
+		automaton.pattern[state] = i
+	}
+
+	// Breadth first, the failure of a state is the longest proper suffix
+	// that is also a prefix of some pattern
+	queue := []int{}
+	for _, child := range automaton.next[0] {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		if automaton.pattern[automaton.fail[state]] >= 0 {
+			automaton.output[state] = automaton.fail[state]
+		} else {
+			automaton.output[state] = automaton.output[automaton.fail[state]]
+		}
+		for symbol, child := range automaton.next[state] {
+			fallback := automaton.fail[state]
+			for {
+				if target, ok := automaton.next[fallback][symbol]; ok && target != child {
+					automaton.fail[child] = target
+					break
+				}
+				if fallback == 0 {
+					automaton.fail[child] = 0
+					break
+				}
+				fallback = automaton.fail[fallback]
+			}
+			queue = append(queue, child)
+		}
+	}
+	return automaton
+}
+
+func (automaton *ahoCorasick) addState() int {
+	automaton.next = append(automaton.next, map[byte]int{})
+	automaton.fail = append(automaton.fail, 0)
+	automaton.pattern = append(automaton.pattern, -1)
+	automaton.output = append(automaton.output, -1)
+	return len(automaton.next) - 1
+}
+
+func (automaton *ahoCorasick) step(state int, symbol byte) int {
+	for {
+		if next, ok := automaton.next[state][symbol]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = automaton.fail[state]
+	}
+}
+
+// replace substitutes the leftmost longest non overlapping occurrences of
+// the patterns with their replacements
+func (automaton *ahoCorasick) replace(text string, replacements []string) string {
+	// Longest pattern starting at every position, found by the end of every
+	// occurrence
+	var longest map[int]int
+	state := 0
+	for i := 0; i < len(text); i++ {
+		state = automaton.step(state, text[i])
+		for match := state; match > 0; match = automaton.output[match] {
+			pattern := automaton.pattern[match]
+			if pattern < 0 {
+				continue
+			}
+			start := i + 1 - automaton.lengths[pattern]
+			if longest == nil {
+				longest = map[int]int{}
+			}
+			if current, ok := longest[start]; !ok || automaton.lengths[pattern] > automaton.lengths[current] {
+				longest[start] = pattern
+			}
+		}
+	}
+	if longest == nil {
+		return text
+	}
+
+	result := make([]byte, 0, len(text))
+	for i := 0; i < len(text); {
+		if pattern, ok := longest[i]; ok {
+			result = append(result, replacements[pattern]...)
+			i += automaton.lengths[pattern]
+			continue
+		}
+		result = append(result, text[i])
+		i++
+	}
+	return string(result)
+}
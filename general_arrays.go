@@ -2,96 +2,112 @@ package grizzly
 
 import (
 	"math"
-	"runtime"
 	"strconv"
 	"sync"
+	"sync/atomic"
 )
 
-func arrayFloatBase(initValue float64, data []float64, operation func(info float64, result float64) float64) chan float64 {
-	length := len(data)
-	if length == 0 {
-		// Handle empty data case by returning a closed channel immediately
-		emptyChan := make(chan float64)
-		close(emptyChan)
-		return emptyChan
-	}
-	numGoroutines := runtime.NumCPU()
-	if numGoroutines > length {
-		numGoroutines = length // Avoid creating more goroutines than necessary
-	}
+// deterministicBlock is the number of values folded into every partial result
+// when Config.Deterministic is set, independent of the number of workers
+const deterministicBlock = 4096
+
+// parallelChunks splits length rows in one chunk per worker and calls
+// operation for every chunk in its own Goroutine, returning when all are
+// done. chunk numbers the chunks from 0 in row order.
+func parallelChunks(length int, operation func(chunk, start, end int)) {
+	numGoroutines := workerCount()
 	chunkSize := (length + numGoroutines - 1) / numGoroutines
 	var wg sync.WaitGroup
-	resultChan := make(chan float64, numGoroutines)
-
-	// Function to calculate the sum of a chunk
-	worker := func(start, end int) {
-		defer wg.Done()
-		result := initValue
-		// Always starts from second value to calculate Mean Correctly
-		for i := start; i < end; i++ {
-			if !math.IsNaN(data[i]) {
-				result = operation(data[i], result)
-			}
-		}
-		resultChan <- result
-	}
 
-	// Launch goroutines to process chunks
 	for i := 0; i < numGoroutines; i++ {
 		start := i * chunkSize
 		end := start + chunkSize
+		if start >= length {
+			break
+		}
 		if end > length {
 			end = length
 		}
+
 		wg.Add(1)
-		go worker(start, end)
+		go func(chunk, start, end int) {
+			defer wg.Done()
+			operation(chunk, start, end)
+		}(i, start, end)
 	}
+	wg.Wait()
+}
 
-	// Wait for all workers to finish and close the results channel
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
-
-	return resultChan
+// parallelBlocks splits length rows in blocks of deterministicBlock rows and
+// calls operation for every block on the workers, returning when all are
+// done. block numbers the blocks from 0 in row order, so the split does not
+// depend on the number of workers.
+func parallelBlocks(length int, operation func(block, start, end int)) {
+	blocks := (length + deterministicBlock - 1) / deterministicBlock
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < min(workerCount(), blocks); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for block := int(next.Add(1)) - 1; block < blocks; block = int(next.Add(1)) - 1 {
+				start := block * deterministicBlock
+				operation(block, start, min(start+deterministicBlock, length))
+			}
+		}()
+	}
+	wg.Wait()
 }
 
-func arrayStringBase(initValue float64, data []string, operation func(info string, result float64) float64) chan float64 {
-	length := len(data)
+// chunkPartials folds length values chunk by chunk in parallel and returns
+// the partial results. The chunks are one per worker and the partials arrive
+// as they finish, unless Config.Deterministic is set: then the chunks have a
+// fixed size and the partials arrive in the order of the chunks, so combining
+// them gives the same bits on every run and machine.
+func chunkPartials(length int, fold func(start, end int) float64) chan float64 {
 	if length == 0 {
 		// Handle empty data case by returning a closed channel immediately
 		emptyChan := make(chan float64)
 		close(emptyChan)
 		return emptyChan
 	}
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	if numGoroutines > length {
 		numGoroutines = length // Avoid creating more goroutines than necessary
 	}
-	chunkSize := (length + numGoroutines - 1) / numGoroutines
-	var wg sync.WaitGroup
-	resultChan := make(chan float64, numGoroutines)
 
-	// Function to calculate the sum of a chunk
-	worker := func(start, end int) {
-		defer wg.Done()
-		result := initValue
-		// Always starts from second value to calculate Mean Correctly
-		for i := start; i < end; i++ {
-			result = operation(data[i], result)
+	if GetConfig().Deterministic {
+		partials := make([]float64, (length+deterministicBlock-1)/deterministicBlock)
+		parallelBlocks(length, func(block, start, end int) {
+			partials[block] = fold(start, end)
+		})
+		resultChan := make(chan float64, len(partials))
+		for _, partial := range partials {
+			resultChan <- partial
 		}
-		resultChan <- result
+		close(resultChan)
+		return resultChan
 	}
 
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	resultChan := make(chan float64, numGoroutines)
+
 	// Launch goroutines to process chunks
 	for i := 0; i < numGoroutines; i++ {
 		start := i * chunkSize
 		end := start + chunkSize
+		if start >= length {
+			break
+		}
 		if end > length {
 			end = length
 		}
 		wg.Add(1)
-		go worker(start, end)
+		go func(start, end int) {
+			defer wg.Done()
+			resultChan <- fold(start, end)
+		}(start, end)
 	}
 
 	// Wait for all workers to finish and close the results channel
@@ -103,8 +119,32 @@ func arrayStringBase(initValue float64, data []string, operation func(info strin
 	return resultChan
 }
 
+func arrayFloatBase(initValue float64, data []float64, operation func(info float64, result float64) float64) chan float64 {
+	// Function to calculate the result of a chunk
+	return chunkPartials(len(data), func(start, end int) float64 {
+		result := initValue
+		for i := start; i < end; i++ {
+			if !math.IsNaN(data[i]) {
+				result = operation(data[i], result)
+			}
+		}
+		return result
+	})
+}
+
+func arrayStringBase(initValue float64, data []string, operation func(info string, result float64) float64) chan float64 {
+	// Function to calculate the result of a chunk
+	return chunkPartials(len(data), func(start, end int) float64 {
+		result := initValue
+		for i := start; i < end; i++ {
+			result = operation(data[i], result)
+		}
+		return result
+	})
+}
+
 func arrayStringCountWord(data []string, word string) float64 {
-	CPUNumbers := runtime.NumCPU()
+	CPUNumbers := workerCount()
 
 	chunkSize := len(data) / CPUNumbers
 	if len(data)%CPUNumbers != 0 {
@@ -150,7 +190,7 @@ func arrayStringCountWord(data []string, word string) float64 {
 }
 
 func arrayFloatCountValue(data []float64, value float64) float64 {
-	CPUNumbers := runtime.NumCPU()
+	CPUNumbers := workerCount()
 
 	chunkSize := len(data) / CPUNumbers
 	if len(data)%CPUNumbers != 0 {
@@ -196,7 +236,7 @@ func arrayFloatCountValue(data []float64, value float64) float64 {
 }
 
 func arrayFloatCountNaNValue(data []float64) float64 {
-	CPUNumbers := runtime.NumCPU()
+	CPUNumbers := workerCount()
 
 	chunkSize := len(data) / CPUNumbers
 	if len(data)%CPUNumbers != 0 {
@@ -242,7 +282,7 @@ func arrayFloatCountNaNValue(data []float64) float64 {
 }
 
 func arrayCountFloatDuplicates(elements []float64) map[float64]int {
-	numCPU := runtime.NumCPU()
+	numCPU := workerCount()
 	length := len(elements)
 	if length == 0 {
 		return nil // Handle empty input
@@ -317,14 +357,14 @@ func arrayContainsString(arr []string, target string) bool {
 	return false // Element not found
 }
 
-// arrayGetNonFloatValues identifies non-convertible float values using goroutines
+// arrayGetNonFloatValues identifies non-convertible float values using
+// goroutines, in the order of the input
 func arrayGetNonFloatValues(input []string) []string {
-	numGoroutines := runtime.NumCPU() // Number of goroutines to use
+	numGoroutines := workerCount() // Number of goroutines to use
 	chunkSize := (len(input) + numGoroutines - 1) / numGoroutines
 
 	var wg sync.WaitGroup
-	var mu sync.Mutex // Protects shared access to `nonConvertible`
-	var nonConvertible []string
+	chunks := make([][]string, numGoroutines) // Results of every chunk, merged in order
 
 	// Launch goroutines to process chunks
 	for g := 0; g < numGoroutines; g++ {
@@ -338,24 +378,21 @@ func arrayGetNonFloatValues(input []string) []string {
 		}
 
 		wg.Add(1)
-		go func(start, end int) {
+		go func(chunk, start, end int) {
 			defer wg.Done()
-			localNonConvertible := []string{} // Local slice to collect results
-
 			for _, str := range input[start:end] {
 				if _, err := strconv.ParseFloat(str, 64); err != nil {
-					localNonConvertible = append(localNonConvertible, str)
+					chunks[chunk] = append(chunks[chunk], str)
 				}
 			}
-
-			// Append results to the shared slice
-			mu.Lock()
-			nonConvertible = append(nonConvertible, localNonConvertible...)
-			mu.Unlock()
-		}(start, end)
+		}(g, start, end)
 	}
 
 	wg.Wait()
+	var nonConvertible []string
+	for _, chunk := range chunks {
+		nonConvertible = append(nonConvertible, chunk...)
+	}
 	return nonConvertible
 }
 
@@ -377,8 +414,11 @@ func arrayUniqueValuesFloat(arr []float64) []float64 {
 	if len(arr) == 0 {
 		return []float64{}
 	}
+	if GetConfig().Deterministic {
+		return arrayUniqueValuesOrdered(arr)
+	}
 
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	if numGoroutines > len(arr) {
 		numGoroutines = len(arr) // Avoid spawning more goroutines than necessary
 	}
@@ -437,8 +477,11 @@ func arrayUniqueValuesString(arr []string) []string {
 	if len(arr) == 0 {
 		return []string{}
 	}
+	if GetConfig().Deterministic {
+		return arrayUniqueValuesOrdered(arr)
+	}
 
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	if numGoroutines > len(arr) {
 		numGoroutines = len(arr) // Avoid spawning more goroutines than necessary
 	}
@@ -493,6 +536,54 @@ func arrayUniqueValuesString(arr []string) []string {
 	return uniqueValues
 }
 
+// arrayUniqueValuesOrdered returns the unique values in the order they first
+// appear, the chunks are merged in order
+func arrayUniqueValuesOrdered[T comparable](arr []T) []T {
+	numGoroutines := workerCount()
+	if numGoroutines > len(arr) {
+		numGoroutines = len(arr) // Avoid spawning more goroutines than necessary
+	}
+	chunkSize := (len(arr) + numGoroutines - 1) / numGoroutines
+
+	chunks := make([][]T, numGoroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < numGoroutines; i++ {
+		start := i * chunkSize
+		end := start + chunkSize
+		if start >= len(arr) {
+			break
+		}
+		if end > len(arr) {
+			end = len(arr)
+		}
+
+		wg.Add(1)
+		go func(chunk int, data []T) {
+			defer wg.Done()
+			seen := make(map[T]struct{})
+			for _, val := range data {
+				if _, ok := seen[val]; !ok {
+					seen[val] = struct{}{}
+					chunks[chunk] = append(chunks[chunk], val)
+				}
+			}
+		}(i, arr[start:end])
+	}
+	wg.Wait()
+
+	seen := make(map[T]struct{})
+	uniqueValues := []T{}
+	for _, chunk := range chunks {
+		for _, val := range chunk {
+			if _, ok := seen[val]; !ok {
+				seen[val] = struct{}{}
+				uniqueValues = append(uniqueValues, val)
+			}
+		}
+	}
+	return uniqueValues
+}
+
 func oneHotEncode(data []string) ([]float64, map[float64]string) {
 	arrayUniqueValuesString(data)
 	// Create a map to hold indices and categories
@@ -523,3 +614,13 @@ func oneHotEncode(data []string) ([]float64, map[float64]string) {
 
 	return encoded, indexToCategory
 }
+
+// arrayApplyString replaces every value with the result of operation, in
+// parallel chunks
+func arrayApplyString(data []string, operation func(string) string) {
+	parallelChunks(len(data), func(_, start, end int) {
+		for j := start; j < end; j++ {
+			data[j] = operation(data[j])
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package grizzly
+
+import "math"
+
+// radixSortThreshold is the length from which float columns are sorted with
+// radix sort instead of a comparison sort. BenchmarkRadixSort against
+// BenchmarkSortFloat64s puts the crossover between 1024 and 2048 values, the
+// threshold keeps a margin above it.
+const radixSortThreshold = 1 << 12
+
+// floatKey maps a float to an unsigned key with the same order, negative
+// numbers have every bit flipped and positive numbers only the sign bit
+func floatKey(value float64) uint64 {
+	if value == 0 {
+		value = 0 // -0 and 0 are equal
+	}
+	bits := math.Float64bits(value)
+	if bits>>63 == 1 {
+		return ^bits
+	}
+	return bits | 1<<63
+}
+
+func floatFromKey(key uint64) float64 {
+	if key>>63 == 1 {
+		return math.Float64frombits(key &^ (1 << 63))
+	}
+	return math.Float64frombits(^key)
+}
+
+// radixSort sorts keys with a stable least significant digit radix sort of
+// one byte per pass, indices (when not nil) are moved along with the keys.
+// Passes where every key has the same digit are skipped.
+func radixSort(keys []uint64, indices []int) {
+	n := len(keys)
+	if n <= 1 {
+		return
+	}
+	buffer := uint64Pool.get(n)
+	defer uint64Pool.put(buffer)
+	source, destination := keys, buffer
+	var sourceIndices, destinationIndices []int
+	if indices != nil {
+		indexBuffer := intPool.get(n)
+		defer intPool.put(indexBuffer)
+		sourceIndices, destinationIndices = indices, indexBuffer
+	}
+
+	for shift := 0; shift < 64; shift += 8 {
+		var offsets [256]int
+		for _, key := range source {
+			offsets[(key>>shift)&0xFF]++
+		}
+		if offsets[(source[0]>>shift)&0xFF] == n {
+			continue
+		}
+		position := 0
+		for digit, count := range offsets {
+			offsets[digit] = position
+			position += count
+		}
+		for i, key := range source {
+			digit := (key >> shift) & 0xFF
+			destination[offsets[digit]] = key
+			if indices != nil {
+				destinationIndices[offsets[digit]] = sourceIndices[i]
+			}
+			offsets[digit]++
+		}
+		source, destination = destination, source
+		sourceIndices, destinationIndices = destinationIndices, sourceIndices
+	}
+
+	// After an odd number of passes the result is in the buffer
+	if &source[0] != &keys[0] {
+		copy(keys, source)
+		if indices != nil {
+			copy(indices, sourceIndices)
+		}
+	}
+}
+
+// RadixSortFloat sorts arr in place in linear time, NaN values go first
+func RadixSortFloat(arr []float64) []float64 {
+	keys := make([]uint64, 0, len(arr))
+	nulls := 0
+	for _, value := range arr {
+		if math.IsNaN(value) {
+			nulls++
+			continue
+		}
+		keys = append(keys, floatKey(value))
+	}
+	radixSort(keys, nil)
+	for i := 0; i < nulls; i++ {
+		arr[i] = math.NaN()
+	}
+	for i, key := range keys {
+		arr[nulls+i] = floatFromKey(key)
+	}
+	return arr
+}
+
+// RadixSortInt64 sorts arr in place in linear time
+func RadixSortInt64(arr []int64) []int64 {
+	keys := make([]uint64, len(arr))
+	for i, value := range arr {
+		keys[i] = uint64(value) ^ 1<<63
+	}
+	radixSort(keys, nil)
+	for i, key := range keys {
+		arr[i] = int64(key ^ 1<<63)
+	}
+	return arr
+}
+
+// radixSortIndices returns the stable permutation that sorts a float series,
+// nulls go last unless nullsFirst is set
+func radixSortIndices(values []float64, descending, nullsFirst bool) []int {
+	keys := uint64Pool.get(len(values))[:0]
+	defer uint64Pool.put(keys)
+	// The indices are returned to the pool by the callers that only use
+	// them to reorder the rows
+	indices := intPool.get(len(values))[:0]
+	var nulls []int
+	for i, value := range values {
+		if math.IsNaN(value) {
+			nulls = append(nulls, i)
+			continue
+		}
+		key := floatKey(value)
+		if descending {
+			key = ^key
+		}
+		keys = append(keys, key)
+		indices = append(indices, i)
+	}
+	radixSort(keys, indices)
+	if nullsFirst {
+		return append(nulls, indices...)
+	}
+	return append(indices, nulls...)
+}
@@ -0,0 +1,47 @@
+package grizzly
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// The sizes around radixSortThreshold show where RadixSortFloat starts to
+// beat sort.Float64s
+var radixBenchmarkSizes = []int{1 << 10, 1 << 11, radixSortThreshold, 1 << 15, 1 << 18}
+
+func randomFloats(n int) []float64 {
+	random := rand.New(rand.NewSource(1))
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = random.NormFloat64() * 1000
+	}
+	return values
+}
+
+func BenchmarkRadixSort(b *testing.B) {
+	for _, size := range radixBenchmarkSizes {
+		values := randomFloats(size)
+		scratch := make([]float64, size)
+		b.Run(fmt.Sprintf("n=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(scratch, values)
+				RadixSortFloat(scratch)
+			}
+		})
+	}
+}
+
+func BenchmarkSortFloat64s(b *testing.B) {
+	for _, size := range radixBenchmarkSizes {
+		values := randomFloats(size)
+		scratch := make([]float64, size)
+		b.Run(fmt.Sprintf("n=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(scratch, values)
+				sort.Float64s(scratch)
+			}
+		})
+	}
+}
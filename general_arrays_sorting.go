@@ -1,25 +1,32 @@
 package grizzly
 
 import (
-	"math"
-	"runtime"
-	"sort"
+	"cmp"
+	"slices"
 	"sync"
 )
 
-func ParallelSortFloat(arr []float64) []float64 {
+// parallelSortMinChunk is the smallest chunk sorted in its own Goroutine,
+// shorter slices are sorted without starting any (see the benchmarks in
+// general_arrays_sorting_test.go)
+const parallelSortMinChunk = 1 << 11
+
+// ParallelSort sorts arr with a stable merge sort, chunks are sorted in
+// separate Goroutines and merged in pairs, also in parallel. NaN values sort
+// first. The sorted slice is returned and arr is used as scratch space.
+func ParallelSort[T cmp.Ordered](arr []T) []T {
 	n := len(arr)
 	if n <= 1 {
 		return arr
 	}
 
-	numCPUs := runtime.NumCPU()
-	chunkSize := int(math.Ceil(float64(n) / float64(numCPUs)))
-
-	// Channel to collect sorted chunks
-	chunks := make(chan []float64, numCPUs)
-
-	// Use a WaitGroup to synchronize goroutines
+	numCPUs := min(workerCount(), n/parallelSortMinChunk)
+	if numCPUs <= 1 {
+		slices.Sort(arr)
+		return arr
+	}
+	chunkSize := (n + numCPUs - 1) / numCPUs
+	var chunks [][]T
 	var wg sync.WaitGroup
 
 	for i := 0; i < numCPUs; i++ {
@@ -31,112 +38,66 @@ func ParallelSortFloat(arr []float64) []float64 {
 		if end > n {
 			end = n // Ensure we don't go out of bounds
 		}
+		chunks = append(chunks, arr[start:end])
 
 		wg.Add(1)
-
 		// Sort each chunk in a separate Goroutine
-		go func(subarray []float64) {
+		go func(subarray []T) {
 			defer wg.Done()
-			sort.Float64s(subarray) // Sort the chunk
-			chunks <- subarray      // Send it to the channel
+			// Equal ordered values can not be told apart, so the chunks
+			// do not need a stable sort
+			slices.Sort(subarray)
 		}(arr[start:end])
 	}
-
-	// Wait for all Goroutines to finish
-	go func() {
+	wg.Wait()
+
+	// Merge neighbouring chunks until one is left, keeping their order so
+	// equal values stay in place
+	for len(chunks) > 1 {
+		merged := make([][]T, (len(chunks)+1)/2)
+		for i := 0; i < len(chunks); i += 2 {
+			if i+1 == len(chunks) {
+				merged[i/2] = chunks[i]
+				continue
+			}
+			wg.Add(1)
+			go func(position int, left, right []T) {
+				defer wg.Done()
+				merged[position] = mergeSorted(left, right)
+			}(i/2, chunks[i], chunks[i+1])
+		}
 		wg.Wait()
-		close(chunks)
-	}()
-
-	// Collect and merge sorted chunks
-	sortedResult := make([]float64, 0, n)
-	for sortedChunk := range chunks {
-		sortedResult = mergeFloat(sortedResult, sortedChunk)
+		chunks = merged
 	}
-
-	return sortedResult
+	return chunks[0]
 }
 
-func mergeFloat(left, right []float64) []float64 {
-	result := make([]float64, 0, len(left)+len(right))
-	i, j := 0, 0
-	for i < len(left) && j < len(right) {
-		if left[i] < right[j] {
-			result = append(result, left[i])
-			i++
-		} else {
-			result = append(result, right[j])
-			j++
-		}
+// ParallelSortFloat sorts a float slice in parallel, see ParallelSort. Large
+// slices are sorted with RadixSortFloat.
+func ParallelSortFloat(arr []float64) []float64 {
+	if len(arr) >= radixSortThreshold {
+		return RadixSortFloat(arr)
 	}
-	// Append any remaining elements
-	result = append(result, left[i:]...)
-	result = append(result, right[j:]...)
-	return result
+	return ParallelSort(arr)
 }
 
-// parallelSortString performs a parallel sort on an array of strings.
-func parallelSortString(arr []string) []string {
-	n := len(arr)
-	if n <= 1 {
-		return arr // Already sorted
-	}
-
-	numCPUs := runtime.NumCPU()
-	chunkSize := int(math.Ceil(float64(n) / float64(numCPUs)))
-
-	// Channel to collect sorted chunks
-	chunks := make(chan []string, numCPUs)
-
-	// Use a WaitGroup to synchronize Goroutines
-	var wg sync.WaitGroup
-
-	for i := 0; i < numCPUs; i++ {
-		start := i * chunkSize
-		end := start + chunkSize
-		if start >= n {
-			break // Avoid processing if start is beyond the array length
-		}
-		if end > n {
-			end = n // Ensure we don't go out of bounds
-		}
-
-		wg.Add(1)
-
-		// Sort each chunk in a separate Goroutine
-		go func(subarray []string) {
-			defer wg.Done()
-			sort.Strings(subarray) // Sort the chunk
-			chunks <- subarray     // Send the sorted chunk to the channel
-		}(arr[start:end])
-	}
-
-	// Wait for all Goroutines to finish
-	go func() {
-		wg.Wait()
-		close(chunks)
-	}()
-
-	// Collect and merge sorted chunks
-	sortedResult := make([]string, 0, n)
-	for sortedChunk := range chunks {
-		sortedResult = mergeString(sortedResult, sortedChunk)
-	}
-
-	return sortedResult
+// ParallelSortString sorts a string slice in parallel, see ParallelSort
+func ParallelSortString(arr []string) []string {
+	return ParallelSort(arr)
 }
 
-// mergeString merges two sorted string slices into one sorted slice.
-func mergeString(left, right []string) []string {
-	result := make([]string, 0, len(left)+len(right))
+// mergeSorted merges two sorted slices into a new one, taking the left value
+// on ties
+func mergeSorted[T cmp.Ordered](left, right []T) []T {
+	result := make([]T, 0, len(left)+len(right))
 	i, j := 0, 0
 	for i < len(left) && j < len(right) {
-		if left[i] < right[j] {
-			result = append(result, left[i])
-			i++
-		} else {
+		if cmp.Less(right[j], left[i]) {
 			result = append(result, right[j])
 			j++
+		} else {
+			result = append(result, left[i])
+			i++
 		}
 	}
 	// Append any remaining elements
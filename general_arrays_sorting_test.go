@@ -0,0 +1,46 @@
+package grizzly
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+var sortBenchmarkSizes = []int{64, 1 << 10, 1 << 12, 1 << 14, 1 << 17}
+
+func randomStrings(n int) []string {
+	random := rand.New(rand.NewSource(1))
+	values := make([]string, n)
+	for i := range values {
+		values[i] = strconv.Itoa(random.Int())
+	}
+	return values
+}
+
+func BenchmarkParallelSortString(b *testing.B) {
+	for _, size := range sortBenchmarkSizes {
+		values := randomStrings(size)
+		scratch := make([]string, size)
+		b.Run(fmt.Sprintf("n=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(scratch, values)
+				ParallelSortString(scratch)
+			}
+		})
+	}
+}
+
+func BenchmarkSlicesSortString(b *testing.B) {
+	for _, size := range sortBenchmarkSizes {
+		values := randomStrings(size)
+		scratch := make([]string, size)
+		b.Run(fmt.Sprintf("n=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(scratch, values)
+				slices.Sort(scratch)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package grizzly
+
+import (
+	"math"
+	"sync"
+)
+
+// bloomFilter is a fixed size probabilistic set. It never reports false
+// negatives, so it can be used to reject keys before an exact lookup.
+type bloomFilter struct {
+	bits   []uint64
+	size   uint64
+	hashes uint64
+}
+
+func newBloomFilter(expected int, falsePositiveRate float64) *bloomFilter {
+	if expected < 1 {
+		expected = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+	size := uint64(math.Ceil(-float64(expected) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if size < 64 {
+		size = 64
+	}
+	hashes := uint64(math.Round(float64(size) / float64(expected) * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+	return &bloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+func (b *bloomFilter) add(h1, h2 uint64) {
+	for i := uint64(0); i < b.hashes; i++ {
+		pos := (h1 + i*h2) % b.size
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+func (b *bloomFilter) mayContain(h1, h2 uint64) bool {
+	for i := uint64(0); i < b.hashes; i++ {
+		pos := (h1 + i*h2) % b.size
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// union merges a filter built with the same parameters into b
+func (b *bloomFilter) union(other *bloomFilter) {
+	for i := range b.bits {
+		b.bits[i] |= other.bits[i]
+	}
+}
+
+func (b *bloomFilter) mayContainString(value string) bool {
+	h1, h2 := bloomHashString(value)
+	return b.mayContain(h1, h2)
+}
+
+func (b *bloomFilter) mayContainFloat(value float64) bool {
+	h1, h2 := bloomHashFloat(value)
+	return b.mayContain(h1, h2)
+}
+
+// bloomHashString hashes with FNV-1a without allocating
+func bloomHashString(value string) (uint64, uint64) {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(value); i++ {
+		h ^= uint64(value[i])
+		h *= 1099511628211
+	}
+	return h, bloomMix(h) | 1
+}
+
+// bloomHashFloat hashes equal numbers alike: -0 and 0 compare equal and
+// every NaN has the same bits
+func bloomHashFloat(value float64) (uint64, uint64) {
+	switch {
+	case value == 0:
+		value = 0
+	case math.IsNaN(value):
+		value = math.NaN()
+	}
+	h := bloomMix(math.Float64bits(value))
+	return h, bloomMix(h) | 1
+}
+
+// bloomMix is the splitmix64 finalizer
+func bloomMix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func buildBloomFilterString(data []string, falsePositiveRate float64) *bloomFilter {
+	length := len(data)
+	result := newBloomFilter(length, falsePositiveRate)
+	if length == 0 {
+		return result
+	}
+	numGoroutines := workerCount()
+	if numGoroutines > length {
+		numGoroutines = length
+	}
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	partials := make([]*bloomFilter, numGoroutines)
+	var wg sync.WaitGroup
+
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end, g int) {
+			defer wg.Done()
+			local := newBloomFilter(length, falsePositiveRate)
+			for i := start; i < end; i++ {
+				local.add(bloomHashString(data[i]))
+			}
+			partials[g] = local
+		}(start, end, g)
+	}
+	wg.Wait()
+
+	for _, partial := range partials {
+		result.union(partial)
+	}
+	return result
+}
+
+func buildBloomFilterFloat(data []float64, falsePositiveRate float64) *bloomFilter {
+	length := len(data)
+	result := newBloomFilter(length, falsePositiveRate)
+	if length == 0 {
+		return result
+	}
+	numGoroutines := workerCount()
+	if numGoroutines > length {
+		numGoroutines = length
+	}
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	partials := make([]*bloomFilter, numGoroutines)
+	var wg sync.WaitGroup
+
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end, g int) {
+			defer wg.Done()
+			local := newBloomFilter(length, falsePositiveRate)
+			for i := start; i < end; i++ {
+				local.add(bloomHashFloat(data[i]))
+			}
+			partials[g] = local
+		}(start, end, g)
+	}
+	wg.Wait()
+
+	for _, partial := range partials {
+		result.union(partial)
+	}
+	return result
+}
@@ -0,0 +1,195 @@
+package grizzly
+
+import (
+	"math"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// NoDecimals is the FloatPrecision that writes floats rounded to whole
+// numbers
+const NoDecimals = -1
+
+// Config holds the package wide settings. SetConfig changes them for every
+// DataFrame, DataFrame.SetConfig overrides them for one DataFrame and
+// DataFrame.WithConfig for one call. A zero field means the default, or the
+// package setting in an override, so partial literals can be used.
+type Config struct {
+	// FloatPrecision is the number of decimals when floats are printed or
+	// exported, 0 writes the shortest exact representation and NoDecimals
+	// rounds to whole numbers
+	FloatPrecision int
+	// MaxWorkers limits the goroutines of every parallel operation, 0 uses
+	// one per CPU. It is only read from the package config.
+	MaxWorkers int
+	// NullString is written for nulls when printing and exporting, "NaN"
+	// when empty
+	NullString string
+	// NullValues are the tokens read as nulls by the readers and
+	// ConvertStringToFloat, "NaN" is always a null. A nil slice uses the
+	// default tokens and an empty one only "NaN".
+	NullValues []string
+	// PanicOnError makes DataFrame transformations, reads, joins and group
+	// bys panic with their error instead of returning it
+	PanicOnError bool
+	// InternStrings makes the CSV, fixed-width, log and Avro readers intern
+	// the values of string columns so repeated values share memory, see
+	// Series.Intern. It is only read from the package config.
+	InternStrings bool
+	// Deterministic makes parallel operations return the same result on
+	// every run whatever MaxWorkers is: float reductions and aggregations
+	// fold fixed size blocks merged in order and collected values keep the
+	// order of the input. It is only read from the package config.
+	Deterministic bool
+	// MemoryLimit is the memory in bytes that SortWith and Merge may use
+	// for their row numbers, above it they keep them in temporary files.
+	// 0 sets no limit. It is only read from the package config.
+	MemoryLimit int
+	// TempDir is the directory of the temporary files of MemoryLimit, the
+	// default temporary directory when empty. It is only read from the
+	// package config.
+	TempDir string
+}
+
+// DefaultConfig returns the settings used when none are set
+func DefaultConfig() Config {
+	return Config{
+		NullString: "NaN",
+		NullValues: []string{"", "NA", "N/A", "null", "-", "?"},
+	}
+}
+
+// merge returns config with its zero fields taken from base
+func (config Config) merge(base Config) Config {
+	if config.FloatPrecision == 0 {
+		config.FloatPrecision = base.FloatPrecision
+	}
+	if config.MaxWorkers == 0 {
+		config.MaxWorkers = base.MaxWorkers
+	}
+	if config.NullString == "" {
+		config.NullString = base.NullString
+	}
+	if config.NullValues == nil {
+		config.NullValues = base.NullValues
+	}
+	if config.MemoryLimit == 0 {
+		config.MemoryLimit = base.MemoryLimit
+	}
+	if config.TempDir == "" {
+		config.TempDir = base.TempDir
+	}
+	config.PanicOnError = config.PanicOnError || base.PanicOnError
+	config.InternStrings = config.InternStrings || base.InternStrings
+	config.Deterministic = config.Deterministic || base.Deterministic
+	return config
+}
+
+var (
+	configMutex   sync.RWMutex
+	packageConfig = DefaultConfig()
+)
+
+// SetConfig replaces the package settings, zero fields take the defaults
+func SetConfig(config Config) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	config = config.merge(DefaultConfig())
+	config.NullValues = append([]string{}, config.NullValues...)
+	packageConfig = config
+}
+
+// GetConfig returns the package settings
+func GetConfig() Config {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+	return packageConfig
+}
+
+// SetConfig overrides the package settings for this DataFrame, zero fields
+// keep the package setting. Copy keeps the override.
+func (df *DataFrame) SetConfig(config Config) {
+	if config.NullValues != nil {
+		config.NullValues = append([]string{}, config.NullValues...)
+	}
+	df.config = &config
+}
+
+// WithConfig returns a copy of the DataFrame with config overriding its
+// settings, for a single call such as df.WithConfig(config).WriteCSV(path).
+// The copy shares the column values until one of them is modified.
+func (df *DataFrame) WithConfig(config Config) DataFrame {
+	result := df.Copy()
+	if df.config != nil {
+		config = config.merge(*df.config)
+	}
+	result.SetConfig(config)
+	return result
+}
+
+// ResetConfig makes the DataFrame use the package settings again
+func (df *DataFrame) ResetConfig() {
+	df.config = nil
+}
+
+func (df *DataFrame) getConfig() Config {
+	if df.config != nil {
+		return df.config.merge(GetConfig())
+	}
+	return GetConfig()
+}
+
+// workerCount is the number of goroutines a parallel operation starts
+func workerCount() int {
+	if workers := GetConfig().MaxWorkers; workers > 0 {
+		return workers
+	}
+	return runtime.NumCPU()
+}
+
+// memoryLimit is the memory an operation may hold before spilling to disk,
+// 0 when there is no limit
+func memoryLimit() int {
+	return max(GetConfig().MemoryLimit, 0)
+}
+
+// formatValue writes a value for printing and exporting with the precision
+// and null string of config
+func formatValue(series *Series, index int, config Config) string {
+	if series.DataType == "float" {
+		value := series.Float[index]
+		if math.IsNaN(value) {
+			return config.NullString
+		}
+		precision := config.FloatPrecision
+		switch {
+		case precision == 0:
+			precision = -1
+		case precision < 0:
+			precision = 0
+		}
+		return strconv.FormatFloat(value, 'f', precision, 64)
+	}
+	value := series.GetValueString(index)
+	if value == "NaN" {
+		return config.NullString
+	}
+	return value
+}
+
+// nullValues returns the set of tokens read as nulls
+func (config Config) nullValues() map[string]bool {
+	nulls := map[string]bool{"NaN": true}
+	for _, value := range config.NullValues {
+		nulls[value] = true
+	}
+	return nulls
+}
+
+// panicOnError panics with err when config asks for it
+func panicOnError(err error, config Config) {
+	if err != nil && config.PanicOnError {
+		panic(err)
+	}
+}
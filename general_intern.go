@@ -0,0 +1,131 @@
+package grizzly
+
+import (
+	"hash/maphash"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Interned strings are kept in a package wide pool so identical values of
+// every series share one backing array. The pool is split in shards to keep
+// parallel readers from waiting on a single lock. It only grows, ResetIntern
+// releases it.
+const internShardCount = 64
+
+type internShard struct {
+	mutex  sync.Mutex
+	values map[string]string
+}
+
+var (
+	internShards [internShardCount]internShard
+	internSeed   = maphash.MakeSeed()
+
+	internLookups atomic.Int64
+	internHits    atomic.Int64
+	internSaved   atomic.Int64
+)
+
+// intern returns the pooled string equal to value, adding value when it is
+// new
+func intern(value string) string {
+	internLookups.Add(1)
+	shard := &internShards[maphash.String(internSeed, value)%internShardCount]
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	if pooled, ok := shard.values[value]; ok {
+		internHits.Add(1)
+		internSaved.Add(int64(len(value)))
+		return pooled
+	}
+	if shard.values == nil {
+		shard.values = make(map[string]string)
+	}
+	// Readers often slice values out of a whole line, the clone keeps the
+	// pool from holding the line
+	value = strings.Clone(value)
+	shard.values[value] = value
+	return value
+}
+
+// Intern replaces the values of a string series with pooled copies so
+// identical strings across every interned series share memory. Compressed
+// and float series are left unchanged.
+func (series *Series) Intern() {
+	if series.DataType != "string" || series.compressed != nil {
+		return
+	}
+	values := series.String
+	length := len(values)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := minInt(start+chunkSize, length)
+		if start >= length {
+			break
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			// The values stay equal, so views sharing the slice are not affected
+			for i := start; i < end; i++ {
+				values[i] = intern(values[i])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// InternStrings interns every string column, see Series.Intern. Readers do
+// it automatically when the Config has InternStrings set.
+func (df *DataFrame) InternStrings() {
+	for i := range df.Columns {
+		df.Columns[i].Intern()
+	}
+}
+
+// internOnRead interns the string columns of a DataFrame just read when the
+// package Config asks for it
+func (df *DataFrame) internOnRead() {
+	if GetConfig().InternStrings {
+		df.InternStrings()
+	}
+}
+
+// InternStats describes the string pool, Unique is the number of distinct
+// pooled strings, Hits the lookups that found an existing string and
+// BytesSaved the bytes of string data those hits no longer hold
+type InternStats struct {
+	Unique     int
+	Lookups    int64
+	Hits       int64
+	BytesSaved int64
+}
+
+// GetInternStats returns the state of the string pool since the start or the
+// last ResetIntern
+func GetInternStats() InternStats {
+	stats := InternStats{Lookups: internLookups.Load(), Hits: internHits.Load(), BytesSaved: internSaved.Load()}
+	for i := range internShards {
+		internShards[i].mutex.Lock()
+		stats.Unique += len(internShards[i].values)
+		internShards[i].mutex.Unlock()
+	}
+	return stats
+}
+
+// ResetIntern empties the string pool and its stats. Series interned before
+// keep their values.
+func ResetIntern() {
+	for i := range internShards {
+		internShards[i].mutex.Lock()
+		internShards[i].values = nil
+		internShards[i].mutex.Unlock()
+	}
+	internLookups.Store(0)
+	internHits.Store(0)
+	internSaved.Store(0)
+}
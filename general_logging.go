@@ -0,0 +1,96 @@
+package grizzly
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Logger receives warnings and operation records, *slog.Logger satisfies it
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// Span is one traced operation, adapters can forward it to OpenTelemetry
+type Span interface {
+	SetAttributes(args ...any)
+	End(err error)
+}
+
+// Tracer starts a span for every major operation: "parse", "join" and "groupby"
+type Tracer interface {
+	Start(operation string) Span
+}
+
+var (
+	loggingMutex sync.RWMutex
+	logger       Logger = slog.Default()
+	tracer       Tracer
+)
+
+// SetLogger replaces the logger, nil discards every record
+func SetLogger(newLogger Logger) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	if newLogger == nil {
+		newLogger = discardLogger{}
+	}
+	logger = newLogger
+}
+
+// SetTracer installs the tracer, nil disables tracing
+func SetTracer(newTracer Tracer) {
+	loggingMutex.Lock()
+	defer loggingMutex.Unlock()
+	tracer = newTracer
+}
+
+func getLogger() Logger {
+	loggingMutex.RLock()
+	defer loggingMutex.RUnlock()
+	return logger
+}
+
+type discardLogger struct{}
+
+func (discardLogger) Debug(string, ...any) {}
+func (discardLogger) Info(string, ...any)  {}
+func (discardLogger) Warn(string, ...any)  {}
+func (discardLogger) Error(string, ...any) {}
+
+type operationSpan struct {
+	operation string
+	start     time.Time
+	span      Span
+}
+
+func startSpan(operation string) *operationSpan {
+	loggingMutex.RLock()
+	current := tracer
+	loggingMutex.RUnlock()
+	span := &operationSpan{operation: operation, start: time.Now()}
+	if current != nil {
+		span.span = current.Start(operation)
+	}
+	return span
+}
+
+// end closes the span and logs the operation with its duration, attributes
+// are key value pairs like "rows", 100
+func (span *operationSpan) end(err error, attributes ...any) {
+	duration := time.Since(span.start)
+	attributes = append(attributes, "duration", duration)
+	if span.span != nil {
+		span.span.SetAttributes(attributes...)
+		span.span.End(err)
+	}
+	if err != nil {
+		// The error is returned to the caller, only record it
+		attributes = append(attributes, "error", err)
+	}
+	getLogger().Debug("grizzly operation", append([]any{"operation", span.operation}, attributes...)...)
+	panicOnError(err, GetConfig())
+}
@@ -0,0 +1,84 @@
+package grizzly
+
+import (
+	"math"
+	"sort"
+)
+
+// matrixSymmetricEigen diagonalizes a symmetric matrix with the cyclic Jacobi
+// method. Eigenvalues are returned in descending order and eigenvectors as
+// the columns of the second result.
+func matrixSymmetricEigen(matrix [][]float64) ([]float64, [][]float64) {
+	n := len(matrix)
+	a := make([][]float64, n)
+	vectors := make([][]float64, n)
+	for i := range matrix {
+		a[i] = append([]float64{}, matrix[i]...)
+		vectors[i] = make([]float64, n)
+		vectors[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		var offDiagonal float64
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				offDiagonal += a[p][q] * a[p][q]
+			}
+		}
+		if offDiagonal < 1e-22 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if math.Abs(a[p][q]) < 1e-300 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp := a[k][p]
+					akq := a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk := a[p][k]
+					aqk := a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp := vectors[k][p]
+					vkq := vectors[k][q]
+					vectors[k][p] = c*vkp - s*vkq
+					vectors[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return a[order[i]][order[i]] > a[order[j]][order[j]]
+	})
+	values := make([]float64, n)
+	sorted := make([][]float64, n)
+	for i := range sorted {
+		sorted[i] = make([]float64, n)
+	}
+	for column, source := range order {
+		values[column] = a[source][source]
+		for row := 0; row < n; row++ {
+			sorted[row][column] = vectors[row][source]
+		}
+	}
+	return values, sorted
+}
@@ -0,0 +1,84 @@
+package grizzly
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// Temporary buffers of filters and sorts are reused through size classed
+// pools to reduce the garbage collector load of long running servers. Every
+// class holds buffers with a capacity of a power of two.
+const (
+	minPoolClass = 6
+	maxPoolClass = 24
+)
+
+type bufferPool[T any] struct {
+	classes [maxPoolClass - minPoolClass + 1]sync.Pool
+}
+
+var (
+	intPool    bufferPool[int]
+	uint64Pool bufferPool[uint64]
+
+	poolGets atomic.Int64
+	poolHits atomic.Int64
+)
+
+// poolClass returns the class of the buffers able to hold length values,
+// false when length is too large to be pooled
+func poolClass(length int) (int, bool) {
+	class := minPoolClass
+	if length > 1<<minPoolClass {
+		class = bits.Len(uint(length - 1))
+	}
+	return class, class <= maxPoolClass
+}
+
+// get returns a buffer of the given length with undefined contents
+func (pool *bufferPool[T]) get(length int) []T {
+	class, ok := poolClass(length)
+	if !ok {
+		return make([]T, length)
+	}
+	poolGets.Add(1)
+	if buffer, ok := pool.classes[class-minPoolClass].Get().(*[]T); ok {
+		poolHits.Add(1)
+		return (*buffer)[:length]
+	}
+	return make([]T, length, 1<<class)
+}
+
+// put returns a buffer to the pool, it must not be used afterwards
+func (pool *bufferPool[T]) put(buffer []T) {
+	class, ok := poolClass(cap(buffer))
+	if !ok || cap(buffer) != 1<<class {
+		return
+	}
+	buffer = buffer[:0]
+	pool.classes[class-minPoolClass].Put(&buffer)
+}
+
+// PoolStats counts the temporary buffers requested by the operations and
+// how many of them were reused
+type PoolStats struct {
+	Gets    int64
+	Hits    int64
+	HitRate float64
+}
+
+// GetPoolStats returns the usage of the internal buffer pools since the
+// start or the last ResetPoolStats
+func GetPoolStats() PoolStats {
+	stats := PoolStats{Gets: poolGets.Load(), Hits: poolHits.Load()}
+	if stats.Gets > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(stats.Gets)
+	}
+	return stats
+}
+
+func ResetPoolStats() {
+	poolGets.Store(0)
+	poolHits.Store(0)
+}
@@ -0,0 +1,96 @@
+package grizzly
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Progress describes how far a long operation ("read", "groupby", "sort" or
+// "join") has gone
+type Progress struct {
+	Operation string
+	Processed int
+	Total     int
+}
+
+func (progress Progress) Percentage() float64 {
+	if progress.Total == 0 {
+		return 100
+	}
+	return float64(progress.Processed) * 100 / float64(progress.Total)
+}
+
+// ProgressFunc receives the progress of long operations, calls are serialized
+type ProgressFunc func(progress Progress)
+
+// progressStep is the number of rows between two reports
+const progressStep = 1 << 16
+
+var (
+	progressMutex sync.RWMutex
+	progressHook  ProgressFunc
+)
+
+// SetProgressFunc installs the callback for progress reports, nil disables them
+func SetProgressFunc(report ProgressFunc) {
+	progressMutex.Lock()
+	defer progressMutex.Unlock()
+	progressHook = report
+}
+
+// ProgressToChannel returns a ProgressFunc that sends the reports to channel,
+// dropping them when the channel is full so operations never block
+func ProgressToChannel(channel chan<- Progress) ProgressFunc {
+	return func(progress Progress) {
+		select {
+		case channel <- progress:
+		default:
+		}
+	}
+}
+
+type progressTracker struct {
+	operation string
+	total     int
+	processed atomic.Int64
+	report    ProgressFunc
+	mutex     sync.Mutex
+}
+
+// startProgress returns nil when no callback is installed, the methods of a
+// nil tracker do nothing
+func startProgress(operation string, total int) *progressTracker {
+	progressMutex.RLock()
+	report := progressHook
+	progressMutex.RUnlock()
+	if report == nil {
+		return nil
+	}
+	tracker := &progressTracker{operation: operation, total: total, report: report}
+	tracker.send(0)
+	return tracker
+}
+
+// add counts processed rows and reports every progressStep rows
+func (tracker *progressTracker) add(rows int) {
+	if tracker == nil || rows <= 0 {
+		return
+	}
+	processed := tracker.processed.Add(int64(rows))
+	if processed/progressStep != (processed-int64(rows))/progressStep {
+		tracker.send(int(processed))
+	}
+}
+
+func (tracker *progressTracker) finish() {
+	if tracker == nil {
+		return
+	}
+	tracker.send(tracker.total)
+}
+
+func (tracker *progressTracker) send(processed int) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	tracker.report(Progress{Operation: tracker.operation, Processed: minInt(processed, tracker.total), Total: tracker.total})
+}
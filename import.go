@@ -1,11 +1,15 @@
 package grizzly
 
 import (
+	"bufio"
 	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
-	"runtime"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -16,7 +20,32 @@ func ImportCSV(filepath string) (DataFrame, error) {
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
+	return ReadCSV(file)
+}
+
+// ReadCSV parses CSV data with a header row from a path or an io.Reader,
+// numeric columns become float
+func ReadCSV(source any) (DataFrame, error) {
+	return ReadDelimited(source, ',')
+}
+
+// ReadDelimited is ReadCSV with a custom field delimiter, like '\t'
+func ReadDelimited(source any, delimiter rune) (DataFrame, error) {
+	span := startSpan("parse")
+	df, err := readDelimited(source, delimiter)
+	span.end(err, "rows", df.GetLength(), "columns", df.GetNumberOfColumns())
+	return df, err
+}
+
+func readDelimited(source any, delimiter rune) (DataFrame, error) {
+	input, closeSource, err := openSource(source)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	defer closeSource()
+
+	reader := csv.NewReader(input)
+	reader.Comma = delimiter
 	records, err := reader.ReadAll()
 	if err != nil {
 		return DataFrame{}, fmt.Errorf("failed to read CSV file: %v", err)
@@ -27,10 +56,74 @@ func ImportCSV(filepath string) (DataFrame, error) {
 		return DataFrame{}, nil
 	}
 
-	headers := records[0]
-	rows := records[1:]
+	return recordsToDataFrame(records[0], records[1:]), nil
+}
+
+// ReadCSVGlob reads every CSV file matching pattern, like "data/2024-*.csv",
+// in parallel and stacks them in path order. Columns missing in a file are
+// filled with NaN and a column that is a string in any file becomes a string
+// column. When sourceColumn is not empty a column with that name holds the
+// path each row was read from.
+func ReadCSVGlob(pattern string, sourceColumn string) (DataFrame, error) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+	}
+	if len(paths) == 0 {
+		return DataFrame{}, fmt.Errorf("no files match %q", pattern)
+	}
+	frames, err := readFiles(paths, func(path string) (DataFrame, error) {
+		df, err := ReadCSV(path)
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to read %q: %w", path, err)
+		}
+		if sourceColumn != "" {
+			if isNameRepeated(df.Columns, sourceColumn) {
+				return DataFrame{}, fmt.Errorf("source column %q already exists in %q", sourceColumn, path)
+			}
+			df.Columns = append(df.Columns, NewStringSeries(sourceColumn, arrayResizeString(nil, df.GetLength(), path)))
+		}
+		return df, nil
+	})
+	if err != nil {
+		return DataFrame{}, err
+	}
+	return unionFrames(frames), nil
+}
+
+// readFiles calls read on every path with at most workerCount files open,
+// returning the frames in the order of paths
+func readFiles(paths []string, read func(path string) (DataFrame, error)) ([]DataFrame, error) {
+	frames := make([]DataFrame, len(paths))
+	errs := make([]error, len(paths))
+	limit := make(chan struct{}, workerCount())
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			frames[i], errs[i] = read(path)
+		}(i, path)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return frames, nil
+}
+
+// recordsToDataFrame builds string columns from the rows, the null tokens of
+// the Config become NaN and columns where every value parses as a number
+// become float
+func recordsToDataFrame(headers []string, rows [][]string) DataFrame {
 	numCols := len(headers)
 	numRows := len(rows)
+	progress := startProgress("read", numRows)
+	defer progress.finish()
 	columns := make([]Series, numCols)
 
 	// Initialize Series for each header
@@ -43,9 +136,10 @@ func ImportCSV(filepath string) (DataFrame, error) {
 	}
 
 	var result DataFrame
+	nulls := GetConfig().nullValues()
 
 	// Determine the number of goroutines based on available CPUs
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	chunkSize := (numRows + numGoroutines - 1) / numGoroutines
 
 	// Create local trackers for each goroutine
@@ -74,12 +168,15 @@ func ImportCSV(filepath string) (DataFrame, error) {
 			defer wg.Done()
 			localTracker := localTrackers[g] // Each goroutine uses its own tracker
 			for j := start; j < end; j++ {
+				if (j-start)%1024 == 1023 {
+					progress.add(1024)
+				}
 				for i := range columns {
 					stringValue := ""
 					if i < len(rows[j]) {
 						stringValue = rows[j][i]
 					}
-					if stringValue == "" {
+					if nulls[stringValue] {
 						stringValue = "NaN"
 					}
 					if localTracker[i] {
@@ -90,6 +187,7 @@ func ImportCSV(filepath string) (DataFrame, error) {
 					columns[i].String[j] = stringValue
 				}
 			}
+			progress.add((end - start) % 1024)
 		}(start, end, g)
 	}
 
@@ -116,8 +214,131 @@ func ImportCSV(filepath string) (DataFrame, error) {
 
 	result.Columns = columns
 	result.FixShape()
+	result.internOnRead()
+
+	return result
+}
 
-	return result, nil
+// openSource accepts a file path or an io.Reader, close must be called when done
+func openSource(source any) (io.Reader, func() error, error) {
+	switch value := source.(type) {
+	case string:
+		file, err := os.Open(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open file: %w", err)
+		}
+		return file, file.Close, nil
+	case io.Reader:
+		return value, func() error { return nil }, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported source type %T, expected a path or an io.Reader", source)
+}
+
+// FWFWidths converts consecutive field widths into column specs for ReadFWF
+func FWFWidths(widths ...int) [][2]int {
+	colspecs := make([][2]int, len(widths))
+	start := 0
+	for i, width := range widths {
+		colspecs[i] = [2]int{start, start + width}
+		start += width
+	}
+	return colspecs
+}
+
+// ReadFWF reads a fixed-width file from a path or an io.Reader. colspecs are
+// half-open [start, end) character ranges of every column, names defaults to
+// column_0, column_1... Values are trimmed, null tokens become NaN.
+func ReadFWF(source any, colspecs [][2]int, names []string) (DataFrame, error) {
+	if len(colspecs) == 0 {
+		return DataFrame{}, fmt.Errorf("fixed-width reader requires at least one column spec")
+	}
+	if names == nil {
+		names = make([]string, len(colspecs))
+		for i := range names {
+			names[i] = "column_" + strconv.Itoa(i)
+		}
+	}
+	if len(names) != len(colspecs) {
+		return DataFrame{}, fmt.Errorf("got %d names for %d column specs", len(names), len(colspecs))
+	}
+	for _, spec := range colspecs {
+		if spec[0] < 0 || spec[1] < spec[0] {
+			return DataFrame{}, fmt.Errorf("invalid column spec [%d, %d)", spec[0], spec[1])
+		}
+	}
+
+	input, closeSource, err := openSource(source)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	defer closeSource()
+
+	var rows [][]string
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := []rune(strings.TrimRight(scanner.Text(), "\r"))
+		if len(line) == 0 {
+			continue
+		}
+		row := make([]string, len(colspecs))
+		for i, spec := range colspecs {
+			start := minInt(spec[0], len(line))
+			end := minInt(spec[1], len(line))
+			row[i] = strings.TrimSpace(string(line[start:end]))
+		}
+		rows = append(rows, row)
+	}
+	if err = scanner.Err(); err != nil {
+		return DataFrame{}, fmt.Errorf("failed to read fixed-width file: %w", err)
+	}
+	return recordsToDataFrame(names, rows), nil
+}
+
+// ReadLogLines parses a log from a path or an io.Reader with a regular
+// expression, every named group becomes a column. Lines that do not match are
+// skipped.
+func ReadLogLines(source any, pattern string) (DataFrame, error) {
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("failed to compile pattern: %w", err)
+	}
+	var names []string
+	var groups []int
+	for i, name := range expression.SubexpNames() {
+		if name != "" {
+			names = append(names, name)
+			groups = append(groups, i)
+		}
+	}
+	if len(names) == 0 {
+		return DataFrame{}, fmt.Errorf("pattern has no named groups")
+	}
+
+	input, closeSource, err := openSource(source)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	defer closeSource()
+
+	var rows [][]string
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		match := expression.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		row := make([]string, len(groups))
+		for i, group := range groups {
+			row[i] = match[group]
+		}
+		rows = append(rows, row)
+	}
+	if err = scanner.Err(); err != nil {
+		return DataFrame{}, fmt.Errorf("failed to read log file: %w", err)
+	}
+	return recordsToDataFrame(names, rows), nil
 }
 
 func ImportCSVOld(filepath string) (DataFrame, error) {
@@ -0,0 +1,120 @@
+// Package indicators computes technical indicators over Grizzly float series.
+// Every indicator returns series with the length of its input, the rows
+// before the first full window are NaN so the results stay aligned with the
+// prices.
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/Puchungualotsqui/grizzly"
+)
+
+// prices returns the values of a float series
+func prices(series grizzly.Series, window int) ([]float64, error) {
+	if series.DataType != "float" {
+		return nil, fmt.Errorf("series %q is not a float series", series.Name)
+	}
+	if window < 1 {
+		return nil, fmt.Errorf("window must be positive, got %d", window)
+	}
+	return series.Floats(), nil
+}
+
+// workers follows the MaxWorkers setting of the grizzly Config
+func workers() int {
+	if maxWorkers := grizzly.GetConfig().MaxWorkers; maxWorkers > 0 {
+		return maxWorkers
+	}
+	return runtime.NumCPU()
+}
+
+// rolling applies kernel to every full window of values in parallel chunks.
+// Rows before the first full window and windows holding NaN are NaN.
+func rolling(values []float64, window int, kernel func(window []float64) float64) []float64 {
+	length := len(values)
+	result := make([]float64, length)
+	numGoroutines := workers()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := min(start+chunkSize, length)
+		if start >= length {
+			break
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			// lastNaN is the position of the latest NaN seen
+			lastNaN := -1
+			for i := max(start-window+1, 0); i < start; i++ {
+				if math.IsNaN(values[i]) {
+					lastNaN = i
+				}
+			}
+			for i := start; i < end; i++ {
+				if math.IsNaN(values[i]) {
+					lastNaN = i
+				}
+				if i < window-1 || lastNaN > i-window {
+					result[i] = math.NaN()
+					continue
+				}
+				result[i] = kernel(values[i-window+1 : i+1])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return result
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// SMA is the simple moving average over the last window rows, named
+// "<name>_sma"
+func SMA(series grizzly.Series, window int) (grizzly.Series, error) {
+	values, err := prices(series, window)
+	if err != nil {
+		return grizzly.Series{}, fmt.Errorf("failed to compute SMA: %w", err)
+	}
+	return grizzly.NewFloatSeries(series.Name+"_sma", rolling(values, window, mean)), nil
+}
+
+// BollingerBands returns the moving average over the last window rows and
+// the bands numStd population standard deviations above and below it, named
+// "<name>_bb_middle", "<name>_bb_upper" and "<name>_bb_lower". The usual
+// settings are a window of 20 and 2 deviations.
+func BollingerBands(series grizzly.Series, window int, numStd float64) (middle, upper, lower grizzly.Series, err error) {
+	values, err := prices(series, window)
+	if err != nil {
+		return middle, upper, lower, fmt.Errorf("failed to compute Bollinger bands: %w", err)
+	}
+	averages := rolling(values, window, mean)
+	deviations := rolling(values, window, func(window []float64) float64 {
+		average := mean(window)
+		sum := 0.0
+		for _, value := range window {
+			sum += (value - average) * (value - average)
+		}
+		return math.Sqrt(sum / float64(len(window)))
+	})
+	upperValues := make([]float64, len(values))
+	lowerValues := make([]float64, len(values))
+	for i := range values {
+		upperValues[i] = averages[i] + numStd*deviations[i]
+		lowerValues[i] = averages[i] - numStd*deviations[i]
+	}
+	return grizzly.NewFloatSeries(series.Name+"_bb_middle", averages),
+		grizzly.NewFloatSeries(series.Name+"_bb_upper", upperValues),
+		grizzly.NewFloatSeries(series.Name+"_bb_lower", lowerValues), nil
+}
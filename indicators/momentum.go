@@ -0,0 +1,106 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Puchungualotsqui/grizzly"
+)
+
+// ema smooths values with the factor alpha, starting from the mean of the
+// first window consecutive values without NaN. Every value depends on the
+// previous one so it runs sequentially. A NaN after the start gives NaN and
+// keeps the previous average.
+func ema(values []float64, window int, alpha float64) []float64 {
+	result := make([]float64, len(values))
+	average := math.NaN()
+	run := 0
+	for i, value := range values {
+		result[i] = math.NaN()
+		if math.IsNaN(value) {
+			run = 0
+			continue
+		}
+		run++
+		if !math.IsNaN(average) {
+			average += alpha * (value - average)
+		} else if run == window {
+			average = mean(values[i-window+1 : i+1])
+		}
+		result[i] = average
+	}
+	return result
+}
+
+// EMA is the exponential moving average with a smoothing factor of
+// 2 / (span + 1), named "<name>_ema". It starts with the simple average of
+// the first span rows.
+func EMA(series grizzly.Series, span int) (grizzly.Series, error) {
+	values, err := prices(series, span)
+	if err != nil {
+		return grizzly.Series{}, fmt.Errorf("failed to compute EMA: %w", err)
+	}
+	return grizzly.NewFloatSeries(series.Name+"_ema", ema(values, span, 2/float64(span+1))), nil
+}
+
+// RSI is the relative strength index between 0 and 100 over period rows with
+// Wilder's smoothing, named "<name>_rsi". The usual period is 14.
+func RSI(series grizzly.Series, period int) (grizzly.Series, error) {
+	values, err := prices(series, period)
+	if err != nil {
+		return grizzly.Series{}, fmt.Errorf("failed to compute RSI: %w", err)
+	}
+	gains := make([]float64, len(values))
+	losses := make([]float64, len(values))
+	for i := range values {
+		change := math.NaN()
+		if i > 0 {
+			change = values[i] - values[i-1]
+		}
+		gains[i] = math.Max(change, 0)
+		losses[i] = math.Max(-change, 0)
+		if math.IsNaN(change) {
+			gains[i], losses[i] = math.NaN(), math.NaN()
+		}
+	}
+	alpha := 1 / float64(period)
+	averageGains := ema(gains, period, alpha)
+	averageLosses := ema(losses, period, alpha)
+	result := make([]float64, len(values))
+	for i := range result {
+		if averageLosses[i] == 0 {
+			result[i] = 100
+			continue
+		}
+		result[i] = 100 - 100/(1+averageGains[i]/averageLosses[i])
+	}
+	return grizzly.NewFloatSeries(series.Name+"_rsi", result), nil
+}
+
+// MACD returns the difference between the fast and slow EMA, its EMA over
+// signal rows and the histogram between both, named "<name>_macd",
+// "<name>_macd_signal" and "<name>_macd_histogram". The usual settings are
+// 12, 26 and 9.
+func MACD(series grizzly.Series, fast, slow, signal int) (macd, signalLine, histogram grizzly.Series, err error) {
+	if fast >= slow {
+		return macd, signalLine, histogram, fmt.Errorf("failed to compute MACD: fast span %d must be shorter than slow span %d", fast, slow)
+	}
+	values, err := prices(series, min(fast, signal))
+	if err != nil {
+		return macd, signalLine, histogram, fmt.Errorf("failed to compute MACD: %w", err)
+	}
+	fastValues := ema(values, fast, 2/float64(fast+1))
+	slowValues := ema(values, slow, 2/float64(slow+1))
+	macdValues := make([]float64, len(values))
+	for i := range values {
+		macdValues[i] = fastValues[i] - slowValues[i]
+	}
+	signalValues := ema(macdValues, signal, 2/float64(signal+1))
+	histogramValues := make([]float64, len(values))
+	for i := range values {
+		histogramValues[i] = macdValues[i] - signalValues[i]
+	}
+	return grizzly.NewFloatSeries(series.Name+"_macd", macdValues),
+		grizzly.NewFloatSeries(series.Name+"_macd_signal", signalValues),
+		grizzly.NewFloatSeries(series.Name+"_macd_histogram", histogramValues), nil
+}
@@ -0,0 +1,236 @@
+package grizzly
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PDFOptions lays out the table written by WritePDF. Pages are A4, FontSize
+// defaults to 9 points and is reduced when the columns do not fit the width
+// of the page. Title is printed above the table on every page.
+type PDFOptions struct {
+	Title     string
+	Landscape bool
+	FontSize  float64
+}
+
+const (
+	pdfMargin    = 36.0
+	pdfCharWidth = 0.6 // Courier glyphs are 600 units of a 1000 units em
+	pdfMinFont   = 4.0
+	pdfMaxCell   = 40 // characters, longer values are cut
+)
+
+func init() {
+	formats["pdf"] = format{
+		writer: WriterFunc(func(output io.Writer, df *DataFrame) error { return df.WritePDF(output, PDFOptions{}) }),
+	}
+}
+
+// WritePDF renders df as a paginated table to a path or an io.Writer, with
+// the header repeated on every page and page numbers in the footer. Values
+// are formatted as set in the Config and numbers are right aligned. The
+// standard Courier fonts are used, characters outside Latin-1 are printed as
+// "?".
+func (df *DataFrame) WritePDF(destination any, options PDFOptions) error {
+	if options.FontSize < 0 {
+		return fmt.Errorf("font size must not be negative, got %v", options.FontSize)
+	}
+	if options.FontSize == 0 {
+		options.FontSize = 9
+	}
+	output, closeDestination, err := openDestination(destination)
+	if err != nil {
+		return err
+	}
+	if err = df.writePDF(output, options); err != nil {
+		closeDestination()
+		return err
+	}
+	return closeDestination()
+}
+
+// pdfCells formats every value of df as the text of its cell, the header
+// first, and returns the width of every column in characters
+func (df *DataFrame) pdfCells() ([][]string, []int) {
+	config := df.getConfig()
+	length := df.GetLength()
+	cells := make([][]string, length+1)
+	widths := make([]int, len(df.Columns))
+	for row := range cells {
+		cells[row] = make([]string, len(df.Columns))
+	}
+	for j := range df.Columns {
+		series := &df.Columns[j]
+		for row := range cells {
+			text := series.Name
+			if row > 0 {
+				text = formatValue(series, row-1, config)
+			}
+			if utf8.RuneCountInString(text) > pdfMaxCell {
+				text = string([]rune(text)[:pdfMaxCell-3]) + "..."
+			}
+			cells[row][j] = text
+			widths[j] = max(widths[j], utf8.RuneCountInString(text))
+		}
+		if series.DataType == "float" {
+			// Numbers are right aligned, the widths are exact in Courier
+			for row := 1; row < len(cells); row++ {
+				cells[row][j] = strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cells[row][j])) + cells[row][j]
+			}
+		}
+	}
+	return cells, widths
+}
+
+// pdfText escapes text for a PDF string in WinAnsiEncoding
+func pdfText(text string) string {
+	var builder strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case r < 32 || r > 255 || r >= 0x7F && r < 0xA0:
+			builder.WriteByte('?')
+		default:
+			builder.WriteByte(byte(r))
+		}
+	}
+	return builder.String()
+}
+
+func (df *DataFrame) writePDF(output io.Writer, options PDFOptions) error {
+	pageWidth, pageHeight := 595.0, 842.0
+	if options.Landscape {
+		pageWidth, pageHeight = pageHeight, pageWidth
+	}
+	cells, widths := df.pdfCells()
+	const gap = 2 // characters between columns
+	characters := 0
+	for _, width := range widths {
+		characters += width + gap
+	}
+	fontSize := options.FontSize
+	if characters > 0 {
+		fontSize = math.Min(fontSize, (pageWidth-2*pdfMargin)/(float64(characters)*pdfCharWidth))
+	}
+	if fontSize < pdfMinFont {
+		return fmt.Errorf("%d columns of %d characters do not fit the page, select fewer columns or use landscape", len(widths), characters)
+	}
+	lineHeight := fontSize * 1.4
+	top := pageHeight - pdfMargin
+	if options.Title != "" {
+		top -= lineHeight * 2
+	}
+	// The header and its rule take two lines, the footer one more
+	rowsPerPage := max(1, int((top-pdfMargin)/lineHeight)-3)
+	dataRows := cells[1:]
+	pages := max(1, (len(dataRows)+rowsPerPage-1)/rowsPerPage)
+
+	// Every column starts at the offset of the characters before it
+	offsets := make([]float64, len(widths))
+	position := 0
+	for j, width := range widths {
+		offsets[j] = pdfMargin + float64(position)*fontSize*pdfCharWidth
+		position += width + gap
+	}
+	line := func(content *strings.Builder, font string, size float64, x, y float64, text string) {
+		fmt.Fprintf(content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, pdfNumber(size), pdfNumber(x), pdfNumber(y), pdfText(text))
+	}
+
+	writer := &pdfWriter{output: bufio.NewWriter(output)}
+	writer.write("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	// Objects 1 to 4 are the catalog, the page tree and the fonts, then
+	// every page is followed by its content stream
+	writer.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, pages)
+	for page := range kids {
+		kids[page] = fmt.Sprintf("%d 0 R", 5+2*page)
+	}
+	writer.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pages))
+	writer.object(3, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	writer.object(4, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	for page := 0; page < pages; page++ {
+		var content strings.Builder
+		y := top
+		if options.Title != "" {
+			line(&content, "F2", fontSize*1.4, pdfMargin, pageHeight-pdfMargin-fontSize*1.4, options.Title)
+		}
+		y -= lineHeight
+		for j, text := range cells[0] {
+			line(&content, "F2", fontSize, offsets[j], y, text)
+		}
+		rule := y - lineHeight*0.4
+		fmt.Fprintf(&content, "0.5 w %s %s m %s %s l S\n", pdfNumber(pdfMargin), pdfNumber(rule), pdfNumber(pageWidth-pdfMargin), pdfNumber(rule))
+		y -= lineHeight
+		start := page * rowsPerPage
+		for _, row := range dataRows[start:min(start+rowsPerPage, len(dataRows))] {
+			y -= lineHeight
+			for j, text := range row {
+				line(&content, "F1", fontSize, offsets[j], y, text)
+			}
+		}
+		footer := fmt.Sprintf("Page %d of %d", page+1, pages)
+		line(&content, "F1", fontSize, pageWidth-pdfMargin-float64(len(footer))*fontSize*pdfCharWidth, pdfMargin-fontSize, footer)
+
+		writer.object(5+2*page, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfNumber(pageWidth), pdfNumber(pageHeight), 6+2*page))
+		writer.object(6+2*page, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+	writer.finish(4 + 2*pages)
+	if writer.err != nil {
+		return fmt.Errorf("failed to write PDF: %w", writer.err)
+	}
+	return nil
+}
+
+// pdfNumber prints a coordinate with at most two decimals
+func pdfNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// pdfWriter writes objects and remembers their byte offsets for the cross
+// reference table, the first error stops every write after it
+type pdfWriter struct {
+	output  *bufio.Writer
+	offset  int
+	offsets map[int]int
+	err     error
+}
+
+func (writer *pdfWriter) write(text string) {
+	if writer.err != nil {
+		return
+	}
+	var written int
+	written, writer.err = writer.output.WriteString(text)
+	writer.offset += written
+}
+
+func (writer *pdfWriter) object(number int, body string) {
+	if writer.offsets == nil {
+		writer.offsets = map[int]int{}
+	}
+	writer.offsets[number] = writer.offset
+	writer.write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", number, body))
+}
+
+// finish writes the cross reference table of objects 1 to count and the
+// trailer
+func (writer *pdfWriter) finish(count int) {
+	start := writer.offset
+	writer.write(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", count+1))
+	for number := 1; number <= count; number++ {
+		writer.write(fmt.Sprintf("%010d 00000 n \n", writer.offsets[number]))
+	}
+	writer.write(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", count+1, start))
+	if writer.err == nil {
+		writer.err = writer.output.Flush()
+	}
+}
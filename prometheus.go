@@ -0,0 +1,226 @@
+package grizzly
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metric is an aggregation exposed by a MetricsCollector. Kind is "count"
+// for the number of rows, "sum" for the sum of Column or "summary" for the
+// Quantiles of Column with its sum and count. Every combination of values of
+// the Labels columns is a separate series of the metric.
+type Metric struct {
+	Name      string
+	Help      string
+	Kind      string
+	Column    string
+	Labels    []string
+	Quantiles []float64
+}
+
+var metricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// MetricsCollector exposes aggregations of a DataFrame in the Prometheus
+// text format. The DataFrame is read from source on every scrape, so the
+// metrics follow the data of the service.
+type MetricsCollector struct {
+	source  func() (DataFrame, error)
+	metrics []Metric
+}
+
+// NewMetricsCollector checks the metrics and returns a collector reading
+// its data from source. Serve it with http.Handle("/metrics", collector).
+func NewMetricsCollector(source func() (DataFrame, error), metrics ...Metric) (*MetricsCollector, error) {
+	if len(metrics) == 0 {
+		return nil, fmt.Errorf("metrics collector requires at least one metric")
+	}
+	names := map[string]bool{}
+	for _, metric := range metrics {
+		if !metricName.MatchString(metric.Name) {
+			return nil, fmt.Errorf("invalid metric name %q", metric.Name)
+		}
+		if names[metric.Name] {
+			return nil, fmt.Errorf("metric %q is repeated", metric.Name)
+		}
+		names[metric.Name] = true
+		for _, label := range metric.Labels {
+			if !metricName.MatchString(label) || strings.Contains(label, ":") || strings.HasPrefix(label, "__") {
+				return nil, fmt.Errorf("invalid label name %q of metric %q", label, metric.Name)
+			}
+		}
+		switch metric.Kind {
+		case "count":
+		case "sum", "summary":
+			if metric.Column == "" {
+				return nil, fmt.Errorf("metric %q of kind %q requires a column", metric.Name, metric.Kind)
+			}
+			for _, quantile := range metric.Quantiles {
+				if quantile < 0 || quantile > 1 {
+					return nil, fmt.Errorf("quantile %v of metric %q must be between 0 and 1", quantile, metric.Name)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unknown kind %q of metric %q, expected count, sum or summary", metric.Kind, metric.Name)
+		}
+	}
+	return &MetricsCollector{source: source, metrics: metrics}, nil
+}
+
+// ServeHTTP writes the metrics for a scrape
+func (collector *MetricsCollector) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var buffer strings.Builder
+	if _, err := collector.WriteTo(&buffer); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	io.WriteString(writer, buffer.String())
+}
+
+// WriteTo reads the DataFrame from source and writes every metric in the
+// Prometheus text format
+func (collector *MetricsCollector) WriteTo(output io.Writer) (int64, error) {
+	df, err := collector.source()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read metrics source: %w", err)
+	}
+	counter := &countingWriter{output: output}
+	writer := bufio.NewWriter(counter)
+	for _, metric := range collector.metrics {
+		if err = writeMetric(writer, &df, metric); err != nil {
+			return counter.count, err
+		}
+	}
+	err = writer.Flush()
+	return counter.count, err
+}
+
+type countingWriter struct {
+	output io.Writer
+	count  int64
+}
+
+func (writer *countingWriter) Write(data []byte) (int, error) {
+	written, err := writer.output.Write(data)
+	writer.count += int64(written)
+	return written, err
+}
+
+func writeMetric(writer *bufio.Writer, df *DataFrame, metric Metric) error {
+	var values []float64
+	if metric.Kind != "count" {
+		series, err := df.GetColumnByName(metric.Column)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve column of metric %q: %w", metric.Name, err)
+		}
+		if series.DataType != "float" {
+			return fmt.Errorf("column %q of metric %q is not of type float", metric.Column, metric.Name)
+		}
+		values = series.Float
+	}
+
+	groups := [][]int{nil}
+	var keys []*Series
+	if len(metric.Labels) > 0 {
+		identifiers := make([]any, len(metric.Labels))
+		for i, label := range metric.Labels {
+			identifiers[i] = label
+		}
+		groupBy, err := df.groupBy(identifiers)
+		if err != nil {
+			return fmt.Errorf("failed to group metric %q: %w", metric.Name, err)
+		}
+		groups, keys = groupBy.groups, groupBy.keys
+	} else {
+		groups[0] = make([]int, df.GetLength())
+		for i := range groups[0] {
+			groups[0][i] = i
+		}
+	}
+
+	kind := "gauge"
+	if metric.Kind == "summary" {
+		kind = "summary"
+	}
+	if metric.Help != "" {
+		fmt.Fprintf(writer, "# HELP %s %s\n", metric.Name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(metric.Help))
+	}
+	fmt.Fprintf(writer, "# TYPE %s %s\n", metric.Name, kind)
+	for _, rows := range groups {
+		labels := make([]string, len(keys))
+		for i, key := range keys {
+			labels[i] = fmt.Sprintf(`%s="%s"`, metric.Labels[i], metricLabelValue(key, rows[0]))
+		}
+		if metric.Kind == "count" {
+			writeSample(writer, metric.Name, labels, float64(len(rows)))
+			continue
+		}
+		observed := make([]float64, 0, len(rows))
+		sum := 0.0
+		for _, row := range rows {
+			if !math.IsNaN(values[row]) {
+				observed = append(observed, values[row])
+				sum += values[row]
+			}
+		}
+		if metric.Kind == "sum" {
+			writeSample(writer, metric.Name, labels, sum)
+			continue
+		}
+		sort.Float64s(observed)
+		for _, quantile := range metric.Quantiles {
+			quantileLabel := `quantile="` + strconv.FormatFloat(quantile, 'g', -1, 64) + `"`
+			writeSample(writer, metric.Name, append(labels[:len(labels):len(labels)], quantileLabel), sortedQuantile(observed, quantile))
+		}
+		writeSample(writer, metric.Name+"_sum", labels, sum)
+		writeSample(writer, metric.Name+"_count", labels, float64(len(observed)))
+	}
+	return nil
+}
+
+// metricLabelValue formats the value of a label column escaped for the text format
+func metricLabelValue(series *Series, row int) string {
+	value := series.GetValueAsString(row)
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func writeSample(writer *bufio.Writer, name string, labels []string, value float64) {
+	writer.WriteString(name)
+	if len(labels) > 0 {
+		writer.WriteString("{" + strings.Join(labels, ",") + "}")
+	}
+	var formatted string
+	switch {
+	case math.IsNaN(value):
+		formatted = "NaN"
+	case math.IsInf(value, 1):
+		formatted = "+Inf"
+	case math.IsInf(value, -1):
+		formatted = "-Inf"
+	default:
+		formatted = strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	writer.WriteString(" " + formatted + "\n")
+}
+
+// sortedQuantile interpolates the quantile q of sorted values, NaN when
+// there are none
+func sortedQuantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	position := q * float64(len(sorted)-1)
+	lower := int(position)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	weight := position - float64(lower)
+	return sorted[lower]*(1-weight) + sorted[lower+1]*weight
+}
@@ -0,0 +1,277 @@
+package grizzly
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RemoteQuery is an operation run by a RemoteServer on one of its frames.
+// Filter keeps the rows where a ParseExpr expression is true, then the rows
+// are grouped by GroupBy and reduced with Aggregations like GroupBy.Agg, or
+// the Columns are selected, every column when it is empty. Limit keeps the
+// first rows of the result when it is positive, the server also cuts the
+// result to its MaxRows.
+type RemoteQuery struct {
+	Frame        string
+	Filter       string
+	Columns      []string
+	GroupBy      []string
+	Aggregations map[string][]string
+	Limit        int
+}
+
+// RemoteServer is an http.Handler running RemoteQuery operations on the
+// DataFrames registered in it and returning only their results, so clients
+// in other processes do not have to load the data. Mount it with
+//
+//	http.Handle("/frames/", http.StripPrefix("/frames", server))
+//
+// and query it with a RemoteClient. The protocol is plain HTTP with
+// encoding/gob bodies, not gRPC. Frames are read on every request without
+// copying or modifying them, so concurrent requests are safe, but the frames
+// must not be modified by their owner while a request is served. The server
+// does not authenticate clients.
+type RemoteServer struct {
+	// MaxQueryBytes is the largest query body accepted
+	MaxQueryBytes int64
+	// MaxRows is the most rows a query returns, whatever its Limit. 0 sets
+	// no limit.
+	MaxRows int
+
+	mutex  sync.RWMutex
+	frames map[string]*DataFrame
+}
+
+// NewRemoteServer returns a RemoteServer without frames accepting queries of
+// up to 1 MiB and returning up to 1,000,000 rows
+func NewRemoteServer() *RemoteServer {
+	return &RemoteServer{MaxQueryBytes: 1 << 20, MaxRows: 1_000_000, frames: map[string]*DataFrame{}}
+}
+
+// Register makes df available under name, replacing a frame with the same name
+func (server *RemoteServer) Register(name string, df *DataFrame) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.frames[name] = df
+}
+
+// Unregister removes the frame registered under name
+func (server *RemoteServer) Unregister(name string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	delete(server.frames, name)
+}
+
+// remoteResult is the body of a successful query, the columns are sent
+// with their exported fields
+type remoteResult struct {
+	Columns []Series
+}
+
+func (server *RemoteServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch {
+	case request.Method == http.MethodGet && request.URL.Path == "/frames":
+		server.mutex.RLock()
+		names := make([]string, 0, len(server.frames))
+		for name := range server.frames {
+			names = append(names, name)
+		}
+		server.mutex.RUnlock()
+		sort.Strings(names)
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(names)
+	case request.Method == http.MethodPost && request.URL.Path == "/query":
+		var query RemoteQuery
+		body := http.MaxBytesReader(writer, request.Body, server.MaxQueryBytes)
+		if err := gob.NewDecoder(body).Decode(&query); err != nil {
+			http.Error(writer, fmt.Sprintf("invalid query: %v", err), http.StatusBadRequest)
+			return
+		}
+		server.mutex.RLock()
+		df, found := server.frames[query.Frame]
+		server.mutex.RUnlock()
+		if !found {
+			http.Error(writer, fmt.Sprintf("frame %q is not registered", query.Frame), http.StatusNotFound)
+			return
+		}
+		if server.MaxRows > 0 && (query.Limit <= 0 || query.Limit > server.MaxRows) {
+			query.Limit = server.MaxRows
+		}
+		result, err := df.runRemoteQuery(query)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var buffer bytes.Buffer
+		if err = gob.NewEncoder(&buffer).Encode(remoteResult{Columns: result.Columns}); err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/x-gob")
+		writer.Write(buffer.Bytes())
+	default:
+		http.Error(writer, "expected GET /frames or POST /query", http.StatusNotFound)
+	}
+}
+
+// runRemoteQuery runs query on df, the result never shares memory with df
+// and its string columns are not compressed so they can be encoded
+func (df *DataFrame) runRemoteQuery(query RemoteQuery) (DataFrame, error) {
+	rows := identityRows(df.GetLength())
+	if query.Filter != "" {
+		mask, err := df.conditionMask(query.Filter)
+		if err != nil {
+			return DataFrame{}, err
+		}
+		rows = MaskIndexes(mask)
+	}
+
+	var result DataFrame
+	if len(query.GroupBy) > 0 {
+		subset, err := df.SelectRows(rows)
+		if err != nil {
+			return DataFrame{}, err
+		}
+		identifiers := make([]any, len(query.GroupBy))
+		for i, name := range query.GroupBy {
+			identifiers[i] = name
+		}
+		groupBy, err := subset.groupBy(identifiers)
+		if err != nil {
+			return DataFrame{}, err
+		}
+		if result, err = groupBy.Agg(query.Aggregations); err != nil {
+			return DataFrame{}, err
+		}
+		if query.Limit > 0 && query.Limit < result.GetLength() {
+			result, err = result.SelectRows(identityRows(query.Limit))
+			if err != nil {
+				return DataFrame{}, err
+			}
+		}
+	} else {
+		if len(query.Aggregations) > 0 {
+			return DataFrame{}, fmt.Errorf("aggregations require group by columns")
+		}
+		if query.Limit > 0 && query.Limit < len(rows) {
+			rows = rows[:query.Limit]
+		}
+		names := query.Columns
+		if len(names) == 0 {
+			names = df.GetColumnNames()
+		}
+		for _, name := range names {
+			series, err := df.GetColumnByName(name)
+			if err != nil {
+				return DataFrame{}, fmt.Errorf("failed to select column %q: %w", name, err)
+			}
+			result.Columns = append(result.Columns, seriesTake(*series, rows))
+		}
+	}
+	for i := range result.Columns {
+		if err := result.Columns[i].Decompress(); err != nil {
+			return DataFrame{}, err
+		}
+	}
+	return result, nil
+}
+
+// RemoteClient runs queries against a RemoteServer in another process
+type RemoteClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewRemoteClient returns a client of the RemoteServer mounted at baseURL,
+// like "http://reports:8080/frames". client is http.DefaultClient when nil.
+func NewRemoteClient(baseURL string, client *http.Client) *RemoteClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &RemoteClient{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+// Frames returns the names of the frames registered in the server
+func (client *RemoteClient) Frames(ctx context.Context) ([]string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, client.baseURL+"/frames", nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.do(request)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	if err = json.Unmarshal(body, &names); err != nil {
+		return nil, fmt.Errorf("invalid frames response: %w", err)
+	}
+	return names, nil
+}
+
+// Query runs query in the server and returns its result
+func (client *RemoteClient) Query(ctx context.Context, query RemoteQuery) (DataFrame, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(query); err != nil {
+		return DataFrame{}, fmt.Errorf("failed to encode query: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, client.baseURL+"/query", &buffer)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	request.Header.Set("Content-Type", "application/x-gob")
+	body, err := client.do(request)
+	if err != nil {
+		return DataFrame{}, fmt.Errorf("query of frame %q failed: %w", query.Frame, err)
+	}
+	var result remoteResult
+	if err = gob.NewDecoder(bytes.NewReader(body)).Decode(&result); err != nil {
+		return DataFrame{}, fmt.Errorf("invalid query response: %w", err)
+	}
+	df := DataFrame{Columns: result.Columns}
+	if err = df.CheckIntegrity(); err != nil {
+		return DataFrame{}, fmt.Errorf("invalid query response: %w", err)
+	}
+	return df, nil
+}
+
+// Select returns the columns of a remote frame, every column when none is given
+func (client *RemoteClient) Select(ctx context.Context, frame string, columns ...string) (DataFrame, error) {
+	return client.Query(ctx, RemoteQuery{Frame: frame, Columns: columns})
+}
+
+// Filter returns the rows of a remote frame where the expression is true
+func (client *RemoteClient) Filter(ctx context.Context, frame string, filter string) (DataFrame, error) {
+	return client.Query(ctx, RemoteQuery{Frame: frame, Filter: filter})
+}
+
+// Aggregate groups a remote frame by the columns in by and reduces every
+// group like GroupBy.Agg
+func (client *RemoteClient) Aggregate(ctx context.Context, frame string, by []string, aggregations map[string][]string) (DataFrame, error) {
+	return client.Query(ctx, RemoteQuery{Frame: frame, GroupBy: by, Aggregations: aggregations})
+}
+
+// do sends request and returns the body, errors of the server are returned
+// with their message
+func (client *RemoteClient) do(request *http.Request) ([]byte, error) {
+	response, err := client.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %s: %s", response.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -6,11 +6,33 @@ import (
 	"strconv"
 )
 
+// Series is a named column holding either floats or strings. Build series
+// with NewFloatSeries, NewStringSeries or NewSeries and read the values with
+// Floats and Strings, Validate checks the invariants of series built by
+// hand.
 type Series struct {
-	Name     string
-	Float    []float64
-	String   []string
+	Name string
+	// Deprecated: read the values with Floats. Writing Float directly can
+	// leave the series inconsistent with DataType.
+	Float []float64
+	// Deprecated: read the values with Strings. Writing String directly can
+	// leave the series inconsistent with DataType.
+	String []string
+	// DataType is "float" or "string"
 	DataType string
+	// Metadata holds descriptive attributes like "description", "unit" or
+	// "source", it is kept when rows are selected, filtered or grouped
+	Metadata map[string]string
+
+	bloom       *bloomFilter
+	keySet      *keySet
+	hashIndex   *hashIndex
+	sortedIndex []int
+	compressed  *compressedStrings
+	stats       *statsCache
+	// shared values belong to a Slice view or its source, they are copied
+	// before the series is modified
+	shared bool
 }
 
 func NewStringSeries(name string, String []string) Series {
@@ -19,6 +41,7 @@ func NewStringSeries(name string, String []string) Series {
 		Float:    make([]float64, 0),
 		String:   String,
 		DataType: "string",
+		stats:    &statsCache{},
 	}
 }
 
@@ -28,10 +51,93 @@ func NewFloatSeries(name string, Float []float64) Series {
 		Float:    Float,
 		String:   make([]string, 0),
 		DataType: "float",
+		stats:    &statsCache{},
+	}
+}
+
+// NewSeries builds a series from []float64, []string, []int, []int64 or
+// []bool values, integers and booleans are stored as floats
+func NewSeries(name string, values any) (Series, error) {
+	switch typed := values.(type) {
+	case []float64:
+		return NewFloatSeries(name, typed), nil
+	case []string:
+		return NewStringSeries(name, typed), nil
+	case []int:
+		floats := make([]float64, len(typed))
+		for i, value := range typed {
+			floats[i] = float64(value)
+		}
+		return NewFloatSeries(name, floats), nil
+	case []int64:
+		floats := make([]float64, len(typed))
+		for i, value := range typed {
+			floats[i] = float64(value)
+		}
+		return NewFloatSeries(name, floats), nil
+	case []bool:
+		floats := make([]float64, len(typed))
+		for i, value := range typed {
+			if value {
+				floats[i] = 1
+			}
+		}
+		return NewFloatSeries(name, floats), nil
+	}
+	return Series{}, fmt.Errorf("unsupported series values type %T", values)
+}
+
+// Validate checks that the data type is "float" or "string" and that only
+// the slice of that type holds values
+func (series *Series) Validate() error {
+	switch series.DataType {
+	case "float":
+		if len(series.String) > 0 || series.compressed != nil {
+			count := len(series.String)
+			if series.compressed != nil {
+				count = series.compressed.length
+			}
+			return fmt.Errorf("float series %q holds %d string values", series.Name, count)
+		}
+	case "string":
+		if len(series.Float) > 0 {
+			return fmt.Errorf("string series %q holds %d float values", series.Name, len(series.Float))
+		}
+	default:
+		return fmt.Errorf("series %q has unsupported type %q", series.Name, series.DataType)
+	}
+	return nil
+}
+
+// sameLength checks that the operands of an element-wise operation have
+// the same length, naming both in the error
+func sameLength(operation string, left, right *Series) error {
+	if left.GetLength() != right.GetLength() {
+		return fmt.Errorf("%s requires series of the same length, %q has %d values and %q has %d",
+			operation, left.Name, left.GetLength(), right.Name, right.GetLength())
+	}
+	return nil
+}
+
+// Floats returns the values of a float series, nil for string series
+func (series *Series) Floats() []float64 {
+	if series.DataType != "float" {
+		return nil
+	}
+	return series.Float
+}
+
+// Strings returns the values of a string series, decompressing them when
+// the series is compressed, and nil for float series
+func (series *Series) Strings() []string {
+	if series.DataType != "string" {
+		return nil
 	}
+	return series.strings()
 }
 
 func (series *Series) ResizeSeries(targetLength int, defaultValue string) {
+	series.invalidateIndexes()
 	if series.DataType == "string" {
 		series.String = arrayResizeString(series.String, targetLength, defaultValue)
 		return
@@ -48,7 +154,7 @@ func (series *Series) Print(max int) {
 		}
 	} else {
 		for i := 0; i < max; i++ {
-			fmt.Println(i, series.String[i])
+			fmt.Println(i, series.GetValueString(i))
 		}
 	}
 }
@@ -58,6 +164,13 @@ func (series *Series) GetValueFloat(index int) float64 {
 }
 
 func (series *Series) GetValueString(index int) string {
+	if series.compressed != nil {
+		chunk, err := series.compressed.chunk(index / compressionChunkSize)
+		if err != nil {
+			panic(fmt.Sprintf("failed to decompress series %q: %v", series.Name, err))
+		}
+		return chunk[index%compressionChunkSize]
+	}
 	return series.String[index]
 }
 
@@ -68,3 +181,49 @@ func (series *Series) GetValueAsString(index int) string {
 		return series.GetValueString(index)
 	}
 }
+
+// Copy returns a series with its own backing slices, indexes are not copied
+func (series *Series) Copy() Series {
+	return Series{
+		Name:     series.Name,
+		Float:    append(make([]float64, 0, len(series.Float)), series.Float...),
+		String:   append(make([]string, 0, len(series.String)), series.String...),
+		DataType: series.DataType,
+		Metadata: copyMetadata(series.Metadata),
+		// Compressed values and statistics are never modified and can be shared
+		compressed: series.compressed,
+		stats:      series.stats,
+	}
+}
+
+func (series *Series) SetMetadata(key, value string) {
+	if series.Metadata == nil {
+		series.Metadata = make(map[string]string)
+	}
+	series.Metadata[key] = value
+}
+
+func (series *Series) GetMetadata(key string) (string, bool) {
+	value, ok := series.Metadata[key]
+	return value, ok
+}
+
+// SetUnit is a shortcut for the "unit" metadata
+func (series *Series) SetUnit(unit string) {
+	series.SetMetadata("unit", unit)
+}
+
+func (series *Series) GetUnit() string {
+	return series.Metadata["unit"]
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+	result := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		result[key] = value
+	}
+	return result
+}
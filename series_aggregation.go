@@ -1,22 +1,111 @@
 package grizzly
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
 
 func (series *Series) CountWord(word string) float64 {
 	if series.DataType == "float" {
 		return 0
 	} else {
-		return arrayStringCountWord(series.String, word)
+		return arrayStringCountWord(series.strings(), word)
 	}
 }
 
+// MatchOptions configures CountMatches. IgnoreCase ignores the case,
+// WholeWord only counts matches not preceded or followed by a letter, digit
+// or underscore, so patterns like "C++" work, and Regex reads the pattern
+// as a regular expression instead of plain text.
+type MatchOptions struct {
+	IgnoreCase bool
+	WholeWord  bool
+	Regex      bool
+}
+
+// CountMatches counts the non overlapping occurrences of pattern inside
+// every value, unlike CountWord that compares whole values. It returns the
+// total and a float series "<name>_matches" with the count of every row,
+// NaN for null values.
+func (series *Series) CountMatches(pattern string, options MatchOptions) (float64, Series, error) {
+	if series.DataType != "string" {
+		return 0, Series{}, fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	if pattern == "" {
+		return 0, Series{}, fmt.Errorf("pattern can not be empty")
+	}
+	count := func(value string) int { return strings.Count(value, pattern) }
+	if options.IgnoreCase || options.WholeWord || options.Regex {
+		expression := pattern
+		if !options.Regex {
+			expression = regexp.QuoteMeta(pattern)
+		}
+		if options.IgnoreCase {
+			expression = "(?i)" + expression
+		}
+		compiled, err := regexp.Compile(expression)
+		if err != nil {
+			return 0, Series{}, fmt.Errorf("failed to compile pattern %q: %w", pattern, err)
+		}
+		count = func(value string) int { return len(compiled.FindAllStringIndex(value, -1)) }
+		if options.WholeWord {
+			count = func(value string) int { return countWholeWords(compiled, value) }
+		}
+	}
+
+	values := series.strings()
+	counts := make([]float64, len(values))
+	parallelChunks(len(values), func(_, start, end int) {
+		for j := start; j < end; j++ {
+			if values[j] == "NaN" {
+				counts[j] = math.NaN()
+				continue
+			}
+			counts[j] = float64(count(values[j]))
+		}
+	})
+
+	var total float64
+	for _, value := range counts {
+		if !math.IsNaN(value) {
+			total += value
+		}
+	}
+	return total, NewFloatSeries(series.Name+"_matches", counts), nil
+}
+
+// isWordRune reports whether r is part of a word for WholeWord matches
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// countWholeWords counts the non overlapping matches of compiled in value
+// that are not preceded or followed by a word rune. The matches are found on
+// the whole value so anchors and \b in the pattern keep their meaning.
+func countWholeWords(compiled *regexp.Regexp, value string) int {
+	count := 0
+	for _, match := range compiled.FindAllStringIndex(value, -1) {
+		start, end := match[0], match[1]
+		before, _ := utf8.DecodeLastRuneInString(value[:start])
+		after, _ := utf8.DecodeRuneInString(value[end:])
+		if end > start && (start == 0 || !isWordRune(before)) && (end == len(value) || !isWordRune(after)) {
+			count++
+		}
+	}
+	return count
+}
+
 func (series *Series) GetMax() (float64, error) {
 	if series.DataType == "string" {
 		return 0, fmt.Errorf("to get max select a float column")
 	} else if series.GetLength() == 0 {
 		return 0, fmt.Errorf("GetMax requires a non-empty array")
 	}
-	return arrayMax(series.Float), nil
+	return series.statistics().max, nil
 }
 
 func (series *Series) GetMin() (float64, error) {
@@ -25,7 +114,7 @@ func (series *Series) GetMin() (float64, error) {
 	} else if series.GetLength() == 0 {
 		return 0, fmt.Errorf("GetMin requires a non-empty array")
 	}
-	return arrayMin(series.Float), nil
+	return series.statistics().min, nil
 }
 
 func (series *Series) GetMean() (float64, error) {
@@ -61,7 +150,7 @@ func (series *Series) GetSum() (float64, error) {
 	} else if series.GetLength() == 0 {
 		return 0, fmt.Errorf("GetSum requires a non-empty array")
 	}
-	return arraySum(series.Float), nil
+	return series.statistics().sum, nil
 }
 
 func (series *Series) GetVariance() (float64, error) {
@@ -77,5 +166,5 @@ func (series *Series) GetNonFloatValues() []string {
 	if series.DataType == "float" {
 		return []string{}
 	}
-	return arrayGetNonFloatValues(series.String)
+	return arrayGetNonFloatValues(series.strings())
 }
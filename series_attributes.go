@@ -3,6 +3,8 @@ package grizzly
 func (series *Series) GetLength() int {
 	if series.DataType == "float" {
 		return len(series.Float)
+	} else if series.compressed != nil {
+		return series.compressed.length
 	} else {
 		return len(series.String)
 	}
@@ -0,0 +1,99 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ACF returns the autocorrelation of a float series for every lag from 0 to
+// maxLag as the series "<name>_acf", where the value at position k is the
+// correlation between rows t and t+k. Pairs holding NaN are skipped.
+func (series *Series) ACF(maxLag int) (Series, error) {
+	if series.DataType != "float" {
+		return Series{}, fmt.Errorf("autocorrelation requires a float series")
+	}
+	values := series.Float
+	if maxLag < 0 || maxLag >= len(values) {
+		return Series{}, fmt.Errorf("maximum lag must be between 0 and %d, got %d", len(values)-1, maxLag)
+	}
+	mean, count := 0.0, 0.0
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			mean += value
+			count++
+		}
+	}
+	mean /= count
+	variance := 0.0
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			variance += (value - mean) * (value - mean)
+		}
+	}
+	if variance == 0 || math.IsNaN(variance) {
+		return Series{}, fmt.Errorf("autocorrelation is undefined for a series without variance")
+	}
+	result := make([]float64, maxLag+1)
+	for lag := range result {
+		sum := 0.0
+		for t := 0; t+lag < len(values); t++ {
+			if product := (values[t] - mean) * (values[t+lag] - mean); !math.IsNaN(product) {
+				sum += product
+			}
+		}
+		result[lag] = sum / variance
+	}
+	return NewFloatSeries(series.Name+"_acf", result), nil
+}
+
+// PACF returns the partial autocorrelation for every lag from 0 to maxLag as
+// the series "<name>_pacf", the correlation between rows t and t+k once the
+// lags in between are accounted for. It solves the Durbin-Levinson recursion
+// over the ACF.
+func (series *Series) PACF(maxLag int) (Series, error) {
+	acf, err := series.ACF(maxLag)
+	if err != nil {
+		return Series{}, err
+	}
+	rho := acf.Float
+	result := make([]float64, maxLag+1)
+	result[0] = 1
+	previous := make([]float64, maxLag+1)
+	current := make([]float64, maxLag+1)
+	for k := 1; k <= maxLag; k++ {
+		numerator, denominator := rho[k], 1.0
+		for j := 1; j < k; j++ {
+			numerator -= previous[j] * rho[k-j]
+			denominator -= previous[j] * rho[j]
+		}
+		current[k] = numerator / denominator
+		for j := 1; j < k; j++ {
+			current[j] = previous[j] - current[k]*previous[k-j]
+		}
+		result[k] = current[k]
+		previous, current = current, previous
+	}
+	return NewFloatSeries(series.Name+"_pacf", result), nil
+}
+
+// LagMatrix returns a DataFrame with the series and its values shifted by 1
+// to maxLag rows, named "<name>_lag1", "<name>_lag2"..., the features of an
+// autoregressive model. The first rows of every lag column are NaN.
+func (series *Series) LagMatrix(maxLag int) (DataFrame, error) {
+	length := series.GetLength()
+	if maxLag < 1 {
+		return DataFrame{}, fmt.Errorf("maximum lag must be positive, got %d", maxLag)
+	}
+	result := DataFrame{Columns: []Series{series.Copy()}}
+	rows := make([]int, length)
+	for lag := 1; lag <= maxLag; lag++ {
+		for i := range rows {
+			rows[i] = i - lag
+		}
+		lagged := seriesTake(*series, rows)
+		lagged.Name = series.Name + "_lag" + strconv.Itoa(lag)
+		result.Columns = append(result.Columns, lagged)
+	}
+	return result, nil
+}
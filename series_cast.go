@@ -0,0 +1,205 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CastOptions configures Series.Cast. Strict fails on the first value that
+// can not be converted exactly and leaves the series unchanged, otherwise
+// lossy values are kept rounded and values that can not be converted become
+// nulls. Layout is the format of "datetime" values, RFC 3339 by default.
+type CastOptions struct {
+	Strict bool
+	Layout string
+}
+
+// CastIssue is a value that lost information or could not be converted
+type CastIssue struct {
+	Row    int
+	Value  string
+	Reason string
+}
+
+// CastReport describes a conversion made by Series.Cast
+type CastReport struct {
+	Converted int
+	Nulls     int
+	Lossy     []CastIssue
+	Failed    []CastIssue
+}
+
+// castTypes are the types accepted by Cast and the storage they use
+var castTypes = map[string]string{
+	"float": "float", "float64": "float", "float32": "float",
+	"int": "float", "int64": "float", "int32": "float", "bool": "float",
+	"string": "string", "datetime": "string",
+}
+
+// castNumber converts a number to a numeric type, returning the new value,
+// the reason it lost information and the reason it failed
+func castNumber(value float64, dtype string) (float64, string, string) {
+	switch dtype {
+	case "float32":
+		converted := float64(float32(value))
+		if math.IsInf(converted, 0) && !math.IsInf(value, 0) {
+			return 0, "", "overflows float32"
+		}
+		if converted != value {
+			return converted, "rounded to float32", ""
+		}
+		return converted, "", ""
+	case "int", "int64", "int32":
+		if math.IsInf(value, 0) {
+			return 0, "", "infinity is not an integer"
+		}
+		if dtype == "int32" && (value < math.MinInt32 || value > math.MaxInt32) {
+			return 0, "", "overflows int32"
+		}
+		if value < math.MinInt64 || value >= math.MaxInt64 {
+			return 0, "", "overflows int64"
+		}
+		if truncated := math.Trunc(value); truncated != value {
+			return truncated, "fraction dropped", ""
+		}
+		return value, "", ""
+	case "bool":
+		if value != 0 && value != 1 {
+			return 1, "non zero value converted to 1", ""
+		}
+		return value, "", ""
+	}
+	return value, "", ""
+}
+
+// castString reads a string as a number for a numeric type
+func castString(value string, dtype string) (float64, string, string) {
+	trimmed := strings.TrimSpace(value)
+	if dtype == "bool" {
+		if number, ok := parseBool(trimmed); ok {
+			return number, "", ""
+		}
+	}
+	number, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		return 0, "", "not a number"
+	}
+	if dtype == "int" || dtype == "int64" || dtype == "int32" {
+		// Integers beyond 2^53 do not fit in a float64
+		if integer, err := strconv.ParseInt(trimmed, 10, 64); err == nil && int64(number) != integer {
+			return number, "integer beyond float64 precision", ""
+		}
+	}
+	return castNumber(number, dtype)
+}
+
+// Cast converts the series to "float" (or "float64"), "float32", "int" (or
+// "int64"), "int32", "bool", "string" or "datetime". Numeric types are stored
+// as floats rounded to the type, booleans as 0 and 1 and dates as strings in
+// the layout of the options. Floats cast to datetime are Unix seconds. The
+// report lists the values that lost information or could not be converted.
+func (series *Series) Cast(dtype string, options CastOptions) (CastReport, error) {
+	storage, ok := castTypes[dtype]
+	if !ok {
+		return CastReport{}, fmt.Errorf("unsupported cast type %q", dtype)
+	}
+	if options.Layout == "" {
+		options.Layout = time.RFC3339
+	}
+	nulls := GetConfig().nullValues()
+	length := series.GetLength()
+	texts := series.strings()
+	report := CastReport{}
+	var floats []float64
+	var strs []string
+	if storage == "float" {
+		floats = make([]float64, length)
+	} else {
+		strs = make([]string, length)
+	}
+
+	for i := 0; i < length; i++ {
+		var source string
+		var number float64
+		var lossy, failed string
+		isNull := false
+		if series.DataType == "float" {
+			number = series.Float[i]
+			isNull = math.IsNaN(number)
+			source = strconv.FormatFloat(number, 'f', -1, 64)
+		} else {
+			source = texts[i]
+			isNull = nulls[source]
+		}
+
+		if isNull {
+			report.Nulls++
+			if storage == "float" {
+				floats[i] = math.NaN()
+			} else {
+				strs[i] = "NaN"
+			}
+			continue
+		}
+
+		switch {
+		case storage == "float" && series.DataType == "float":
+			floats[i], lossy, failed = castNumber(number, dtype)
+		case storage == "float":
+			floats[i], lossy, failed = castString(source, dtype)
+		case dtype == "string":
+			strs[i] = source
+		default:
+			var parsed time.Time
+			if series.DataType == "float" {
+				// Years 1 to 9999
+				if number < -62135596800 || number > 253402300799 || math.IsInf(number, 0) {
+					failed = "out of date range"
+					break
+				}
+				seconds, fraction := math.Modf(number)
+				parsed = time.Unix(int64(seconds), int64(fraction*1e9)).UTC()
+			} else if parsed, ok = parseDateTime(source); !ok {
+				failed = "not a date"
+				break
+			}
+			strs[i] = parsed.Format(options.Layout)
+			if reparsed, err := time.Parse(options.Layout, strs[i]); err != nil || !reparsed.Equal(parsed) {
+				lossy = "date truncated by layout"
+			}
+		}
+
+		switch {
+		case failed != "":
+			report.Failed = append(report.Failed, CastIssue{Row: i, Value: source, Reason: failed})
+			if storage == "float" {
+				floats[i] = math.NaN()
+			} else {
+				strs[i] = "NaN"
+			}
+		case lossy != "":
+			report.Lossy = append(report.Lossy, CastIssue{Row: i, Value: source, Reason: lossy})
+			report.Converted++
+		default:
+			report.Converted++
+		}
+		if options.Strict && (failed != "" || lossy != "") {
+			reason := failed + lossy
+			return report, fmt.Errorf("failed to cast value %q at row %d of %q to %s: %s", source, i, series.Name, dtype, reason)
+		}
+	}
+
+	series.invalidateIndexes()
+	if storage == "float" {
+		series.Float = floats
+		series.String = nil
+	} else {
+		series.String = strs
+		series.Float = nil
+	}
+	series.DataType = storage
+	return report, nil
+}
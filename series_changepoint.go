@@ -0,0 +1,146 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// changepointCost is the squared error of a segment around its mean, read
+// from prefix sums of the values and their squares
+type changepointCost struct {
+	sums    []float64
+	squares []float64
+}
+
+func newChangepointCost(values []float64) changepointCost {
+	cost := changepointCost{sums: make([]float64, len(values)+1), squares: make([]float64, len(values)+1)}
+	for i, value := range values {
+		cost.sums[i+1] = cost.sums[i] + value
+		cost.squares[i+1] = cost.squares[i] + value*value
+	}
+	return cost
+}
+
+// segment is the cost of the values from start to end, excluded
+func (cost changepointCost) segment(start, end int) float64 {
+	sum := cost.sums[end] - cost.sums[start]
+	return cost.squares[end] - cost.squares[start] - sum*sum/float64(end-start)
+}
+
+// DetectChangepoints finds the rows where the mean of a float series shifts
+// and returns their indexes, every one starts a new segment. method is
+// "pelt", the exact optimal segmentation, or "binseg", a faster binary
+// segmentation. penalty is the cost of adding a changepoint, larger values
+// find fewer changes, and 0 uses 2 * variance * log(n) with the variance
+// estimated from the differences between rows. Segments have at least 2
+// rows and NaN values are skipped.
+func (series *Series) DetectChangepoints(method string, penalty float64) ([]int, error) {
+	if series.DataType != "float" {
+		return nil, fmt.Errorf("changepoint detection requires a float series")
+	}
+	var values []float64
+	var positions []int
+	for i, value := range series.Float {
+		if !math.IsNaN(value) {
+			values = append(values, value)
+			positions = append(positions, i)
+		}
+	}
+	if method != "pelt" && method != "binseg" {
+		return nil, fmt.Errorf("unknown changepoint method %q, expected \"pelt\" or \"binseg\"", method)
+	}
+	if penalty < 0 {
+		return nil, fmt.Errorf("penalty must not be negative, got %v", penalty)
+	}
+	if len(values) < 4 {
+		return nil, nil
+	}
+	if penalty == 0 {
+		penalty = 2 * noiseVariance(values) * math.Log(float64(len(values)))
+	}
+
+	cost := newChangepointCost(values)
+	var changepoints []int
+	if method == "pelt" {
+		changepoints = pelt(cost, len(values), penalty)
+	} else {
+		changepoints = binarySegmentation(cost, 0, len(values), penalty)
+	}
+	for i, changepoint := range changepoints {
+		changepoints[i] = positions[changepoint]
+	}
+	return changepoints, nil
+}
+
+// noiseVariance estimates the variance of the noise with the median absolute
+// difference between consecutive values, which ignores the mean shifts
+func noiseVariance(values []float64) float64 {
+	differences := make([]float64, len(values)-1)
+	for i := range differences {
+		differences[i] = math.Abs(values[i+1] - values[i])
+	}
+	sort.Float64s(differences)
+	deviation := differences[len(differences)/2] / (0.6745 * math.Sqrt2)
+	if deviation == 0 {
+		// Constant stretches, fall back to a tiny variance so shifts are found
+		return 1e-12
+	}
+	return deviation * deviation
+}
+
+// pelt minimizes the total cost plus penalty per changepoint, pruning the
+// starts that can no longer be optimal
+func pelt(cost changepointCost, n int, penalty float64) []int {
+	const minSize = 2
+	best := make([]float64, n+1)
+	last := make([]int, n+1)
+	best[0] = -penalty
+	candidates := []int{0}
+	for end := minSize; end <= n; end++ {
+		best[end] = math.Inf(1)
+		totals := make([]float64, len(candidates))
+		for i, start := range candidates {
+			totals[i] = math.Inf(1)
+			if end-start >= minSize {
+				totals[i] = best[start] + cost.segment(start, end) + penalty
+				if totals[i] < best[end] {
+					best[end], last[end] = totals[i], start
+				}
+			}
+		}
+		pruned := candidates[:0]
+		for i, start := range candidates {
+			if end-start < minSize || totals[i]-penalty <= best[end] {
+				pruned = append(pruned, start)
+			}
+		}
+		candidates = append(pruned, end)
+	}
+	var changepoints []int
+	for end := last[n]; end > 0; end = last[end] {
+		changepoints = append(changepoints, end)
+	}
+	for i, j := 0, len(changepoints)-1; i < j; i, j = i+1, j-1 {
+		changepoints[i], changepoints[j] = changepoints[j], changepoints[i]
+	}
+	return changepoints
+}
+
+// binarySegmentation splits the values at the row that reduces the cost the
+// most while the reduction is larger than penalty
+func binarySegmentation(cost changepointCost, start, end int, penalty float64) []int {
+	const minSize = 2
+	whole := cost.segment(start, end)
+	bestSplit, bestGain := -1, penalty
+	for split := start + minSize; split <= end-minSize; split++ {
+		if gain := whole - cost.segment(start, split) - cost.segment(split, end); gain > bestGain {
+			bestSplit, bestGain = split, gain
+		}
+	}
+	if bestSplit < 0 {
+		return nil
+	}
+	changepoints := append(binarySegmentation(cost, start, bestSplit, penalty), bestSplit)
+	return append(changepoints, binarySegmentation(cost, bestSplit, end, penalty)...)
+}
@@ -0,0 +1,225 @@
+package grizzly
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// compressionChunkSize is the number of values compressed together, reading
+// one value decompresses only its chunk
+const compressionChunkSize = 4096
+
+// compressedStrings holds the values of a compressed string series as
+// deflate chunks. It is never modified after it is built, so copies of the
+// series share it.
+type compressedStrings struct {
+	length int
+	chunks [][]byte
+
+	// The last chunk read by GetValueString
+	mutex       sync.Mutex
+	cachedChunk int
+	cached      []string
+}
+
+func compressChunk(values []string) ([]byte, error) {
+	var raw []byte
+	for _, value := range values {
+		raw = binary.AppendUvarint(raw, uint64(len(value)))
+		raw = append(raw, value...)
+	}
+	var buffer bytes.Buffer
+	writer, err := flate.NewWriter(&buffer, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = writer.Write(raw); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func decompressChunk(chunk []byte, values []string) error {
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(chunk)))
+	if err != nil {
+		return err
+	}
+	for i := range values {
+		size, read := binary.Uvarint(raw)
+		if read <= 0 || uint64(len(raw)-read) < size {
+			return fmt.Errorf("corrupted compressed chunk")
+		}
+		values[i] = string(raw[read : read+int(size)])
+		raw = raw[read+int(size):]
+	}
+	return nil
+}
+
+// chunk returns the values of a chunk, reusing the last one read
+func (compressed *compressedStrings) chunk(index int) ([]string, error) {
+	compressed.mutex.Lock()
+	defer compressed.mutex.Unlock()
+	if compressed.cached != nil && compressed.cachedChunk == index {
+		return compressed.cached, nil
+	}
+	size := min(compressionChunkSize, compressed.length-index*compressionChunkSize)
+	values := make([]string, size)
+	if err := decompressChunk(compressed.chunks[index], values); err != nil {
+		return nil, err
+	}
+	compressed.cachedChunk = index
+	compressed.cached = values
+	return values, nil
+}
+
+// decompress returns every value, decompressing the chunks in parallel
+func (compressed *compressedStrings) decompress() ([]string, error) {
+	values := make([]string, compressed.length)
+	errs := make([]error, len(compressed.chunks))
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, workerCount())
+	for i, chunk := range compressed.chunks {
+		wg.Add(1)
+		go func(i int, chunk []byte) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			start := i * compressionChunkSize
+			end := min(start+compressionChunkSize, compressed.length)
+			errs[i] = decompressChunk(chunk, values[start:end])
+		}(i, chunk)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Compress stores the values of a string series as deflate chunks and frees
+// the uncompressed values, trading CPU for memory on cold text columns.
+// GetValueString decompresses only the chunk of the value, Strings returns
+// every value and operations modifying the series decompress it back. Code
+// reading the String field directly must call Decompress first.
+func (series *Series) Compress() error {
+	if series.compressed != nil {
+		return nil
+	}
+	if series.DataType != "string" {
+		return fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	length := len(series.String)
+	chunks := make([][]byte, (length+compressionChunkSize-1)/compressionChunkSize)
+	errs := make([]error, len(chunks))
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, workerCount())
+	for i := range chunks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			start := i * compressionChunkSize
+			end := min(start+compressionChunkSize, length)
+			chunks[i], errs[i] = compressChunk(series.String[start:end])
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("failed to compress series %q: %w", series.Name, err)
+		}
+	}
+	series.invalidateIndexes()
+	series.compressed = &compressedStrings{length: length, chunks: chunks}
+	series.String = nil
+	return nil
+}
+
+// Decompress restores the values of a compressed series
+func (series *Series) Decompress() error {
+	if series.compressed == nil {
+		return nil
+	}
+	values, err := series.compressed.decompress()
+	if err != nil {
+		return fmt.Errorf("failed to decompress series %q: %w", series.Name, err)
+	}
+	series.String = values
+	series.compressed = nil
+	return nil
+}
+
+func (series *Series) IsCompressed() bool {
+	return series.compressed != nil
+}
+
+// CompressedSize returns the bytes used by the compressed values, 0 when
+// the series is not compressed
+func (series *Series) CompressedSize() int {
+	if series.compressed == nil {
+		return 0
+	}
+	size := 0
+	for _, chunk := range series.compressed.chunks {
+		size += len(chunk)
+	}
+	return size
+}
+
+// decompressedColumns returns the columns of df with the compressed ones
+// decompressed into new series, leaving df unchanged. Operations reading
+// every row use it instead of decompressing a chunk per value.
+func (df *DataFrame) decompressedColumns() []Series {
+	columns := df.Columns
+	for i := range df.Columns {
+		if df.Columns[i].compressed == nil {
+			continue
+		}
+		if &columns[0] == &df.Columns[0] {
+			columns = append([]Series{}, df.Columns...)
+		}
+		columns[i].String = df.Columns[i].strings()
+		columns[i].compressed = nil
+	}
+	return columns
+}
+
+// strings returns the values of a string series without changing it,
+// decompressing them when the series is compressed
+func (series *Series) strings() []string {
+	if series.compressed == nil {
+		return series.String
+	}
+	values, err := series.compressed.decompress()
+	if err != nil {
+		panic(fmt.Sprintf("failed to decompress series %q: %v", series.Name, err))
+	}
+	return values
+}
+
+// CompressColumn compresses a string column in memory, see Series.Compress
+func (df *DataFrame) CompressColumn(identifier any) error {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to compress column %v: %w", identifier, err)
+	}
+	return series.Compress()
+}
+
+func (df *DataFrame) DecompressColumn(identifier any) error {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to decompress column %v: %w", identifier, err)
+	}
+	return series.Decompress()
+}
@@ -0,0 +1,236 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// arrayConditionalSum adds the non NaN values of the rows passing test in a
+// single parallel pass, returning the sum and the number of values added
+func arrayConditionalSum(data []float64, test func(index int) bool) (float64, int) {
+	length := len(data)
+	if length == 0 {
+		return 0, 0
+	}
+	numGoroutines := workerCount()
+	if numGoroutines > length {
+		numGoroutines = length
+	}
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	sums := make([]float64, numGoroutines)
+	counts := make([]int, numGoroutines)
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end, g int) {
+			defer wg.Done()
+			var sum float64
+			var count int
+			for i := start; i < end; i++ {
+				if math.IsNaN(data[i]) || !test(i) {
+					continue
+				}
+				sum += data[i]
+				count++
+			}
+			sums[g] = sum
+			counts[g] = count
+		}(start, end, g)
+	}
+	wg.Wait()
+
+	var sum float64
+	var count int
+	for g := range sums {
+		sum += sums[g]
+		count += counts[g]
+	}
+	return sum, count
+}
+
+// arrayConditionalCount counts the rows passing test in parallel
+func arrayConditionalCount(length int, test func(index int) bool) int {
+	if length == 0 {
+		return 0
+	}
+	numGoroutines := workerCount()
+	if numGoroutines > length {
+		numGoroutines = length
+	}
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	counts := make([]int, numGoroutines)
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end, g int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				if test(i) {
+					counts[g]++
+				}
+			}
+		}(start, end, g)
+	}
+	wg.Wait()
+
+	var count int
+	for _, partial := range counts {
+		count += partial
+	}
+	return count
+}
+
+func (series *Series) SumWhere(mask []bool) (float64, error) {
+	if series.DataType != "float" {
+		return 0, fmt.Errorf("to get sum select a float column")
+	}
+	if len(mask) != series.GetLength() {
+		return 0, fmt.Errorf("mask has length %d, series %q has length %d", len(mask), series.Name, series.GetLength())
+	}
+	sum, _ := arrayConditionalSum(series.Float, func(i int) bool { return mask[i] })
+	return sum, nil
+}
+
+func (series *Series) MeanWhere(mask []bool) (float64, error) {
+	if series.DataType != "float" {
+		return 0, fmt.Errorf("to get mean select a float column")
+	}
+	if len(mask) != series.GetLength() {
+		return 0, fmt.Errorf("mask has length %d, series %q has length %d", len(mask), series.Name, series.GetLength())
+	}
+	sum, count := arrayConditionalSum(series.Float, func(i int) bool { return mask[i] })
+	if count == 0 {
+		return math.NaN(), nil
+	}
+	return sum / float64(count), nil
+}
+
+func (series *Series) CountWhere(mask []bool) (float64, error) {
+	if len(mask) != series.GetLength() {
+		return 0, fmt.Errorf("mask has length %d, series %q has length %d", len(mask), series.Name, series.GetLength())
+	}
+	return float64(arrayConditionalCount(len(mask), func(i int) bool { return mask[i] })), nil
+}
+
+// conditionTest builds the row test of a float condition column
+func (df *DataFrame) conditionTest(identifier any, condition func(value float64) bool) (func(int) bool, error) {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return nil, err
+	}
+	if series.DataType != "float" {
+		return nil, fmt.Errorf("column %v is not of type float; actual type is %q", identifier, series.DataType)
+	}
+	return func(i int) bool { return condition(series.Float[i]) }, nil
+}
+
+// conditionTestString builds the row test of a string condition column
+func (df *DataFrame) conditionTestString(identifier any, condition func(value string) bool) (func(int) bool, error) {
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return nil, err
+	}
+	if series.DataType != "string" {
+		return nil, fmt.Errorf("column %v is not of type string; actual type is %q", identifier, series.DataType)
+	}
+	values := series.strings()
+	return func(i int) bool { return condition(values[i]) }, nil
+}
+
+func (df *DataFrame) conditionalSum(valueIdentifier any, test func(int) bool) (float64, int, error) {
+	series, err := df.GetColumnDynamic(valueIdentifier)
+	if err != nil {
+		return 0, 0, err
+	}
+	if series.DataType != "float" {
+		return 0, 0, fmt.Errorf("column %v is not of type float; actual type is %q", valueIdentifier, series.DataType)
+	}
+	sum, count := arrayConditionalSum(series.Float, test)
+	return sum, count, nil
+}
+
+// CountIf counts the rows whose float column passes the condition
+func (df *DataFrame) CountIf(identifier any, condition func(value float64) bool) (float64, error) {
+	test, err := df.conditionTest(identifier, condition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count column %v: %w", identifier, err)
+	}
+	return float64(arrayConditionalCount(df.GetLength(), test)), nil
+}
+
+// CountIfString counts the rows whose string column passes the condition
+func (df *DataFrame) CountIfString(identifier any, condition func(value string) bool) (float64, error) {
+	test, err := df.conditionTestString(identifier, condition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count column %v: %w", identifier, err)
+	}
+	return float64(arrayConditionalCount(df.GetLength(), test)), nil
+}
+
+// SumIf adds the values of a float column on the rows where the condition
+// column passes the condition, without building a filtered copy
+func (df *DataFrame) SumIf(valueIdentifier any, conditionIdentifier any, condition func(value float64) bool) (float64, error) {
+	test, err := df.conditionTest(conditionIdentifier, condition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum column %v: %w", valueIdentifier, err)
+	}
+	sum, _, err := df.conditionalSum(valueIdentifier, test)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum column %v: %w", valueIdentifier, err)
+	}
+	return sum, nil
+}
+
+func (df *DataFrame) SumIfString(valueIdentifier any, conditionIdentifier any, condition func(value string) bool) (float64, error) {
+	test, err := df.conditionTestString(conditionIdentifier, condition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum column %v: %w", valueIdentifier, err)
+	}
+	sum, _, err := df.conditionalSum(valueIdentifier, test)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum column %v: %w", valueIdentifier, err)
+	}
+	return sum, nil
+}
+
+// MeanIf is NaN when no row passes the condition
+func (df *DataFrame) MeanIf(valueIdentifier any, conditionIdentifier any, condition func(value float64) bool) (float64, error) {
+	test, err := df.conditionTest(conditionIdentifier, condition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mean of column %v: %w", valueIdentifier, err)
+	}
+	sum, count, err := df.conditionalSum(valueIdentifier, test)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mean of column %v: %w", valueIdentifier, err)
+	}
+	if count == 0 {
+		return math.NaN(), nil
+	}
+	return sum / float64(count), nil
+}
+
+func (df *DataFrame) MeanIfString(valueIdentifier any, conditionIdentifier any, condition func(value string) bool) (float64, error) {
+	test, err := df.conditionTestString(conditionIdentifier, condition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mean of column %v: %w", valueIdentifier, err)
+	}
+	sum, count, err := df.conditionalSum(valueIdentifier, test)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mean of column %v: %w", valueIdentifier, err)
+	}
+	if count == 0 {
+		return math.NaN(), nil
+	}
+	return sum / float64(count), nil
+}
@@ -0,0 +1,151 @@
+package grizzly
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// phoneRegion holds the country calling code and the national trunk prefix
+// dropped when a local number is written in international format
+type phoneRegion struct {
+	code  string
+	trunk string
+}
+
+var phoneRegions = map[string]phoneRegion{
+	"US": {"1", "1"}, "CA": {"1", "1"}, "MX": {"52", ""}, "BR": {"55", "0"}, "AR": {"54", "0"},
+	"CL": {"56", ""}, "CO": {"57", ""}, "PE": {"51", "0"}, "EC": {"593", "0"}, "BO": {"591", "0"},
+	"VE": {"58", "0"}, "UY": {"598", "0"}, "PY": {"595", "0"}, "GB": {"44", "0"}, "IE": {"353", "0"},
+	"DE": {"49", "0"}, "FR": {"33", "0"}, "ES": {"34", ""}, "PT": {"351", ""}, "IT": {"39", ""},
+	"NL": {"31", "0"}, "BE": {"32", "0"}, "CH": {"41", "0"}, "AT": {"43", "0"}, "SE": {"46", "0"},
+	"NO": {"47", ""}, "DK": {"45", ""}, "FI": {"358", "0"}, "PL": {"48", ""}, "RU": {"7", "8"},
+	"TR": {"90", "0"}, "IN": {"91", "0"}, "CN": {"86", "0"}, "JP": {"81", "0"}, "KR": {"82", "0"},
+	"AU": {"61", "0"}, "NZ": {"64", "0"}, "ZA": {"27", "0"}, "NG": {"234", "0"}, "EG": {"20", "0"},
+}
+
+// seriesMapString builds a new string series from every value of a string
+// series, in parallel chunks
+func seriesMapString(series *Series, name string, operation func(string) string) (Series, error) {
+	if series.DataType != "string" {
+		return Series{}, fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	values := append([]string{}, series.strings()...)
+	arrayApplyString(values, operation)
+	return NewStringSeries(name, values), nil
+}
+
+// seriesMask evaluates condition on every value of a string series
+func seriesMask(series *Series, condition func(string) bool) ([]bool, error) {
+	if series.DataType != "string" {
+		return nil, fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	values := series.strings()
+	mask := make([]bool, len(values))
+	parallelChunks(len(values), func(_, start, end int) {
+		for j := start; j < end; j++ {
+			mask[j] = condition(values[j])
+		}
+	})
+	return mask, nil
+}
+
+// extractDomain returns the lower case host of a URL or the domain of an
+// email address without a leading "www."
+func extractDomain(value string) string {
+	value = strings.TrimSpace(value)
+	var host string
+	if at := strings.LastIndex(value, "@"); at >= 0 && !strings.Contains(value, "://") {
+		host = strings.TrimRight(value[at+1:], ">)]")
+	} else {
+		if !strings.Contains(value, "://") {
+			value = "http://" + value
+		}
+		parsed, err := url.Parse(value)
+		if err != nil {
+			return "NaN"
+		}
+		host = parsed.Hostname()
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	host = strings.TrimPrefix(host, "www.")
+	if host == "" || host == "nan" {
+		return "NaN"
+	}
+	return host
+}
+
+func isValidEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Name != "" || address.Address != strings.TrimSpace(value) {
+		return false
+	}
+	at := strings.LastIndex(address.Address, "@")
+	domain := address.Address[at+1:]
+	return strings.Contains(domain, ".") && !strings.HasPrefix(domain, ".") && !strings.HasSuffix(domain, ".") &&
+		!strings.Contains(domain, "..")
+}
+
+// normalizePhone writes a number in E.164 format (+CCNNNN), local numbers
+// take the calling code of region. Invalid numbers become NaN.
+func normalizePhone(value string, region phoneRegion) string {
+	value = strings.TrimSpace(value)
+	// Drop extensions like "x123" or "ext. 4"
+	lower := strings.ToLower(value)
+	for _, marker := range []string{"ext", "x", "#"} {
+		if index := strings.Index(lower, marker); index > 0 {
+			value = value[:index]
+			break
+		}
+	}
+	international := strings.HasPrefix(value, "+")
+	var digits strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	number := digits.String()
+	if !international && strings.HasPrefix(number, "00") {
+		international = true
+		number = number[2:]
+	}
+	if !international {
+		if region.trunk != "" && strings.HasPrefix(number, region.trunk) {
+			number = number[len(region.trunk):]
+		}
+		number = region.code + number
+	}
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "NaN"
+	}
+	return "+" + number
+}
+
+// ExtractDomain returns a string series with the domain of every URL or email
+// address, NaN when there is none
+func (series *Series) ExtractDomain() (Series, error) {
+	return seriesMapString(series, series.Name+"_domain", extractDomain)
+}
+
+// IsValidEmail returns a mask with true for well formed email addresses
+func (series *Series) IsValidEmail() ([]bool, error) {
+	return seriesMask(series, isValidEmail)
+}
+
+// NormalizePhone returns a string series with the phone numbers in E.164
+// format. region is the ISO 3166 code used for numbers without a country
+// calling code, like "US" or "GB".
+func (series *Series) NormalizePhone(region string) (Series, error) {
+	settings, ok := phoneRegions[strings.ToUpper(region)]
+	if !ok {
+		return Series{}, fmt.Errorf("unsupported phone region %q", region)
+	}
+	return seriesMapString(series, series.Name+"_phone", func(value string) string {
+		if value == "NaN" {
+			return value
+		}
+		return normalizePhone(value, settings)
+	})
+}
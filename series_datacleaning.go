@@ -1,17 +1,21 @@
 package grizzly
 
 import (
+	"fmt"
 	"math"
-	"runtime"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 )
 
 func (series *Series) FillNaN(newValue float64) {
+	series.invalidateIndexes()
 	if series.DataType == "string" {
 		return
 	}
 
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	length := series.GetLength()
 	chunkSize := (length + numGoroutines - 1) / numGoroutines
 	var wg sync.WaitGroup
@@ -36,26 +40,31 @@ func (series *Series) FillNaN(newValue float64) {
 }
 
 func (series *Series) DropNaN() {
+	series.invalidateIndexes()
 	if series.DataType == "string" {
 		return // No-op for string data
 	}
 
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	length := series.GetLength()
 	chunkSize := (length + numGoroutines - 1) / numGoroutines
 
-	// Channel to collect non-NaN values
-	resultChan := make(chan []float64, numGoroutines)
+	// Non-NaN values of every chunk, joined in order to keep the order of
+	// the values
+	chunks := make([][]float64, numGoroutines)
 
 	var wg sync.WaitGroup
 	for i := 0; i < numGoroutines; i++ {
 		start := i * chunkSize
 		end := start + chunkSize
+		if start >= length {
+			break
+		}
 		if end > length {
 			end = length
 		}
 		wg.Add(1)
-		go func(start, end int) {
+		go func(chunk, start, end int) {
 			defer wg.Done()
 			nonNaN := make([]float64, 0, end-start) // Temporary slice for non-NaN values
 			for j := start; j < end; j++ {
@@ -63,22 +72,145 @@ func (series *Series) DropNaN() {
 					nonNaN = append(nonNaN, series.Float[j])
 				}
 			}
-			resultChan <- nonNaN
-		}(start, end)
+			chunks[chunk] = nonNaN
+		}(i, start, end)
 	}
-
-	// Close the channel when all workers are done
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
+	wg.Wait()
 
 	// Collect results from all goroutines
 	result := make([]float64, 0, length)
-	for nonNaN := range resultChan {
+	for _, nonNaN := range chunks {
 		result = append(result, nonNaN...)
 	}
 
 	// Update the Series with the filtered values
 	series.Float = result
 }
+
+// CoercionFailure is a value CoerceNumeric could not read as a number
+type CoercionFailure struct {
+	Row    int
+	Value  string
+	Reason string
+}
+
+// CoercionReport describes what CoerceNumeric did with every value
+type CoercionReport struct {
+	// Parsed values were already plain numbers
+	Parsed int
+	// Repaired values were read after cleaning them
+	Repaired int
+	// Nulls are null tokens of the Config
+	Nulls    int
+	Failures []CoercionFailure
+}
+
+var thousandsPattern = regexp.MustCompile(`^\d{1,3}([,. ']\d{3})+([.,]\d+)?$`)
+
+// coerceNumber reads a number written by people: surrounding spaces,
+// currency symbols, thousands separators ("1,234" or "1.234,5"), accounting
+// negatives ("(500)"), trailing signs ("500-") and percentages ("12%" is
+// 0.12). It returns the reason when value can not be read.
+func coerceNumber(value string) (float64, string) {
+	cleaned := strings.TrimSpace(strings.ReplaceAll(value, "\u00a0", " "))
+	if cleaned == "" {
+		return math.NaN(), "empty value"
+	}
+	negative := false
+	if strings.HasPrefix(cleaned, "(") && strings.HasSuffix(cleaned, ")") {
+		negative = true
+		cleaned = strings.TrimSpace(cleaned[1 : len(cleaned)-1])
+	}
+	cleaned = strings.TrimFunc(cleaned, func(r rune) bool {
+		return strings.ContainsRune("$€£¥₹", r) || r == ' '
+	})
+	percent := strings.HasSuffix(cleaned, "%")
+	cleaned = strings.TrimSpace(strings.TrimSuffix(cleaned, "%"))
+	cleaned = strings.ReplaceAll(cleaned, "\u2212", "-")
+	if strings.HasSuffix(cleaned, "-") {
+		negative = !negative
+		cleaned = cleaned[:len(cleaned)-1]
+	} else if strings.HasPrefix(cleaned, "-") {
+		negative = !negative
+		cleaned = cleaned[1:]
+	} else {
+		cleaned = strings.TrimPrefix(cleaned, "+")
+	}
+	cleaned = strings.TrimFunc(cleaned, func(r rune) bool {
+		return strings.ContainsRune("$€£¥₹", r) || r == ' '
+	})
+
+	if thousandsPattern.MatchString(cleaned) {
+		// The first separator groups the thousands, a different one after
+		// the last group starts the decimals
+		separator := string(cleaned[strings.IndexAny(cleaned, ",. '")])
+		integer, fraction := cleaned, ""
+		if last := strings.LastIndexAny(cleaned, ".,"); last >= 0 && string(cleaned[last]) != separator {
+			integer, fraction = cleaned[:last], cleaned[last+1:]
+		}
+		// A single dot is read as the decimal point, like ParseFloat does
+		if !(separator == "." && fraction == "" && strings.Count(integer, ".") == 1) {
+			cleaned = strings.ReplaceAll(integer, separator, "")
+			if fraction != "" {
+				cleaned += "." + fraction
+			}
+		}
+	} else if strings.Count(cleaned, ",") == 1 && !strings.Contains(cleaned, ".") {
+		// Decimal comma like "3,5"
+		cleaned = strings.Replace(cleaned, ",", ".", 1)
+	}
+
+	number, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || math.IsNaN(number) {
+		return math.NaN(), fmt.Sprintf("%q is not a number", value)
+	}
+	if negative {
+		number = -number
+	}
+	if percent {
+		number /= 100
+	}
+	return number, ""
+}
+
+// CoerceNumeric converts a string series with mixed formats to float as well
+// as it can, values that can not be read become NaN. When report is not nil
+// it receives the counts and the rows that could not be fixed.
+func (series *Series) CoerceNumeric(report *CoercionReport) error {
+	if series.DataType == "float" {
+		return nil
+	}
+	if series.DataType != "string" {
+		return fmt.Errorf("series %q has unsupported type %q", series.Name, series.DataType)
+	}
+	series.invalidateIndexes()
+	nulls := GetConfig().nullValues()
+	values := make([]float64, len(series.String))
+	result := CoercionReport{}
+	for i, value := range series.String {
+		if nulls[value] {
+			values[i] = math.NaN()
+			result.Nulls++
+			continue
+		}
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			values[i] = number
+			result.Parsed++
+			continue
+		}
+		number, reason := coerceNumber(value)
+		values[i] = number
+		if reason != "" {
+			result.Failures = append(result.Failures, CoercionFailure{Row: i, Value: value, Reason: reason})
+		} else {
+			result.Repaired++
+		}
+	}
+	series.Float = values
+	series.String = nil
+	series.DataType = "float"
+	if report != nil {
+		*report = result
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+)
+
+// Decomposition holds the additive components of a series, the trend, the
+// seasonal pattern and the residual left by both
+type Decomposition struct {
+	Trend    Series
+	Seasonal Series
+	Residual Series
+}
+
+// Decompose splits a float series into trend, seasonal and residual series
+// named "<name>_trend", "<name>_seasonal" and "<name>_residual" that add up
+// to the values, with the classical moving average decomposition rather
+// than STL. The trend is a centered moving average over one period, so the
+// first and last period/2 rows of the trend and residual are NaN. The
+// seasonal value of every position in the period is the mean of the
+// detrended values at that position, shifted so a full period sums 0.
+// NaN values are skipped: the trend averages the values present in its
+// window, and positions without any detrended value have a NaN seasonal.
+func (series *Series) Decompose(period int) (Decomposition, error) {
+	if series.DataType != "float" {
+		return Decomposition{}, fmt.Errorf("decomposition requires a float series")
+	}
+	if period < 2 {
+		return Decomposition{}, fmt.Errorf("period must be at least 2, got %d", period)
+	}
+	values := series.Float
+	if len(values) < 2*period {
+		return Decomposition{}, fmt.Errorf("decomposition requires at least two periods, got %d rows for period %d", len(values), period)
+	}
+
+	// Even periods average two windows so the trend stays centered
+	weights := make([]float64, period+1-period%2)
+	for i := range weights {
+		weights[i] = 1 / float64(period)
+	}
+	if period%2 == 0 {
+		weights[0], weights[period] = 0.5/float64(period), 0.5/float64(period)
+	}
+	half := len(weights) / 2
+	trend := make([]float64, len(values))
+	for i := range values {
+		trend[i] = math.NaN()
+		if i < half || i+half >= len(values) {
+			continue
+		}
+		sum, total := 0.0, 0.0
+		for j, weight := range weights {
+			if value := values[i-half+j]; !math.IsNaN(value) {
+				sum += weight * value
+				total += weight
+			}
+		}
+		if total > 0 {
+			trend[i] = sum / total
+		}
+	}
+
+	sums := make([]float64, period)
+	counts := make([]float64, period)
+	for i, value := range values {
+		if detrended := value - trend[i]; !math.IsNaN(detrended) {
+			sums[i%period] += detrended
+			counts[i%period]++
+		}
+	}
+	pattern := make([]float64, period)
+	offset, positions := 0.0, 0.0
+	for i := range pattern {
+		pattern[i] = math.NaN()
+		if counts[i] > 0 {
+			pattern[i] = sums[i] / counts[i]
+			offset += pattern[i]
+			positions++
+		}
+	}
+	if positions > 0 {
+		offset /= positions
+	}
+	seasonal := make([]float64, len(values))
+	residual := make([]float64, len(values))
+	for i, value := range values {
+		seasonal[i] = pattern[i%period] - offset
+		residual[i] = value - trend[i] - seasonal[i]
+	}
+	return Decomposition{
+		Trend:    NewFloatSeries(series.Name+"_trend", trend),
+		Seasonal: NewFloatSeries(series.Name+"_seasonal", seasonal),
+		Residual: NewFloatSeries(series.Name+"_residual", residual),
+	}, nil
+}
+
+// Forecast holds the predicted values of the next rows of a series and the
+// bounds of their confidence interval
+type Forecast struct {
+	Prediction Series
+	Lower      Series
+	Upper      Series
+}
+
+// newForecast builds the forecast series, the interval widens with the
+// square root of the steps ahead when widen is set
+func newForecast(name string, predictions []float64, sigma float64, confidence float64, widen bool) Forecast {
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	lower := make([]float64, len(predictions))
+	upper := make([]float64, len(predictions))
+	for h, prediction := range predictions {
+		margin := z * sigma
+		if widen {
+			margin *= math.Sqrt(float64(h + 1))
+		}
+		lower[h] = prediction - margin
+		upper[h] = prediction + margin
+	}
+	return Forecast{
+		Prediction: NewFloatSeries(name+"_forecast", predictions),
+		Lower:      NewFloatSeries(name+"_lower", lower),
+		Upper:      NewFloatSeries(name+"_upper", upper),
+	}
+}
+
+// forecastInput checks the arguments shared by every forecast and returns
+// the values without NaN
+func forecastInput(series *Series, horizon int, confidence float64, minimum int) ([]float64, error) {
+	if horizon <= 0 {
+		return nil, fmt.Errorf("horizon must be positive, got %d", horizon)
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return nil, fmt.Errorf("confidence must be between 0 and 1, got %v", confidence)
+	}
+	values, err := nonNullFloats(series)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) < minimum {
+		return nil, fmt.Errorf("forecast requires at least %d values, got %d", minimum, len(values))
+	}
+	return values, nil
+}
+
+// residualDeviation is the root mean square of the one step errors
+func residualDeviation(errors []float64) float64 {
+	sum := 0.0
+	for _, value := range errors {
+		sum += value * value
+	}
+	return math.Sqrt(sum / float64(len(errors)))
+}
+
+// ForecastNaive predicts the last value for the next horizon rows. The
+// interval, at the given confidence like 0.95, follows a random walk with the
+// deviation of the changes between rows. NaN values are skipped.
+func (series *Series) ForecastNaive(horizon int, confidence float64) (Forecast, error) {
+	values, err := forecastInput(series, horizon, confidence, 2)
+	if err != nil {
+		return Forecast{}, fmt.Errorf("failed to forecast: %w", err)
+	}
+	errors := make([]float64, len(values)-1)
+	for i := range errors {
+		errors[i] = values[i+1] - values[i]
+	}
+	predictions := arrayResizeFloat(nil, horizon, values[len(values)-1])
+	return newForecast(series.Name, predictions, residualDeviation(errors), confidence, true), nil
+}
+
+// ForecastMovingAverage predicts the mean of the last window values for the
+// next horizon rows. The interval uses the deviation of the one step errors
+// of the moving average over the history. NaN values are skipped.
+func (series *Series) ForecastMovingAverage(window int, horizon int, confidence float64) (Forecast, error) {
+	if window < 1 {
+		return Forecast{}, fmt.Errorf("failed to forecast: window must be positive, got %d", window)
+	}
+	values, err := forecastInput(series, horizon, confidence, window+1)
+	if err != nil {
+		return Forecast{}, fmt.Errorf("failed to forecast: %w", err)
+	}
+	sum := 0.0
+	for _, value := range values[:window] {
+		sum += value
+	}
+	errors := make([]float64, 0, len(values)-window)
+	for i := window; i < len(values); i++ {
+		errors = append(errors, values[i]-sum/float64(window))
+		sum += values[i] - values[i-window]
+	}
+	predictions := arrayResizeFloat(nil, horizon, sum/float64(window))
+	return newForecast(series.Name, predictions, residualDeviation(errors), confidence, false), nil
+}
+
+// HoltWintersOptions are the smoothing factors between 0 and 1 of the level
+// (Alpha), the trend (Beta) and the season (Gamma), and the number of rows
+// of a season (Period). Zero factors use 0.5, 0.1 and 0.1, and a Period of 0
+// or 1 leaves out the season.
+type HoltWintersOptions struct {
+	Alpha  float64
+	Beta   float64
+	Gamma  float64
+	Period int
+}
+
+// ForecastHoltWinters predicts the next horizon rows with additive triple
+// exponential smoothing, or Holt's linear trend without a season. The
+// interval widens with the deviation of the one step errors over the
+// history. NaN values are skipped.
+func (series *Series) ForecastHoltWinters(options HoltWintersOptions, horizon int, confidence float64) (Forecast, error) {
+	alpha, beta, gamma, period := options.Alpha, options.Beta, options.Gamma, options.Period
+	if alpha == 0 {
+		alpha = 0.5
+	}
+	if beta == 0 {
+		beta = 0.1
+	}
+	if gamma == 0 {
+		gamma = 0.1
+	}
+	for _, factor := range []float64{alpha, beta, gamma} {
+		if factor < 0 || factor > 1 {
+			return Forecast{}, fmt.Errorf("failed to forecast: smoothing factors must be between 0 and 1, got %v", factor)
+		}
+	}
+	if period < 2 {
+		period = 1
+	}
+	values, err := forecastInput(series, horizon, confidence, max(2*period, 2))
+	if err != nil {
+		return Forecast{}, fmt.Errorf("failed to forecast: %w", err)
+	}
+
+	// The means of the first two seasons set the trend, the level is the
+	// trend line at the end of the first season and the season is what the
+	// trend line does not explain
+	season := make([]float64, period)
+	level, trend := values[0], values[1]-values[0]
+	if period > 1 {
+		first, second := 0.0, 0.0
+		for i := 0; i < period; i++ {
+			first += values[i] / float64(period)
+			second += values[period+i] / float64(period)
+		}
+		trend = (second - first) / float64(period)
+		middle := float64(period-1) / 2
+		level = first + middle*trend
+		for i := range season {
+			season[i] = values[i] - (first + (float64(i)-middle)*trend)
+		}
+	}
+
+	var errors []float64
+	for i := period; i < len(values); i++ {
+		position := i % period
+		seasonal := 0.0
+		if period > 1 {
+			seasonal = season[position]
+		}
+		errors = append(errors, values[i]-(level+trend+seasonal))
+		previous := level
+		level = alpha*(values[i]-seasonal) + (1-alpha)*(level+trend)
+		trend = beta*(level-previous) + (1-beta)*trend
+		if period > 1 {
+			season[position] = gamma*(values[i]-level) + (1-gamma)*seasonal
+		}
+	}
+
+	predictions := make([]float64, horizon)
+	for h := range predictions {
+		predictions[h] = level + float64(h+1)*trend
+		if period > 1 {
+			predictions[h] += season[(len(values)+h)%period]
+		}
+	}
+	return newForecast(series.Name, predictions, residualDeviation(errors), confidence, true), nil
+}
@@ -0,0 +1,189 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Histogram counts the values of the series in bins and returns a DataFrame
+// with the columns "bin_start" and "count". Float series take the number of
+// equal width bins between the min and the max (int) or the bin edges
+// ([]float64). String series are read as dates and take a bin width
+// (time.Duration, bins are aligned to multiples of the width) or the bin
+// edges ([]time.Time), their bin_start is written in RFC 3339. Every bin
+// includes its start, the last one also includes its end. Nulls and values
+// outside the edges are not counted.
+func (series *Series) Histogram(bins any) (DataFrame, error) {
+	var values []float64
+	var edges []float64
+	dates := series.DataType == "string"
+
+	if dates {
+		values = make([]float64, series.GetLength())
+		for i, value := range series.strings() {
+			if parsed, ok := parseDateTime(value); ok {
+				values[i] = float64(parsed.UnixNano()) / 1e9
+			} else {
+				values[i] = math.NaN()
+			}
+		}
+	} else {
+		values = series.Float
+	}
+	low, high := histogramRange(values)
+
+	switch value := bins.(type) {
+	case int:
+		if dates {
+			return DataFrame{}, fmt.Errorf("histogram of dates in %q needs a time.Duration or []time.Time", series.Name)
+		}
+		if value <= 0 {
+			return DataFrame{}, fmt.Errorf("number of bins must be positive, got %d", value)
+		}
+		if math.IsNaN(low) {
+			low, high = 0, 1
+		}
+		if low == high {
+			low, high = low-0.5, high+0.5
+		}
+		edges = make([]float64, value+1)
+		width := (high - low) / float64(value)
+		for i := range edges {
+			edges[i] = low + float64(i)*width
+		}
+		edges[value] = high
+	case []float64:
+		if dates {
+			return DataFrame{}, fmt.Errorf("histogram of dates in %q needs a time.Duration or []time.Time", series.Name)
+		}
+		edges = value
+	case time.Duration:
+		if !dates {
+			return DataFrame{}, fmt.Errorf("histogram of %q needs a number of bins or []float64 edges", series.Name)
+		}
+		if value <= 0 {
+			return DataFrame{}, fmt.Errorf("bin width must be positive, got %v", value)
+		}
+		if math.IsNaN(low) {
+			break
+		}
+		start := time.Unix(0, int64(low*1e9)).UTC().Truncate(value)
+		for edge := start; ; edge = edge.Add(value) {
+			edges = append(edges, float64(edge.UnixNano())/1e9)
+			if float64(edge.UnixNano())/1e9 > high {
+				break
+			}
+		}
+	case []time.Time:
+		if !dates {
+			return DataFrame{}, fmt.Errorf("histogram of %q needs a number of bins or []float64 edges", series.Name)
+		}
+		edges = make([]float64, len(value))
+		for i, edge := range value {
+			edges[i] = float64(edge.UnixNano()) / 1e9
+		}
+	default:
+		return DataFrame{}, fmt.Errorf("unsupported histogram bins type %T", bins)
+	}
+	if len(edges) == 1 {
+		return DataFrame{}, fmt.Errorf("histogram needs at least two edges")
+	}
+	for i := 1; i < len(edges); i++ {
+		if !(edges[i] > edges[i-1]) {
+			return DataFrame{}, fmt.Errorf("histogram edges must be increasing")
+		}
+	}
+
+	counts := histogramCount(values, edges)
+	countSeries := NewFloatSeries("count", counts)
+	if dates {
+		starts := make([]string, len(counts))
+		for i := range starts {
+			starts[i] = time.Unix(0, int64(math.Round(edges[i]*1e9))).UTC().Format(time.RFC3339)
+		}
+		return DataFrame{Columns: []Series{NewStringSeries("bin_start", starts), countSeries}}, nil
+	}
+	starts := append([]float64{}, edges[:len(counts)]...)
+	return DataFrame{Columns: []Series{NewFloatSeries("bin_start", starts), countSeries}}, nil
+}
+
+// histogramRange returns the min and max of values ignoring NaN, both NaN
+// when there are no values
+func histogramRange(values []float64) (float64, float64) {
+	low, high := math.NaN(), math.NaN()
+	for _, value := range values {
+		if math.IsNaN(value) {
+			continue
+		}
+		if math.IsNaN(low) || value < low {
+			low = value
+		}
+		if math.IsNaN(high) || value > high {
+			high = value
+		}
+	}
+	return low, high
+}
+
+// histogramBin returns the bin of value, -1 for nulls and values outside
+// the edges
+func histogramBin(edges []float64, value float64) int {
+	bins := len(edges) - 1
+	if math.IsNaN(value) || value < edges[0] || value > edges[bins] {
+		return -1
+	}
+	bin := sort.SearchFloat64s(edges, value)
+	if edges[bin] != value || bin == bins {
+		bin--
+	}
+	return bin
+}
+
+// histogramCount counts values in one parallel pass, every goroutine fills
+// its own counts that are added at the end
+func histogramCount(values []float64, edges []float64) []float64 {
+	if len(edges) == 0 {
+		return []float64{}
+	}
+	bins := len(edges) - 1
+	length := len(values)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var partials [][]int
+	var wg sync.WaitGroup
+
+	for i := 0; i < numGoroutines; i++ {
+		start := i * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		local := make([]int, bins)
+		partials = append(partials, local)
+
+		wg.Add(1)
+		go func(start, end int, local []int) {
+			defer wg.Done()
+			for j := start; j < end; j++ {
+				if bin := histogramBin(edges, values[j]); bin >= 0 {
+					local[bin]++
+				}
+			}
+		}(start, end, local)
+	}
+	wg.Wait()
+
+	counts := make([]float64, bins)
+	for _, local := range partials {
+		for bin, count := range local {
+			counts[bin] += float64(count)
+		}
+	}
+	return counts
+}
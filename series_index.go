@@ -0,0 +1,375 @@
+package grizzly
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// keySet holds the distinct values of a series, it is built with the bloom
+// filter so membership checks against the series do not collect them again
+type keySet struct {
+	floats  map[float64]struct{}
+	strings map[string]struct{}
+}
+
+// BuildBloomFilter indexes the values of the series for Isin, IsinSeries,
+// SemiJoin, AntiJoin and ContainsValue. It keeps the bloom filter and the
+// exact set of the values: the filter rejects most missing values without
+// touching the set, which is much larger, and the set answers the rest.
+func (series *Series) BuildBloomFilter(falsePositiveRate float64) {
+	if series.DataType == "float" {
+		series.bloom = buildBloomFilterFloat(series.Float, falsePositiveRate)
+		series.keySet = &keySet{floats: buildKeySet(series.Float)}
+	} else {
+		values := series.strings()
+		series.bloom = buildBloomFilterString(values, falsePositiveRate)
+		series.keySet = &keySet{strings: buildKeySet(values)}
+	}
+}
+
+// buildKeySet collects the distinct values of data in parallel chunks
+func buildKeySet[T comparable](data []T) map[T]struct{} {
+	partials := make([]map[T]struct{}, workerCount())
+	parallelChunks(len(data), func(chunk, start, end int) {
+		local := make(map[T]struct{})
+		for _, value := range data[start:end] {
+			local[value] = struct{}{}
+		}
+		partials[chunk] = local
+	})
+
+	merged := make(map[T]struct{})
+	for _, partial := range partials {
+		if len(partial) > len(merged) {
+			merged, partial = partial, merged
+		}
+		for key := range partial {
+			merged[key] = struct{}{}
+		}
+	}
+	return merged
+}
+
+func (series *Series) HasBloomFilter() bool {
+	return series.bloom != nil
+}
+
+// invalidateIndexes drops every index and the cached statistics of the
+// series, it must be called by any operation that mutates the values before
+// reading them. It also gives shared series their own values and
+// decompresses compressed series.
+func (series *Series) invalidateIndexes() {
+	series.detach()
+	if series.compressed != nil {
+		series.String = series.strings()
+		series.compressed = nil
+	}
+	series.stats = &statsCache{}
+	series.bloom = nil
+	series.keySet = nil
+	series.hashIndex = nil
+	series.sortedIndex = nil
+}
+
+func (df *DataFrame) invalidateIndexes() {
+	for i := range df.Columns {
+		df.Columns[i].invalidateIndexes()
+	}
+}
+
+// Isin marks the values present in values. With a bloom filter on the
+// series, the values it cannot hold are dropped first and the scan is
+// skipped when none is left.
+func (series *Series) Isin(values []string) []bool {
+	if series.DataType == "float" {
+		set := make(map[float64]struct{}, len(values))
+		for _, value := range values {
+			number, ok := tryConvertToFloat(value)
+			if ok && (series.bloom == nil || series.bloom.mayContainFloat(number)) {
+				set[number] = struct{}{}
+			}
+		}
+		return arrayIsinFloat(series.Float, set)
+	}
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if series.bloom == nil || series.bloom.mayContainString(value) {
+			set[value] = struct{}{}
+		}
+	}
+	return arrayIsinString(series.strings(), set)
+}
+
+// IsinSeries marks the values present in keys. When keys has a bloom
+// filter the set of its values built with it is reused, otherwise the set
+// is collected for the call without the keys the bloom filter of the series
+// rules out. The scan only uses the exact set: checking a bloom filter
+// before every lookup is slower than the lookup itself.
+func (series *Series) IsinSeries(keys *Series) []bool {
+	if series.DataType == "float" {
+		if keys.DataType != "float" {
+			return make([]bool, series.GetLength())
+		}
+		if keys.keySet != nil {
+			return arrayIsinFloat(series.Float, keys.keySet.floats)
+		}
+		set := make(map[float64]struct{}, len(keys.Float))
+		for _, key := range keys.Float {
+			if series.bloom == nil || series.bloom.mayContainFloat(key) {
+				set[key] = struct{}{}
+			}
+		}
+		return arrayIsinFloat(series.Float, set)
+	}
+	if keys.DataType != "string" {
+		return make([]bool, series.GetLength())
+	}
+	if keys.keySet != nil {
+		return arrayIsinString(series.strings(), keys.keySet.strings)
+	}
+	values := keys.strings()
+	set := make(map[string]struct{}, len(values))
+	for _, key := range values {
+		if series.bloom == nil || series.bloom.mayContainString(key) {
+			set[key] = struct{}{}
+		}
+	}
+	return arrayIsinString(series.strings(), set)
+}
+
+// arrayIsinString marks the values of data present in set
+func arrayIsinString(data []string, set map[string]struct{}) []bool {
+	result := make([]bool, len(data))
+	if len(set) == 0 {
+		return result
+	}
+	parallelChunks(len(data), func(_, start, end int) {
+		for i := start; i < end; i++ {
+			_, result[i] = set[data[i]]
+		}
+	})
+	return result
+}
+
+func arrayIsinFloat(data []float64, set map[float64]struct{}) []bool {
+	result := make([]bool, len(data))
+	if len(set) == 0 {
+		return result
+	}
+	parallelChunks(len(data), func(_, start, end int) {
+		for i := start; i < end; i++ {
+			_, result[i] = set[data[i]]
+		}
+	})
+	return result
+}
+
+// hashIndex maps every value of a series to the rows holding it, rows are
+// kept in ascending order
+type hashIndex struct {
+	floats  map[float64][]int
+	strings map[string][]int
+}
+
+func (series *Series) BuildHashIndex() {
+	length := series.GetLength()
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+
+	if series.DataType == "float" {
+		partials := make([]map[float64][]int, numGoroutines)
+		for g := 0; g < numGoroutines; g++ {
+			start := g * chunkSize
+			end := start + chunkSize
+			if start >= length {
+				break
+			}
+			if end > length {
+				end = length
+			}
+			wg.Add(1)
+			go func(start, end, g int) {
+				defer wg.Done()
+				local := make(map[float64][]int)
+				for i := start; i < end; i++ {
+					local[series.Float[i]] = append(local[series.Float[i]], i)
+				}
+				partials[g] = local
+			}(start, end, g)
+		}
+		wg.Wait()
+
+		// Merge in chunk order so the rows of each key stay sorted
+		merged := make(map[float64][]int)
+		for _, partial := range partials {
+			for key, rows := range partial {
+				merged[key] = append(merged[key], rows...)
+			}
+		}
+		series.hashIndex = &hashIndex{floats: merged}
+		return
+	}
+
+	values := series.strings()
+	partials := make([]map[string][]int, numGoroutines)
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end, g int) {
+			defer wg.Done()
+			local := make(map[string][]int)
+			for i := start; i < end; i++ {
+				local[values[i]] = append(local[values[i]], i)
+			}
+			partials[g] = local
+		}(start, end, g)
+	}
+	wg.Wait()
+
+	merged := make(map[string][]int)
+	for _, partial := range partials {
+		for key, rows := range partial {
+			merged[key] = append(merged[key], rows...)
+		}
+	}
+	series.hashIndex = &hashIndex{strings: merged}
+}
+
+func (series *Series) BuildSortedIndex() {
+	length := series.GetLength()
+	permutation := make([]int, length)
+	for i := range permutation {
+		permutation[i] = i
+	}
+	if series.DataType == "float" {
+		// NaN sorts last so the numbers form a prefix the lookups can
+		// binary search
+		sort.SliceStable(permutation, func(a, b int) bool {
+			x, y := series.Float[permutation[a]], series.Float[permutation[b]]
+			if math.IsNaN(x) {
+				return false
+			}
+			return math.IsNaN(y) || x < y
+		})
+	} else {
+		values := series.strings()
+		sort.SliceStable(permutation, func(a, b int) bool {
+			return values[permutation[a]] < values[permutation[b]]
+		})
+	}
+	series.sortedIndex = permutation
+}
+
+// sortedNumbers returns the prefix of the sorted index without the NaN tail
+func (series *Series) sortedNumbers() []int {
+	first := sort.Search(len(series.sortedIndex), func(i int) bool {
+		return math.IsNaN(series.Float[series.sortedIndex[i]])
+	})
+	return series.sortedIndex[:first]
+}
+
+func (series *Series) HasHashIndex() bool {
+	return series.hashIndex != nil
+}
+
+func (series *Series) HasSortedIndex() bool {
+	return series.sortedIndex != nil
+}
+
+// Lookup returns the rows equal to value in ascending order. It uses the hash
+// index, then the sorted index, and falls back to a full scan.
+func (series *Series) Lookup(value string) []int {
+	if series.DataType == "float" {
+		number, ok := tryConvertToFloat(value)
+		if !ok {
+			return []int{}
+		}
+		return series.lookupFloat(number)
+	}
+	return series.lookupString(value)
+}
+
+func (series *Series) lookupFloat(value float64) []int {
+	if series.hashIndex != nil {
+		return append([]int{}, series.hashIndex.floats[value]...)
+	}
+	if series.sortedIndex != nil {
+		numbers := series.sortedNumbers()
+		low := sort.Search(len(numbers), func(i int) bool {
+			return series.Float[numbers[i]] >= value
+		})
+		high := sort.Search(len(numbers), func(i int) bool {
+			return series.Float[numbers[i]] > value
+		})
+		result := append([]int{}, numbers[low:high]...)
+		sort.Ints(result)
+		return result
+	}
+	result := []int{}
+	for i, number := range series.Float {
+		if number == value {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func (series *Series) lookupString(value string) []int {
+	if series.hashIndex != nil {
+		return append([]int{}, series.hashIndex.strings[value]...)
+	}
+	if series.sortedIndex != nil {
+		low := sort.Search(len(series.sortedIndex), func(i int) bool {
+			return series.GetValueString(series.sortedIndex[i]) >= value
+		})
+		high := sort.Search(len(series.sortedIndex), func(i int) bool {
+			return series.GetValueString(series.sortedIndex[i]) > value
+		})
+		result := append([]int{}, series.sortedIndex[low:high]...)
+		sort.Ints(result)
+		return result
+	}
+	result := []int{}
+	for i, word := range series.strings() {
+		if word == value {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// LookupRange returns the rows of a float series within [low, high], using
+// the sorted index when it exists
+func (series *Series) LookupRange(low, high float64) []int {
+	result := []int{}
+	if series.DataType != "float" {
+		return result
+	}
+	if series.sortedIndex != nil {
+		numbers := series.sortedNumbers()
+		first := sort.Search(len(numbers), func(i int) bool {
+			return series.Float[numbers[i]] >= low
+		})
+		last := sort.Search(len(numbers), func(i int) bool {
+			return series.Float[numbers[i]] > high
+		})
+		result = append(result, numbers[first:last]...)
+		sort.Ints(result)
+		return result
+	}
+	for i, number := range series.Float {
+		if number >= low && number <= high {
+			result = append(result, i)
+		}
+	}
+	return result
+}
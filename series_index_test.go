@@ -0,0 +1,55 @@
+package grizzly
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+// The probed column has 1<<16 rows and holds about one key in a hundred,
+// the key sets grow to show the set of an indexed column being reused
+var isinBenchmarkKeys = []int{1 << 10, 1 << 14, 1 << 18, 1 << 20}
+
+func isinBenchmarkSeries(name string, n, offset int) Series {
+	values := make([]string, n)
+	for i := range values {
+		values[i] = "key" + strconv.Itoa(offset+i)
+	}
+	return NewStringSeries(name, values)
+}
+
+// BenchmarkIsinSeries probes a column against a key column with and without
+// a bloom filter on the keys
+func BenchmarkIsinSeries(b *testing.B) {
+	for _, size := range isinBenchmarkKeys {
+		probed := isinBenchmarkSeries("id", 1<<16, size-(1<<16)/100)
+		for _, bloom := range []bool{false, true} {
+			keys := isinBenchmarkSeries("id", size, 0)
+			if bloom {
+				keys.BuildBloomFilter(0.01)
+			}
+			b.Run(fmt.Sprintf("keys=%d/bloom=%t", size, bloom), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					probed.IsinSeries(&keys)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkIsin looks up values missing from a column, with a bloom filter
+// on the column they are rejected without scanning it
+func BenchmarkIsin(b *testing.B) {
+	values := isinBenchmarkSeries("id", 64, 1<<20).String
+	for _, bloom := range []bool{false, true} {
+		series := isinBenchmarkSeries("id", 1<<20, 0)
+		if bloom {
+			series.BuildBloomFilter(0.01)
+		}
+		b.Run(fmt.Sprintf("bloom=%t", bloom), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				series.Isin(values)
+			}
+		})
+	}
+}
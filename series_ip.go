@@ -0,0 +1,97 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"strings"
+)
+
+// parseIP reads an address, IPv4 addresses written as IPv6 ("::ffff:1.2.3.4")
+// are unmapped so both forms compare equal
+func parseIP(value string) (netip.Addr, bool) {
+	address, err := netip.ParseAddr(strings.TrimSpace(value))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return address.Unmap(), true
+}
+
+// ParseIP returns a string series with every address in canonical form
+// ("2001:db8::1", "10.0.0.1"), values that are not addresses become NaN
+func (series *Series) ParseIP() (Series, error) {
+	return seriesMapString(series, series.Name, func(value string) string {
+		address, ok := parseIP(value)
+		if !ok {
+			return "NaN"
+		}
+		return address.String()
+	})
+}
+
+// IsIPv4 returns a mask with true for the IPv4 addresses
+func (series *Series) IsIPv4() ([]bool, error) {
+	return seriesMask(series, func(value string) bool {
+		address, ok := parseIP(value)
+		return ok && address.Is4()
+	})
+}
+
+// IsIPv6 returns a mask with true for the IPv6 addresses
+func (series *Series) IsIPv6() ([]bool, error) {
+	return seriesMask(series, func(value string) bool {
+		address, ok := parseIP(value)
+		return ok && address.Is6()
+	})
+}
+
+// InSubnet returns a mask with true for the addresses inside the network
+// written in CIDR notation, like "10.0.0.0/8" or "2001:db8::/32"
+func (series *Series) InSubnet(cidr string) ([]bool, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet %q: %w", cidr, err)
+	}
+	prefix = prefix.Masked()
+	return seriesMask(series, func(value string) bool {
+		address, ok := parseIP(value)
+		return ok && prefix.Contains(address)
+	})
+}
+
+// IPToInt converts IPv4 addresses to their integer value in a float series,
+// so address ranges can be compared or joined with AsOfJoin. IPv6 addresses
+// do not fit in a float64 and become NaN like invalid values.
+func (series *Series) IPToInt() (Series, error) {
+	if series.DataType != "string" {
+		return Series{}, fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	values := make([]float64, series.GetLength())
+	for i, value := range series.strings() {
+		address, ok := parseIP(value)
+		if !ok || !address.Is4() {
+			values[i] = math.NaN()
+			continue
+		}
+		bytes := address.As4()
+		values[i] = float64(uint32(bytes[0])<<24 | uint32(bytes[1])<<16 | uint32(bytes[2])<<8 | uint32(bytes[3]))
+	}
+	return NewFloatSeries(series.Name, values), nil
+}
+
+// IntToIP converts integers from IPToInt back to IPv4 addresses
+func (series *Series) IntToIP() (Series, error) {
+	if series.DataType != "float" {
+		return Series{}, fmt.Errorf("series %q is not of type float", series.Name)
+	}
+	values := make([]string, series.GetLength())
+	for i, value := range series.Float {
+		if math.IsNaN(value) || value < 0 || value > math.MaxUint32 || value != math.Trunc(value) {
+			values[i] = "NaN"
+			continue
+		}
+		number := uint32(value)
+		values[i] = netip.AddrFrom4([4]byte{byte(number >> 24), byte(number >> 16), byte(number >> 8), byte(number)}).String()
+	}
+	return NewStringSeries(series.Name, values), nil
+}
@@ -1,15 +1,18 @@
 package grizzly
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"math"
 	"regexp"
-	"runtime"
 	"strconv"
 	"strings"
 	"sync"
 )
 
 func (series *Series) RemoveIndexes(indexes []int) {
+	series.invalidateIndexes()
 	if series.DataType == "float" {
 		filteredFloats := make([]float64, len(indexes))
 		for i, idx := range indexes {
@@ -26,16 +29,18 @@ func (series *Series) RemoveIndexes(indexes []int) {
 }
 
 func (series *Series) ConvertStringToFloat() {
+	series.invalidateIndexes()
 	if series.DataType == "float" {
 		return
 	}
 	// Determine the number of goroutines based on available CPUs
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	length := len(series.String)
 	floatArray := make([]float64, length)
 	var wg sync.WaitGroup
 	var once sync.Once
 	var firstErr error
+	nulls := GetConfig().nullValues()
 
 	// Calculate chunk size
 	chunkSize := (length + numGoroutines - 1) / numGoroutines
@@ -59,6 +64,11 @@ func (series *Series) ConvertStringToFloat() {
 					return
 				}
 
+				// Null tokens become NaN
+				if nulls[series.String[j]] {
+					floatArray[j] = math.NaN()
+					continue
+				}
 				// Try to convert the string to a float
 				val, err := strconv.ParseFloat(series.String[j], 64)
 				if err != nil {
@@ -75,7 +85,7 @@ func (series *Series) ConvertStringToFloat() {
 
 	// Check if an error occurred during conversion
 	if firstErr != nil {
-		fmt.Println("Column was not converted to float due: ", firstErr)
+		getLogger().Warn("column was not converted to float", "column", series.Name, "error", firstErr)
 	} else {
 		series.Float = floatArray
 		series.String = nil // Clear the string slice
@@ -85,12 +95,13 @@ func (series *Series) ConvertStringToFloat() {
 }
 
 func (series *Series) ConvertFloatToString() {
+	series.invalidateIndexes()
 	if series.DataType == "string" {
 		return
 	}
 
 	// Determine the number of goroutines based on available CPUs
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	length := len(series.Float)
 	stringArray := make([]string, length)
 	var wg sync.WaitGroup
@@ -125,11 +136,12 @@ func (series *Series) ConvertFloatToString() {
 }
 
 func (series *Series) ReplaceWholeWord(old, new string) {
+	series.invalidateIndexes()
 	if series.DataType == "float" || series.GetLength() == 0 {
 		return
 	}
 
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	length := series.GetLength()
 	chunkSize := (length + numGoroutines - 1) / numGoroutines
 
@@ -159,6 +171,7 @@ func (series *Series) ReplaceWholeWord(old, new string) {
 }
 
 func (series *Series) Replace(old, new string) {
+	series.invalidateIndexes()
 	if series.GetLength() == 0 {
 		return
 	}
@@ -178,7 +191,7 @@ func (series *Series) Replace(old, new string) {
 	if series.DataType == "float" && !(errorCount == 0) {
 		return
 	}
-	numGoroutines := runtime.NumCPU()
+	numGoroutines := workerCount()
 	length := series.GetLength()
 	chunkSize := (length + numGoroutines - 1) / numGoroutines
 	var wg sync.WaitGroup
@@ -221,3 +234,229 @@ func (series *Series) Replace(old, new string) {
 		return
 	}
 }
+
+// MaskEmail hides the local part of email addresses but its first letter,
+// "john@mail.com" becomes "j***@mail.com". Other values are kept.
+func (series *Series) MaskEmail() error {
+	if series.DataType != "string" {
+		return fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	series.invalidateIndexes()
+	arrayApplyString(series.String, func(value string) string {
+		at := strings.LastIndex(value, "@")
+		if at <= 0 {
+			return value
+		}
+		first := []rune(value[:at])[0]
+		return string(first) + strings.Repeat("*", len([]rune(value[:at]))-1) + value[at:]
+	})
+	return nil
+}
+
+// HashValues replaces every value with the hex SHA-256 of salt and the value,
+// equal values keep matching so the column can still be joined. Float series
+// become string series, nulls are kept.
+func (series *Series) HashValues(salt string) {
+	series.ConvertFloatToString()
+	series.invalidateIndexes()
+	arrayApplyString(series.String, func(value string) string {
+		if value == "NaN" {
+			return value
+		}
+		sum := sha256.Sum256([]byte(salt + value))
+		return hex.EncodeToString(sum[:])
+	})
+}
+
+// RedactPattern replaces every match of the regular expression with
+// replacement, like "[REDACTED]"
+func (series *Series) RedactPattern(pattern, replacement string) error {
+	if series.DataType != "string" {
+		return fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("failed to compile pattern: %w", err)
+	}
+	series.invalidateIndexes()
+	arrayApplyString(series.String, func(value string) string {
+		return expression.ReplaceAllString(value, replacement)
+	})
+	return nil
+}
+
+// GeneralizeNumeric replaces numbers with the range of width they fall in,
+// 27 becomes "20-30" with width 10. The series becomes a string series.
+func (series *Series) GeneralizeNumeric(width float64) error {
+	if series.DataType != "float" {
+		return fmt.Errorf("series %q is not of type float", series.Name)
+	}
+	if width <= 0 {
+		return fmt.Errorf("generalization width must be positive, got %v", width)
+	}
+	series.invalidateIndexes()
+	values := make([]string, len(series.Float))
+	for i, value := range series.Float {
+		if math.IsNaN(value) {
+			values[i] = "NaN"
+			continue
+		}
+		start := math.Floor(value/width) * width
+		values[i] = strconv.FormatFloat(start, 'f', -1, 64) + "-" + strconv.FormatFloat(start+width, 'f', -1, 64)
+	}
+	series.String = values
+	series.Float = nil
+	series.DataType = "string"
+	return nil
+}
+
+// GeneralizeString keeps the first characters of every value and masks the
+// rest, "90210" becomes "902**" with keep 3
+func (series *Series) GeneralizeString(keep int) error {
+	if series.DataType != "string" {
+		return fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	if keep < 0 {
+		return fmt.Errorf("number of characters to keep must not be negative, got %d", keep)
+	}
+	series.invalidateIndexes()
+	arrayApplyString(series.String, func(value string) string {
+		runes := []rune(value)
+		if value == "NaN" || len(runes) <= keep {
+			return value
+		}
+		return string(runes[:keep]) + strings.Repeat("*", len(runes)-keep)
+	})
+	return nil
+}
+
+// ReplaceMany applies every replacement of the map in a single pass over
+// each value with an Aho-Corasick automaton, much faster than calling
+// Replace once per entry with large cleanup dictionaries. Where several keys
+// match at the same position the longest one wins, and replaced text is not
+// searched again.
+func (series *Series) ReplaceMany(replacements map[string]string) error {
+	if series.DataType != "string" {
+		return fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	patterns := make([]string, 0, len(replacements))
+	for old := range replacements {
+		if old == "" {
+			return fmt.Errorf("replacement keys can not be empty")
+		}
+		patterns = append(patterns, old)
+	}
+	patterns = ParallelSortString(patterns)
+	values := make([]string, len(patterns))
+	for i, old := range patterns {
+		values[i] = replacements[old]
+	}
+	automaton := newAhoCorasick(patterns)
+	series.invalidateIndexes()
+	arrayApplyString(series.String, func(value string) string {
+		return automaton.replace(value, values)
+	})
+	return nil
+}
+
+// PatternReplacement is a regular expression and its replacement, which
+// can reference the groups of the expression like "$1"
+type PatternReplacement struct {
+	Pattern     string
+	Replacement string
+}
+
+// ReplacePatterns applies a set of regular expressions in a single pass
+// over each value. At every position the first rule in order that matches
+// is applied and replaced text is not searched again.
+func (series *Series) ReplacePatterns(rules []PatternReplacement) error {
+	if series.DataType != "string" {
+		return fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	// Every rule becomes a group of a single alternation, groups holds the
+	// index of the group of every rule
+	expressions := make([]*regexp.Regexp, len(rules))
+	groups := make([]int, len(rules))
+	alternatives := make([]string, len(rules))
+	group := 1
+	for i, rule := range rules {
+		expression, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("failed to compile pattern %q: %w", rule.Pattern, err)
+		}
+		expressions[i] = expression
+		groups[i] = group
+		group += 1 + expression.NumSubexp()
+		alternatives[i] = "(" + rule.Pattern + ")"
+	}
+	combined, err := regexp.Compile(strings.Join(alternatives, "|"))
+	if err != nil {
+		return fmt.Errorf("failed to combine patterns: %w", err)
+	}
+
+	series.invalidateIndexes()
+	arrayApplyString(series.String, func(value string) string {
+		matches := combined.FindAllStringSubmatchIndex(value, -1)
+		if matches == nil {
+			return value
+		}
+		var builder strings.Builder
+		last := 0
+		for _, match := range matches {
+			builder.WriteString(value[last:match[0]])
+			for i, group := range groups {
+				if match[2*group] < 0 {
+					continue
+				}
+				// Expand the replacement with the groups of the rule
+				submatch := match[2*group : 2*(group+1+expressions[i].NumSubexp())]
+				builder.Write(expressions[i].ExpandString(nil, rules[i].Replacement, value, submatch))
+				break
+			}
+			last = match[1]
+		}
+		builder.WriteString(value[last:])
+		return builder.String()
+	})
+	return nil
+}
+
+// Reverse returns a copy of the series with the rows in reverse order
+func (series *Series) Reverse() Series {
+	length := series.GetLength()
+	rows := make([]int, length)
+	for i := range rows {
+		rows[i] = length - 1 - i
+	}
+	return seriesTake(*series, rows)
+}
+
+// Repeat returns a copy of the series where every value is repeated n times
+// in place, [a b] becomes [a a b b] for n = 2
+func (series *Series) Repeat(n int) (Series, error) {
+	if n < 0 {
+		return Series{}, fmt.Errorf("repeat count must not be negative, got %d", n)
+	}
+	rows := make([]int, series.GetLength()*n)
+	for i := range rows {
+		rows[i] = i / n
+	}
+	return seriesTake(*series, rows), nil
+}
+
+// Tile returns a copy of the series stacked n times, [a b] becomes
+// [a b a b] for n = 2
+func (series *Series) Tile(n int) (Series, error) {
+	if n < 0 {
+		return Series{}, fmt.Errorf("tile count must not be negative, got %d", n)
+	}
+	length := series.GetLength()
+	rows := make([]int, length*n)
+	for i := range rows {
+		rows[i] = i % length
+	}
+	return seriesTake(*series, rows), nil
+}
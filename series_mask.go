@@ -0,0 +1,127 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Masks are []bool with one value per row, like the ones returned by
+// IsValidEmail or InSubnet. They are combined with MaskAnd, MaskOr, MaskXor
+// and MaskNot and applied with df.Filter.
+
+// MaskFloat evaluates condition on every value of a float series
+func (series *Series) MaskFloat(condition func(float64) bool) ([]bool, error) {
+	if series.DataType != "float" {
+		return nil, fmt.Errorf("series %q is not of type float", series.Name)
+	}
+	mask := make([]bool, len(series.Float))
+	for i, value := range series.Float {
+		mask[i] = condition(value)
+	}
+	return mask, nil
+}
+
+// MaskString evaluates condition on every value of a string series
+func (series *Series) MaskString(condition func(string) bool) ([]bool, error) {
+	return seriesMask(series, condition)
+}
+
+// ToMask reads a float series of 0 and 1, like the result of a comparison
+// Expr, as a mask. NaN is false.
+func (series *Series) ToMask() ([]bool, error) {
+	return series.MaskFloat(func(value float64) bool {
+		return value != 0 && !math.IsNaN(value)
+	})
+}
+
+// combineMasks applies operation row by row to masks of the same length
+func combineMasks(masks [][]bool, operation func(a, b bool) bool) ([]bool, error) {
+	if len(masks) == 0 {
+		return nil, fmt.Errorf("at least one mask is required")
+	}
+	result := append([]bool{}, masks[0]...)
+	for i, mask := range masks[1:] {
+		if len(mask) != len(result) {
+			return nil, fmt.Errorf("mask %d has length %d, expected %d", i+1, len(mask), len(result))
+		}
+		for row, value := range mask {
+			result[row] = operation(result[row], value)
+		}
+	}
+	return result, nil
+}
+
+// MaskAnd is true for the rows where every mask is true
+func MaskAnd(masks ...[]bool) ([]bool, error) {
+	return combineMasks(masks, func(a, b bool) bool { return a && b })
+}
+
+// MaskOr is true for the rows where any mask is true
+func MaskOr(masks ...[]bool) ([]bool, error) {
+	return combineMasks(masks, func(a, b bool) bool { return a || b })
+}
+
+// MaskXor is true for the rows where an odd number of masks is true
+func MaskXor(masks ...[]bool) ([]bool, error) {
+	return combineMasks(masks, func(a, b bool) bool { return a != b })
+}
+
+func MaskNot(mask []bool) []bool {
+	result := make([]bool, len(mask))
+	for i, value := range mask {
+		result[i] = !value
+	}
+	return result
+}
+
+// MaskIndexes returns the rows where mask is true in ascending order
+func MaskIndexes(mask []bool) []int {
+	// The rows of every chunk are joined in chunk order
+	var chunks [][]int
+	var mutex sync.Mutex
+	parallelChunks(len(mask), func(chunk, start, end int) {
+		var rows []int
+		for j := start; j < end; j++ {
+			if mask[j] {
+				rows = append(rows, j)
+			}
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		for len(chunks) <= chunk {
+			chunks = append(chunks, nil)
+		}
+		chunks[chunk] = rows
+	})
+
+	total := 0
+	for _, rows := range chunks {
+		total += len(rows)
+	}
+	indexes := make([]int, 0, total)
+	for _, rows := range chunks {
+		indexes = append(indexes, rows...)
+	}
+	return indexes
+}
+
+// FindFloat returns the rows of a float series where condition is true in
+// ascending order
+func (series *Series) FindFloat(condition func(float64) bool) ([]int, error) {
+	mask, err := series.MaskFloat(condition)
+	if err != nil {
+		return nil, err
+	}
+	return MaskIndexes(mask), nil
+}
+
+// FindString returns the rows of a string series where condition is true
+// in ascending order
+func (series *Series) FindString(condition func(string) bool) ([]int, error) {
+	mask, err := series.MaskString(condition)
+	if err != nil {
+		return nil, err
+	}
+	return MaskIndexes(mask), nil
+}
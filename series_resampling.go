@@ -0,0 +1,123 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// resample runs draw n times in parallel and returns the results in order,
+// see parallelDraws
+func resample(n int, seed int, draw func(rng *rand.Rand) float64) []float64 {
+	results := make([]float64, n)
+	parallelDraws(n, seed, func(i int, rng *rand.Rand) {
+		results[i] = draw(rng)
+	})
+	return results
+}
+
+// parallelDraws calls draw for every position from 0 to n in parallel. Every
+// position gets its own generator derived from seed, so the draws do not
+// depend on the number of workers. A seed of 0 uses the current time.
+func parallelDraws(n int, seed int, draw func(i int, rng *rand.Rand)) {
+	base := uint64(seed)
+	if seed == 0 {
+		base = uint64(time.Now().UnixNano())
+	}
+	numGoroutines := workerCount()
+	chunkSize := (n + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := minInt(start+chunkSize, n)
+		if start >= n {
+			break
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				draw(i, rand.New(rand.NewPCG(base, uint64(i))))
+			}
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// nonNullFloats returns the values of a float series without NaN
+func nonNullFloats(series *Series) ([]float64, error) {
+	if series.DataType != "float" {
+		return nil, fmt.Errorf("series %q is not a float series", series.Name)
+	}
+	values := make([]float64, 0, len(series.Float))
+	for _, value := range series.Float {
+		if !math.IsNaN(value) {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// Bootstrap draws nSamples resamples with replacement of the series, the same
+// size as its non NaN values, and returns the statistic of every resample as
+// the series "<name>_bootstrap". Resamples run in parallel and a seed of 0
+// uses the current time.
+func (series *Series) Bootstrap(nSamples int, statistic func(values []float64) float64, seed int) (Series, error) {
+	if nSamples <= 0 {
+		return Series{}, fmt.Errorf("number of samples must be positive, got %d", nSamples)
+	}
+	values, err := nonNullFloats(series)
+	if err != nil {
+		return Series{}, fmt.Errorf("failed to bootstrap: %w", err)
+	}
+	if len(values) == 0 {
+		return Series{}, fmt.Errorf("failed to bootstrap: series %q has no values", series.Name)
+	}
+	results := resample(nSamples, seed, func(rng *rand.Rand) float64 {
+		sample := make([]float64, len(values))
+		for i := range sample {
+			sample[i] = values[rng.IntN(len(values))]
+		}
+		return statistic(sample)
+	})
+	return NewFloatSeries(series.Name+"_bootstrap", results), nil
+}
+
+// PermutationTest checks whether statistic differs between two float series
+// more than chance. The values of both series are pooled and randomly split
+// nPermutations times in parallel into groups of the original sizes. The
+// two-sided p-value is the share of splits whose statistic is at least as
+// extreme as the observed one, counting the observed split. NaN values are
+// ignored and a seed of 0 uses the current time.
+func PermutationTest(a, b Series, statistic func(a, b []float64) float64, nPermutations int, seed int) (observed float64, pValue float64, err error) {
+	if nPermutations <= 0 {
+		return 0, 0, fmt.Errorf("number of permutations must be positive, got %d", nPermutations)
+	}
+	valuesA, err := nonNullFloats(&a)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to run permutation test: %w", err)
+	}
+	valuesB, err := nonNullFloats(&b)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to run permutation test: %w", err)
+	}
+	if len(valuesA) == 0 || len(valuesB) == 0 {
+		return 0, 0, fmt.Errorf("failed to run permutation test: both series need values")
+	}
+	observed = statistic(valuesA, valuesB)
+	pooled := append(append(make([]float64, 0, len(valuesA)+len(valuesB)), valuesA...), valuesB...)
+	results := resample(nPermutations, seed, func(rng *rand.Rand) float64 {
+		shuffled := append([]float64(nil), pooled...)
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		return statistic(shuffled[:len(valuesA)], shuffled[len(valuesA):])
+	})
+	extreme := 1
+	for _, value := range results {
+		if math.Abs(value) >= math.Abs(observed) {
+			extreme++
+		}
+	}
+	return observed, float64(extreme) / float64(nPermutations+1), nil
+}
@@ -0,0 +1,132 @@
+package grizzly
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// searchChunks splits length rows in chunks small enough that workers stop
+// soon after a match, but large enough to keep the scheduling cheap
+func searchChunks(length int) int {
+	return max(1024, length/(workerCount()*16))
+}
+
+// parallelSearch returns the first row, or the last one when fromEnd is set,
+// for which match is true, -1 when there is none. Workers take the chunks in
+// search order and skip the ones that can no longer hold a better match, so
+// the scan stops soon after the answer is found.
+func parallelSearch(length int, fromEnd bool, match func(row int) bool) int {
+	chunkSize := searchChunks(length)
+	numChunks := (length + chunkSize - 1) / chunkSize
+	// best holds the row found so far, flipped when searching from the end so
+	// smaller is always better
+	var best atomic.Int64
+	best.Store(math.MaxInt64)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < min(workerCount(), numChunks); g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				chunk := int(next.Add(1) - 1)
+				if chunk >= numChunks {
+					return
+				}
+				// position counts rows in search order
+				start := chunk * chunkSize
+				if int64(start) >= best.Load() {
+					return
+				}
+				end := min(start+chunkSize, length)
+				for position := start; position < end; position++ {
+					row := position
+					if fromEnd {
+						row = length - 1 - position
+					}
+					if !match(row) {
+						continue
+					}
+					for {
+						current := best.Load()
+						if int64(position) >= current || best.CompareAndSwap(current, int64(position)) {
+							break
+						}
+					}
+					break
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	position := best.Load()
+	if position == math.MaxInt64 {
+		return -1
+	}
+	if fromEnd {
+		return length - 1 - int(position)
+	}
+	return int(position)
+}
+
+// FirstIndexWhere returns the first row for which predicate is true, or -1.
+// Chunks of rows are checked in parallel and the search stops as soon as no
+// earlier match is possible.
+func (series *Series) FirstIndexWhere(predicate func(row int) bool) int {
+	return parallelSearch(series.GetLength(), false, predicate)
+}
+
+// LastIndexWhere returns the last row for which predicate is true, or -1,
+// searching from the end like FirstIndexWhere
+func (series *Series) LastIndexWhere(predicate func(row int) bool) int {
+	return parallelSearch(series.GetLength(), true, predicate)
+}
+
+// ContainsValue reports whether the series holds value, a number for float
+// series or a string for string series. NaN and "NaN" find nulls. Bloom
+// filters, hash indexes and cached statistics answer without a scan,
+// otherwise the rows are scanned in parallel until the value is found.
+func (series *Series) ContainsValue(value any) bool {
+	if series.DataType == "float" {
+		number, err := interfaceConvertToFloat(value)
+		if err != nil {
+			return false
+		}
+		if math.IsNaN(number) {
+			return series.NullCount() > 0
+		}
+		if stats := series.cachedStatistics(); stats != nil && len(series.Float) > 0 && (number < stats.min || number > stats.max) {
+			return false
+		}
+		if series.bloom != nil {
+			if !series.bloom.mayContainFloat(number) {
+				return false
+			}
+			_, found := series.keySet.floats[number]
+			return found
+		}
+		if series.hashIndex != nil {
+			return len(series.hashIndex.floats[number]) > 0
+		}
+		values := series.Float
+		return parallelSearch(len(values), false, func(row int) bool { return values[row] == number }) >= 0
+	}
+
+	text, err := interfaceConvertToString(value)
+	if err != nil {
+		return false
+	}
+	if series.bloom != nil {
+		if !series.bloom.mayContainString(text) {
+			return false
+		}
+		_, found := series.keySet.strings[text]
+		return found
+	}
+	if series.hashIndex != nil {
+		return len(series.hashIndex.strings[text]) > 0
+	}
+	values := series.strings()
+	return parallelSearch(len(values), false, func(row int) bool { return values[row] == text }) >= 0
+}
@@ -0,0 +1,312 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SortOptions configures Series.Sort, Series.SortIndices and
+// DataFrame.SortWith. Nulls (NaN and "NaN") go last unless NullsFirst is set,
+// whatever the direction. Collation orders strings: "" compares bytes,
+// "natural" compares digit runs by their numeric value ("file2" < "file10")
+// and "datetime" parses the values as dates, values that can not be parsed
+// are treated as nulls. CaseInsensitive folds case before comparing and
+// Comparator replaces the collation with a custom three-way comparison.
+type SortOptions struct {
+	Descending      bool
+	NullsFirst      bool
+	Stable          bool
+	Collation       string
+	CaseInsensitive bool
+	Comparator      func(a, b string) int
+}
+
+// dateTimeLayouts are the formats recognized when strings are read as dates
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"02/01/2006 15:04:05",
+	"01/02/2006",
+	"02 Jan 2006",
+	"Jan 2, 2006",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+func parseDateTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// sortComparator returns the nulls of the series and a three-way comparison
+// of two non-null rows
+func (series *Series) sortComparator(options SortOptions) ([]bool, func(a, b int) int, error) {
+	length := series.GetLength()
+	nulls := make([]bool, length)
+
+	if series.DataType == "float" {
+		for i, value := range series.Float {
+			nulls[i] = math.IsNaN(value)
+		}
+		return nulls, func(a, b int) int {
+			switch {
+			case series.Float[a] < series.Float[b]:
+				return -1
+			case series.Float[a] > series.Float[b]:
+				return 1
+			}
+			return 0
+		}, nil
+	}
+
+	source := series.strings()
+	values := source
+	if options.CaseInsensitive {
+		values = make([]string, length)
+		for i, value := range source {
+			values[i] = strings.ToLower(value)
+		}
+	}
+	var compareStrings func(a, b string) int
+	switch {
+	case options.Comparator != nil:
+		compareStrings = options.Comparator
+	case options.Collation == "":
+		compareStrings = strings.Compare
+	case options.Collation == "natural":
+		compareStrings = naturalCompare
+	}
+	if compareStrings != nil {
+		for i, value := range source {
+			nulls[i] = value == "NaN"
+		}
+		return nulls, func(a, b int) int {
+			return compareStrings(values[a], values[b])
+		}, nil
+	}
+
+	switch options.Collation {
+	case "datetime":
+		keys := make([]int64, length)
+		for i, value := range source {
+			parsed, ok := parseDateTime(value)
+			nulls[i] = !ok
+			keys[i] = parsed.UnixNano()
+		}
+		return nulls, func(a, b int) int {
+			switch {
+			case keys[a] < keys[b]:
+				return -1
+			case keys[a] > keys[b]:
+				return 1
+			}
+			return 0
+		}, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported collation %q", options.Collation)
+}
+
+// SortIndices returns the permutation that sorts the series, chunks are
+// sorted in parallel and merged in order so ties keep their position when
+// Stable is set. Large float series use radix sort.
+func (series *Series) SortIndices(options SortOptions) ([]int, error) {
+	if series.DataType == "float" && series.GetLength() >= radixSortThreshold {
+		// Radix sort is stable and linear for large float columns
+		return radixSortIndices(series.Float, options.Descending, options.NullsFirst), nil
+	}
+	less, err := series.sortLess(options)
+	if err != nil {
+		return nil, err
+	}
+
+	length := series.GetLength()
+	indices := identityRows(length)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var chunks [][]int
+	var wg sync.WaitGroup
+
+	for i := 0; i < numGoroutines; i++ {
+		start := i * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		chunk := indices[start:end]
+		chunks = append(chunks, chunk)
+
+		wg.Add(1)
+		go func(chunk []int) {
+			defer wg.Done()
+			if options.Stable {
+				sort.SliceStable(chunk, func(i, j int) bool { return less(chunk[i], chunk[j]) })
+			} else {
+				sort.Slice(chunk, func(i, j int) bool { return less(chunk[i], chunk[j]) })
+			}
+		}(chunk)
+	}
+	wg.Wait()
+
+	// Merge neighbouring chunks so earlier rows win ties
+	for len(chunks) > 1 {
+		var merged [][]int
+		for i := 0; i < len(chunks); i += 2 {
+			if i+1 == len(chunks) {
+				merged = append(merged, chunks[i])
+				continue
+			}
+			merged = append(merged, mergeIndices(chunks[i], chunks[i+1], less))
+		}
+		chunks = merged
+	}
+	if len(chunks) == 0 {
+		return []int{}, nil
+	}
+	return chunks[0], nil
+}
+
+// sortLess returns the order of two rows of the series with the options
+func (series *Series) sortLess(options SortOptions) (func(a, b int) bool, error) {
+	nulls, compare, err := series.sortComparator(options)
+	if err != nil {
+		return nil, err
+	}
+	return func(a, b int) bool {
+		if nulls[a] || nulls[b] {
+			if nulls[a] == nulls[b] {
+				return false
+			}
+			return nulls[a] == options.NullsFirst
+		}
+		if options.Descending {
+			return compare(a, b) > 0
+		}
+		return compare(a, b) < 0
+	}, nil
+}
+
+func mergeIndices(left, right []int, less func(a, b int) bool) []int {
+	result := make([]int, 0, len(left)+len(right))
+	i, j := 0, 0
+	for i < len(left) && j < len(right) {
+		if less(right[j], left[i]) {
+			result = append(result, right[j])
+			j++
+		} else {
+			result = append(result, left[i])
+			i++
+		}
+	}
+	result = append(result, left[i:]...)
+	return append(result, right[j:]...)
+}
+
+// Sort orders the values of the series in place
+func (series *Series) Sort(options SortOptions) error {
+	indices, err := series.SortIndices(options)
+	if err != nil {
+		return fmt.Errorf("failed to sort series %q: %w", series.Name, err)
+	}
+	sorted := seriesTake(*series, indices)
+	series.invalidateIndexes()
+	// Compressed series stay compressed
+	series.Float, series.String, series.compressed = sorted.Float, sorted.String, sorted.compressed
+	return nil
+}
+
+// SortWith orders the rows of the DataFrame by one column with the options.
+// Above Config.MemoryLimit the rows are sorted on disk.
+func (df *DataFrame) SortWith(identifier any, options SortOptions) (err error) {
+	defer df.track("SortWith", identifier, options)(&err)
+	series, err := df.GetColumnDynamic(identifier)
+	if err != nil {
+		return fmt.Errorf("error sorting %v: %w", identifier, err)
+	}
+	if limit := memoryLimit(); limit > 0 && series.GetLength()*sortRowBytes > limit {
+		if err = df.spillSort(series, options, limit); err != nil {
+			return fmt.Errorf("error sorting %v: %w", identifier, err)
+		}
+		return nil
+	}
+	indices, err := series.SortIndices(options)
+	if err != nil {
+		return fmt.Errorf("error sorting %v: %w", identifier, err)
+	}
+	df.invalidateIndexes()
+	for i, column := range df.Columns {
+		df.Columns[i] = seriesTake(column, indices)
+	}
+	intPool.put(indices)
+	return nil
+}
+
+// naturalCompare orders strings treating runs of digits as numbers, so
+// "file2" comes before "file10"
+func naturalCompare(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			startA, startB := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			numberA := strings.TrimLeft(a[startA:i], "0")
+			numberB := strings.TrimLeft(b[startB:j], "0")
+			if len(numberA) != len(numberB) {
+				if len(numberA) < len(numberB) {
+					return -1
+				}
+				return 1
+			}
+			if result := strings.Compare(numberA, numberB); result != 0 {
+				return result
+			}
+			// Equal numbers, fewer leading zeros first
+			if (i - startA) != (j - startB) {
+				if i-startA < j-startB {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			if a[i] < b[j] {
+				return -1
+			}
+			return 1
+		}
+		i++
+		j++
+	}
+	switch {
+	case len(a)-i < len(b)-j:
+		return -1
+	case len(a)-i > len(b)-j:
+		return 1
+	}
+	return 0
+}
+
+func isDigit(character byte) bool {
+	return character >= '0' && character <= '9'
+}
@@ -0,0 +1,205 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// SparseSeries stores a float column that is mostly FillValue, keeping only
+// the positions and values of the other rows in ascending order
+type SparseSeries struct {
+	Name      string
+	Indexes   []int
+	Values    []float64
+	FillValue float64
+	Length    int
+}
+
+// ToSparse converts a float series keeping only the values different from
+// fillValue. NaN can be used as fill value.
+func (series *Series) ToSparse(fillValue float64) (SparseSeries, error) {
+	if series.DataType != "float" {
+		return SparseSeries{}, fmt.Errorf("only float series can be sparse, %q is %q", series.Name, series.DataType)
+	}
+	length := len(series.Float)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	partialIndexes := make([][]int, numGoroutines)
+	partialValues := make([][]float64, numGoroutines)
+	isFill := func(value float64) bool {
+		if math.IsNaN(fillValue) {
+			return math.IsNaN(value)
+		}
+		return value == fillValue
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end, g int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				if !isFill(series.Float[i]) {
+					partialIndexes[g] = append(partialIndexes[g], i)
+					partialValues[g] = append(partialValues[g], series.Float[i])
+				}
+			}
+		}(start, end, g)
+	}
+	wg.Wait()
+
+	result := SparseSeries{Name: series.Name, FillValue: fillValue, Length: length}
+	for g := range partialIndexes {
+		result.Indexes = append(result.Indexes, partialIndexes[g]...)
+		result.Values = append(result.Values, partialValues[g]...)
+	}
+	return result, nil
+}
+
+func (sparse *SparseSeries) ToSeries() Series {
+	values := make([]float64, sparse.Length)
+	if sparse.FillValue != 0 {
+		for i := range values {
+			values[i] = sparse.FillValue
+		}
+	}
+	for k, index := range sparse.Indexes {
+		values[index] = sparse.Values[k]
+	}
+	return NewFloatSeries(sparse.Name, values)
+}
+
+func (sparse *SparseSeries) GetLength() int {
+	return sparse.Length
+}
+
+// Density returns the fraction of rows stored explicitly
+func (sparse *SparseSeries) Density() float64 {
+	if sparse.Length == 0 {
+		return 0
+	}
+	return float64(len(sparse.Values)) / float64(sparse.Length)
+}
+
+func (sparse *SparseSeries) GetValue(index int) float64 {
+	position := sort.SearchInts(sparse.Indexes, index)
+	if position < len(sparse.Indexes) && sparse.Indexes[position] == index {
+		return sparse.Values[position]
+	}
+	return sparse.FillValue
+}
+
+// GetSum ignores NaN like the dense aggregations
+func (sparse *SparseSeries) GetSum() (float64, error) {
+	if sparse.Length == 0 {
+		return 0, fmt.Errorf("GetSum requires a non-empty array")
+	}
+	sum := arraySum(sparse.Values)
+	if sparse.FillValue != 0 && !math.IsNaN(sparse.FillValue) {
+		sum += sparse.FillValue * float64(sparse.Length-len(sparse.Values))
+	}
+	return sum, nil
+}
+
+func (sparse *SparseSeries) GetMean() (float64, error) {
+	sum, err := sparse.GetSum()
+	if err != nil {
+		return 0, fmt.Errorf("GetMean requires a non-empty array")
+	}
+	count := sparse.Length - int(arrayFloatCountNaNValue(sparse.Values))
+	if math.IsNaN(sparse.FillValue) {
+		count -= sparse.Length - len(sparse.Values)
+	}
+	if count == 0 {
+		return math.NaN(), nil
+	}
+	return sum / float64(count), nil
+}
+
+// Dot multiplies two sparse series of the same length, only the stored
+// positions are visited when both fill values are zero
+func (sparse *SparseSeries) Dot(other SparseSeries) (float64, error) {
+	if sparse.Length != other.Length {
+		return 0, fmt.Errorf("cannot multiply series of length %d and %d", sparse.Length, other.Length)
+	}
+	if sparse.FillValue != 0 || other.FillValue != 0 {
+		left := sparse.ToSeries()
+		right := other.ToSeries()
+		return arrayDot(left.Float, right.Float), nil
+	}
+	var sum float64
+	i, j := 0, 0
+	for i < len(sparse.Indexes) && j < len(other.Indexes) {
+		switch {
+		case sparse.Indexes[i] < other.Indexes[j]:
+			i++
+		case sparse.Indexes[i] > other.Indexes[j]:
+			j++
+		default:
+			sum += sparse.Values[i] * other.Values[j]
+			i++
+			j++
+		}
+	}
+	return sum, nil
+}
+
+// DotDense multiplies with a dense float series of the same length
+func (sparse *SparseSeries) DotDense(other Series) (float64, error) {
+	if other.DataType != "float" {
+		return 0, fmt.Errorf("series %q is not of type float", other.Name)
+	}
+	if sparse.Length != other.GetLength() {
+		return 0, fmt.Errorf("cannot multiply series of length %d and %d", sparse.Length, other.GetLength())
+	}
+	var sum float64
+	for k, index := range sparse.Indexes {
+		sum += sparse.Values[k] * other.Float[index]
+	}
+	if sparse.FillValue != 0 {
+		stored := 0
+		for i, value := range other.Float {
+			if stored < len(sparse.Indexes) && sparse.Indexes[stored] == i {
+				stored++
+				continue
+			}
+			sum += sparse.FillValue * value
+		}
+	}
+	return sum, nil
+}
+
+func arrayDot(left, right []float64) float64 {
+	var sum float64
+	for i := range left {
+		sum += left[i] * right[i]
+	}
+	return sum
+}
+
+// ToSparse converts the float columns of the DataFrame to sparse series
+func (df *DataFrame) ToSparse(fillValue float64, identifiers ...any) ([]SparseSeries, error) {
+	var result []SparseSeries
+	for _, identifier := range identifiers {
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert column %v to sparse: %w", identifier, err)
+		}
+		sparse, err := series.ToSparse(fillValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert column %v to sparse: %w", identifier, err)
+		}
+		result = append(result, sparse)
+	}
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package grizzly
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// statistics of a series are computed once and cached until the series is
+// modified, invalidateIndexes replaces the cache with an empty one. A cache
+// is only shared by series with their own copy of the same values, like
+// those returned by Copy. Series holding the same backing array, like
+// views and duplicated columns, are marked as shared and get their own
+// cache, so a change made through one of them never leaves the cache of
+// another stale.
+type statistics struct {
+	min, max, sum float64
+	nulls         int
+}
+
+// statsCache holds the statistics of a series once computed. It is created
+// by the constructors and by invalidateIndexes, so readers on several
+// goroutines only fill it atomically and never write the series itself.
+type statsCache struct {
+	value atomic.Pointer[statistics]
+}
+
+// cachedStatistics returns the statistics when they were already computed
+func (series *Series) cachedStatistics() *statistics {
+	if series.stats == nil {
+		return nil
+	}
+	return series.stats.value.Load()
+}
+
+// statistics returns the cached statistics, computing them on first use.
+// Series without a cache, like those built by hand, compute them every
+// time. min, max and sum are only set for float series with values.
+func (series *Series) statistics() *statistics {
+	if stats := series.cachedStatistics(); stats != nil {
+		return stats
+	}
+	stats := &statistics{}
+	if series.DataType == "float" {
+		for _, value := range series.Float {
+			if math.IsNaN(value) {
+				stats.nulls++
+			}
+		}
+		if len(series.Float) > 0 {
+			stats.min, stats.max, stats.sum = arrayMin(series.Float), arrayMax(series.Float), arraySum(series.Float)
+		}
+	} else {
+		for _, value := range series.strings() {
+			if value == "NaN" {
+				stats.nulls++
+			}
+		}
+	}
+	if series.stats != nil {
+		series.stats.value.Store(stats)
+	}
+	return stats
+}
+
+// NullCount returns the number of NaN values, cached until the series is
+// modified
+func (series *Series) NullCount() int {
+	return series.statistics().nulls
+}
+
+// decideComparison answers the comparison of every value of a float series
+// with a number from the cached min and max. ok is false when the values
+// have to be compared one by one.
+func (series *Series) decideComparison(op string, value float64) (result bool, ok bool) {
+	if series.DataType != "float" || len(series.Float) == 0 {
+		return false, false
+	}
+	stats := series.statistics()
+	// NaN values fail every comparison, so they only block answering true
+	complete := stats.nulls == 0
+	switch op {
+	case ">":
+		if stats.max <= value {
+			return false, true
+		}
+		return true, complete && stats.min > value
+	case ">=":
+		if stats.max < value {
+			return false, true
+		}
+		return true, complete && stats.min >= value
+	case "<":
+		if stats.min >= value {
+			return false, true
+		}
+		return true, complete && stats.max < value
+	case "<=":
+		if stats.min > value {
+			return false, true
+		}
+		return true, complete && stats.max <= value
+	}
+	return false, false
+}
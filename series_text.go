@@ -0,0 +1,296 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TokenizerOptions configures how text is split. Pattern is a regular
+// expression matching one token, words and numbers by default. MaxFeatures
+// keeps only the most frequent terms when building term matrices.
+type TokenizerOptions struct {
+	Pattern     string
+	Lowercase   bool
+	StopWords   []string
+	MinLength   int
+	MaxFeatures int
+}
+
+func (series *Series) Tokenize(options TokenizerOptions) ([][]string, error) {
+	if series.DataType != "string" {
+		return nil, fmt.Errorf("tokenize requires a string column, %q is %q", series.Name, series.DataType)
+	}
+	pattern := options.Pattern
+	if pattern == "" {
+		pattern = `[\p{L}\p{N}]+`
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token pattern: %w", err)
+	}
+	stopWords := make(map[string]struct{}, len(options.StopWords))
+	for _, word := range options.StopWords {
+		if options.Lowercase {
+			word = strings.ToLower(word)
+		}
+		stopWords[word] = struct{}{}
+	}
+
+	length := series.GetLength()
+	texts := series.strings()
+	tokens := make([][]string, length)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				text := texts[i]
+				if options.Lowercase {
+					text = strings.ToLower(text)
+				}
+				found := re.FindAllString(text, -1)
+				kept := found[:0]
+				for _, token := range found {
+					if len([]rune(token)) < options.MinLength {
+						continue
+					}
+					if _, stop := stopWords[token]; stop {
+						continue
+					}
+					kept = append(kept, token)
+				}
+				tokens[i] = kept
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return tokens, nil
+}
+
+// TermFrequency returns a DataFrame with one float column per term counting
+// its occurrences in every row
+func (series *Series) TermFrequency(options TokenizerOptions) (DataFrame, error) {
+	tokens, err := series.Tokenize(options)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	vocabulary, _ := buildVocabulary(tokens, options.MaxFeatures)
+	return termMatrix(tokens, vocabulary, nil, false), nil
+}
+
+// TFIDF weights the term frequencies by the smoothed inverse document
+// frequency ln((1+n)/(1+df))+1 and scales every row to unit length
+func (series *Series) TFIDF(options TokenizerOptions) (DataFrame, error) {
+	tokens, err := series.Tokenize(options)
+	if err != nil {
+		return DataFrame{}, err
+	}
+	vocabulary, documentFrequency := buildVocabulary(tokens, options.MaxFeatures)
+	idf := make([]float64, len(vocabulary))
+	n := float64(len(tokens))
+	for j, term := range vocabulary {
+		idf[j] = math.Log((1+n)/(1+float64(documentFrequency[term]))) + 1
+	}
+	return termMatrix(tokens, vocabulary, idf, true), nil
+}
+
+// buildVocabulary returns the sorted terms and the number of rows holding
+// each of them, keeping the maxFeatures most frequent when it is positive
+func buildVocabulary(tokens [][]string, maxFeatures int) ([]string, map[string]int) {
+	documentFrequency := make(map[string]int)
+	totalFrequency := make(map[string]int)
+	for _, row := range tokens {
+		seen := make(map[string]struct{}, len(row))
+		for _, token := range row {
+			totalFrequency[token]++
+			if _, ok := seen[token]; !ok {
+				seen[token] = struct{}{}
+				documentFrequency[token]++
+			}
+		}
+	}
+	vocabulary := make([]string, 0, len(documentFrequency))
+	for term := range documentFrequency {
+		vocabulary = append(vocabulary, term)
+	}
+	if maxFeatures > 0 && maxFeatures < len(vocabulary) {
+		sort.Slice(vocabulary, func(i, j int) bool {
+			if totalFrequency[vocabulary[i]] != totalFrequency[vocabulary[j]] {
+				return totalFrequency[vocabulary[i]] > totalFrequency[vocabulary[j]]
+			}
+			return vocabulary[i] < vocabulary[j]
+		})
+		vocabulary = vocabulary[:maxFeatures]
+	}
+	vocabulary = ParallelSortString(vocabulary)
+	return vocabulary, documentFrequency
+}
+
+func termMatrix(tokens [][]string, vocabulary []string, weights []float64, normalize bool) DataFrame {
+	position := make(map[string]int, len(vocabulary))
+	values := make([][]float64, len(vocabulary))
+	for j, term := range vocabulary {
+		position[term] = j
+		values[j] = make([]float64, len(tokens))
+	}
+
+	length := len(tokens)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		start := g * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				used := make([]int, 0, len(tokens[i]))
+				for _, token := range tokens[i] {
+					j, ok := position[token]
+					if !ok {
+						continue
+					}
+					if values[j][i] == 0 {
+						used = append(used, j)
+					}
+					values[j][i]++
+				}
+				if weights == nil {
+					continue
+				}
+				var norm float64
+				for _, j := range used {
+					values[j][i] *= weights[j]
+					norm += values[j][i] * values[j][i]
+				}
+				if normalize && norm > 0 {
+					norm = math.Sqrt(norm)
+					for _, j := range used {
+						values[j][i] /= norm
+					}
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	result := DataFrame{Columns: make([]Series, len(vocabulary))}
+	for j, term := range vocabulary {
+		result.Columns[j] = NewFloatSeries(term, values[j])
+	}
+	return result
+}
+
+// seriesMapFloat evaluates operation on every value of a string series in
+// parallel chunks, nulls give NaN
+func seriesMapFloat(series *Series, name string, operation func(string) float64) (Series, error) {
+	if series.DataType != "string" {
+		return Series{}, fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	values := series.strings()
+	length := len(values)
+	result := make([]float64, length)
+	numGoroutines := workerCount()
+	chunkSize := (length + numGoroutines - 1) / numGoroutines
+	var wg sync.WaitGroup
+	for i := 0; i < numGoroutines; i++ {
+		start := i * chunkSize
+		end := start + chunkSize
+		if start >= length {
+			break
+		}
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for j := start; j < end; j++ {
+				if values[j] == "NaN" {
+					result[j] = math.NaN()
+				} else {
+					result[j] = operation(values[j])
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return NewFloatSeries(name, result), nil
+}
+
+// WordCount returns the number of words separated by spaces of every value
+// as a float series "<name>_words"
+func (series *Series) WordCount() (Series, error) {
+	return seriesMapFloat(series, series.Name+"_words", func(value string) float64 {
+		return float64(len(strings.Fields(value)))
+	})
+}
+
+// CharCount returns the number of characters of every value as a float
+// series "<name>_chars"
+func (series *Series) CharCount() (Series, error) {
+	return seriesMapFloat(series, series.Name+"_chars", func(value string) float64 {
+		return float64(len([]rune(value)))
+	})
+}
+
+// DigitRatio returns the share of characters that are digits in every
+// value as a float series "<name>_digit_ratio", NaN for empty values
+func (series *Series) DigitRatio() (Series, error) {
+	return seriesMapFloat(series, series.Name+"_digit_ratio", func(value string) float64 {
+		var digits, total float64
+		for _, r := range value {
+			total++
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		if total == 0 {
+			return math.NaN()
+		}
+		return digits / total
+	})
+}
+
+// Entropy returns the Shannon entropy in bits of the characters of every
+// value as a float series "<name>_entropy". Random tokens score high and
+// repeated characters low.
+func (series *Series) Entropy() (Series, error) {
+	return seriesMapFloat(series, series.Name+"_entropy", func(value string) float64 {
+		counts := map[rune]float64{}
+		var total float64
+		for _, r := range value {
+			counts[r]++
+			total++
+		}
+		var entropy float64
+		for _, count := range counts {
+			p := count / total
+			entropy -= p * math.Log2(p)
+		}
+		return entropy
+	})
+}
@@ -0,0 +1,215 @@
+package grizzly
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	hangulBase   = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulCount  = 11172
+)
+
+// diacriticReplacements are letters with a stroke or ligatures that have no
+// decomposition but are commonly written without the mark
+var diacriticReplacements = map[rune]string{
+	'ø': "o", 'Ø': "O", 'ł': "l", 'Ł': "L", 'đ': "d", 'Đ': "D", 'ħ': "h", 'Ħ': "H",
+	'ß': "ss", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE", 'þ': "th", 'Þ': "Th",
+	'ı': "i", 'ŧ': "t", 'Ŧ': "T", 'ð': "d", 'Ð': "D",
+}
+
+func isASCII(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// unicodeDecompose returns the canonical (or compatibility) decomposition
+// of value with the combining marks in canonical order
+func unicodeDecompose(value string, compatibility bool) []rune {
+	runes := make([]rune, 0, len(value))
+	for _, r := range value {
+		if r >= hangulBase && r < hangulBase+hangulCount {
+			index := r - hangulBase
+			runes = append(runes, hangulLBase+index/hangulNCount, hangulVBase+(index%hangulNCount)/hangulTCount)
+			if trailing := index % hangulTCount; trailing != 0 {
+				runes = append(runes, hangulTBase+trailing)
+			}
+			continue
+		}
+		if compatibility {
+			if decomposition, ok := unicodeCompatibilityDecomposition[r]; ok {
+				runes = append(runes, []rune(decomposition)...)
+				continue
+			}
+		}
+		if decomposition, ok := unicodeCanonicalDecomposition[r]; ok {
+			runes = append(runes, []rune(decomposition)...)
+			continue
+		}
+		runes = append(runes, r)
+	}
+
+	// Canonical ordering of every run of combining marks
+	for start := 0; start < len(runes); start++ {
+		if unicodeCombiningClass[runes[start]] == 0 {
+			continue
+		}
+		end := start
+		for end < len(runes) && unicodeCombiningClass[runes[end]] != 0 {
+			end++
+		}
+		marks := runes[start:end]
+		sort.SliceStable(marks, func(i, j int) bool {
+			return unicodeCombiningClass[marks[i]] < unicodeCombiningClass[marks[j]]
+		})
+		start = end
+	}
+	return runes
+}
+
+// unicodeCompose applies the canonical composition algorithm
+func unicodeCompose(runes []rune) []rune {
+	if len(runes) == 0 {
+		return runes
+	}
+	result := make([]rune, 0, len(runes))
+	starter := -1
+	var lastClass uint8
+	for _, r := range runes {
+		class := unicodeCombiningClass[r]
+		if starter >= 0 {
+			first := result[starter]
+			blocked := len(result)-1 != starter && (lastClass == 0 || lastClass >= class)
+			if !blocked {
+				// Hangul syllables
+				if first >= hangulLBase && first < hangulLBase+19 && r >= hangulVBase && r < hangulVBase+hangulVCount {
+					result[starter] = hangulBase + ((first-hangulLBase)*hangulVCount+(r-hangulVBase))*hangulTCount
+					continue
+				}
+				if first >= hangulBase && first < hangulBase+hangulCount && (first-hangulBase)%hangulTCount == 0 &&
+					r > hangulTBase && r < hangulTBase+hangulTCount {
+					result[starter] = first + r - hangulTBase
+					continue
+				}
+				if composite, ok := unicodeComposition[[2]rune{first, r}]; ok {
+					result[starter] = composite
+					continue
+				}
+			}
+		}
+		if class == 0 {
+			starter = len(result)
+		}
+		lastClass = class
+		result = append(result, r)
+	}
+	return result
+}
+
+// normalizeUnicode converts value to the normalization form "NFC", "NFD",
+// "NFKC" or "NFKD"
+func normalizeUnicode(value string, form string) string {
+	if isASCII(value) {
+		return value
+	}
+	runes := unicodeDecompose(value, form == "NFKC" || form == "NFKD")
+	if form == "NFC" || form == "NFKC" {
+		runes = unicodeCompose(runes)
+	}
+	return string(runes)
+}
+
+// removeDiacritics drops the combining marks and replaces the letters with
+// strokes, so "Łódź" becomes "Lodz"
+func removeDiacritics(value string) string {
+	if isASCII(value) {
+		return value
+	}
+	var builder strings.Builder
+	for _, r := range unicodeDecompose(value, true) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if replacement, ok := diacriticReplacements[r]; ok {
+			builder.WriteString(replacement)
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// NormalizeUnicode converts the values of a string series to the
+// normalization form "NFC", "NFD", "NFKC" or "NFKD"
+func (series *Series) NormalizeUnicode(form string) error {
+	if form != "NFC" && form != "NFD" && form != "NFKC" && form != "NFKD" {
+		return fmt.Errorf("unsupported normalization form %q", form)
+	}
+	if series.DataType != "string" {
+		return fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	series.invalidateIndexes()
+	arrayApplyString(series.String, func(value string) string {
+		return normalizeUnicode(value, form)
+	})
+	return nil
+}
+
+func (series *Series) NormalizeNFC() error {
+	return series.NormalizeUnicode("NFC")
+}
+
+func (series *Series) NormalizeNFKD() error {
+	return series.NormalizeUnicode("NFKD")
+}
+
+// RemoveDiacritics strips accents and other marks from a string series
+func (series *Series) RemoveDiacritics() error {
+	if series.DataType != "string" {
+		return fmt.Errorf("series %q is not of type string", series.Name)
+	}
+	series.invalidateIndexes()
+	arrayApplyString(series.String, removeDiacritics)
+	return nil
+}
+
+func (df *DataFrame) NormalizeUnicode(form string, identifiers ...any) (err error) {
+	defer df.track("NormalizeUnicode", form, identifiers)(&err)
+	for _, identifier := range identifiers {
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve column to normalize %v: %w", identifier, err)
+		}
+		if err = series.NormalizeUnicode(form); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (df *DataFrame) RemoveDiacritics(identifiers ...any) (err error) {
+	defer df.track("RemoveDiacritics", identifiers)(&err)
+	for _, identifier := range identifiers {
+		series, err := df.GetColumnDynamic(identifier)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve column to remove diacritics %v: %w", identifier, err)
+		}
+		if err = series.RemoveDiacritics(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
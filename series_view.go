@@ -0,0 +1,72 @@
+package grizzly
+
+import "fmt"
+
+// Slices return views sharing the values of the source. Both the source and
+// the view are marked as shared and copy their values the first time they
+// are modified, so writing to one of them never changes the other.
+
+// detach gives a shared series its own copy of the values
+func (series *Series) detach() {
+	if !series.shared {
+		return
+	}
+	series.shared = false
+	if series.Float != nil {
+		series.Float = append(make([]float64, 0, len(series.Float)), series.Float...)
+	}
+	if series.String != nil {
+		series.String = append(make([]string, 0, len(series.String)), series.String...)
+	}
+}
+
+// sharesValues reports whether series and other hold the same backing array
+func (series *Series) sharesValues(other *Series) bool {
+	if len(series.Float) > 0 && len(other.Float) > 0 {
+		return &series.Float[0] == &other.Float[0]
+	}
+	if len(series.String) > 0 && len(other.String) > 0 {
+		return &series.String[0] == &other.String[0]
+	}
+	return false
+}
+
+// Slice returns a view of the rows from start to end, excluded, without
+// copying the values. Compressed series are decompressed into the view.
+func (series *Series) Slice(start, end int) (Series, error) {
+	length := series.GetLength()
+	if start < 0 || end > length || start > end {
+		return Series{}, fmt.Errorf("slice [%d:%d] out of range for length %d", start, end, length)
+	}
+	view := Series{Name: series.Name, DataType: series.DataType, Metadata: copyMetadata(series.Metadata), stats: &statsCache{}}
+	if series.DataType == "float" {
+		// The capacity is limited so appending to the view never writes over
+		// the rows of the source
+		view.Float = series.Float[start:end:end]
+		view.String = []string{}
+	} else if series.compressed != nil {
+		view.String = append([]string{}, series.strings()[start:end]...)
+		view.Float = []float64{}
+		return view, nil
+	} else {
+		view.String = series.String[start:end:end]
+		view.Float = []float64{}
+	}
+	series.shared = true
+	view.shared = true
+	return view, nil
+}
+
+// Slice returns a DataFrame with the rows from start to end, excluded, whose
+// columns are views of the columns of df, see Series.Slice
+func (df *DataFrame) Slice(start, end int) (DataFrame, error) {
+	result := DataFrame{Columns: make([]Series, len(df.Columns)), config: df.config}
+	for i := range df.Columns {
+		view, err := df.Columns[i].Slice(start, end)
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to slice column %q: %w", df.Columns[i].Name, err)
+		}
+		result.Columns[i] = view
+	}
+	return result, nil
+}
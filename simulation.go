@@ -0,0 +1,110 @@
+package grizzly
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// Distribution draws random values for Simulate
+type Distribution interface {
+	Sample(rng *rand.Rand) float64
+}
+
+// DistributionFunc adapts a function to the Distribution interface
+type DistributionFunc func(rng *rand.Rand) float64
+
+func (function DistributionFunc) Sample(rng *rand.Rand) float64 {
+	return function(rng)
+}
+
+// Normal is the normal distribution with the given mean and standard deviation
+func Normal(mean, std float64) Distribution {
+	return DistributionFunc(func(rng *rand.Rand) float64 {
+		return mean + std*rng.NormFloat64()
+	})
+}
+
+// LogNormal is the distribution of exp(X) where X is normal with mean mu and
+// standard deviation sigma
+func LogNormal(mu, sigma float64) Distribution {
+	return DistributionFunc(func(rng *rand.Rand) float64 {
+		return math.Exp(mu + sigma*rng.NormFloat64())
+	})
+}
+
+// Uniform draws values between low, included, and high, excluded
+func Uniform(low, high float64) Distribution {
+	return DistributionFunc(func(rng *rand.Rand) float64 {
+		return low + (high-low)*rng.Float64()
+	})
+}
+
+// Exponential is the exponential distribution with the given rate, its mean
+// is 1 / rate
+func Exponential(rate float64) Distribution {
+	return DistributionFunc(func(rng *rand.Rand) float64 {
+		return rng.ExpFloat64() / rate
+	})
+}
+
+// Triangular draws values between low and high that are most likely around
+// mode, a common choice for expert estimates
+func Triangular(low, mode, high float64) Distribution {
+	return DistributionFunc(func(rng *rand.Rand) float64 {
+		u := rng.Float64()
+		split := (mode - low) / (high - low)
+		if u < split {
+			return low + math.Sqrt(u*(high-low)*(mode-low))
+		}
+		return high - math.Sqrt((1-u)*(high-low)*(high-mode))
+	})
+}
+
+// Row holds the inputs of one scenario of Simulate by column name
+type Row map[string]float64
+
+// Simulate generates n scenarios in parallel. Every scenario draws a value
+// from each generator and passes them to model. The result has one column
+// per generator, sorted by name, and the column "output" with the value of
+// model. The same seed always gives the same scenarios and a seed of 0 uses
+// the current time.
+func Simulate(n int, generators map[string]Distribution, model func(Row) float64, seed int) (DataFrame, error) {
+	if n <= 0 {
+		return DataFrame{}, fmt.Errorf("number of scenarios must be positive, got %d", n)
+	}
+	if len(generators) == 0 {
+		return DataFrame{}, fmt.Errorf("simulation requires at least one generator")
+	}
+	names := make([]string, 0, len(generators))
+	for name := range generators {
+		if name == "output" {
+			return DataFrame{}, fmt.Errorf("generator name %q is reserved for the model output", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inputs := make([][]float64, len(names))
+	for i := range inputs {
+		inputs[i] = make([]float64, n)
+	}
+	output := make([]float64, n)
+	parallelDraws(n, seed, func(i int, rng *rand.Rand) {
+		row := make(Row, len(names))
+		for j, name := range names {
+			value := generators[name].Sample(rng)
+			inputs[j][i] = value
+			row[name] = value
+		}
+		output[i] = model(row)
+	})
+
+	result := DataFrame{Columns: make([]Series, 0, len(names)+1)}
+	for j, name := range names {
+		result.Columns = append(result.Columns, NewFloatSeries(name, inputs[j]))
+	}
+	result.Columns = append(result.Columns, NewFloatSeries("output", output))
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Puchungualotsqui/grizzly"
+)
+
+// residualPairs returns the actual and predicted values of the rows where
+// neither is NaN
+func residualPairs(actual, predicted grizzly.Series) ([]float64, []float64, error) {
+	if actual.DataType != "float" || predicted.DataType != "float" {
+		return nil, nil, fmt.Errorf("regression metrics require float series")
+	}
+	if actual.GetLength() != predicted.GetLength() {
+		return nil, nil, fmt.Errorf("actual length %d does not match predicted length %d", actual.GetLength(), predicted.GetLength())
+	}
+	var actualValues, predictedValues []float64
+	for i := range actual.Float {
+		if math.IsNaN(actual.Float[i]) || math.IsNaN(predicted.Float[i]) {
+			continue
+		}
+		actualValues = append(actualValues, actual.Float[i])
+		predictedValues = append(predictedValues, predicted.Float[i])
+	}
+	if len(actualValues) == 0 {
+		return nil, nil, fmt.Errorf("no rows where both actual and predicted values are not NaN")
+	}
+	return actualValues, predictedValues, nil
+}
+
+// MAE is the mean absolute error of predicted against actual. Rows where
+// either value is NaN are ignored.
+func MAE(actual, predicted grizzly.Series) (float64, error) {
+	actualValues, predictedValues, err := residualPairs(actual, predicted)
+	if err != nil {
+		return math.NaN(), err
+	}
+	sum := 0.0
+	for i := range actualValues {
+		sum += math.Abs(actualValues[i] - predictedValues[i])
+	}
+	return sum / float64(len(actualValues)), nil
+}
+
+// MSE is the mean squared error of predicted against actual. Rows where
+// either value is NaN are ignored.
+func MSE(actual, predicted grizzly.Series) (float64, error) {
+	actualValues, predictedValues, err := residualPairs(actual, predicted)
+	if err != nil {
+		return math.NaN(), err
+	}
+	sum := 0.0
+	for i := range actualValues {
+		residual := actualValues[i] - predictedValues[i]
+		sum += residual * residual
+	}
+	return sum / float64(len(actualValues)), nil
+}
+
+// RMSE is the square root of MSE
+func RMSE(actual, predicted grizzly.Series) (float64, error) {
+	mse, err := MSE(actual, predicted)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return math.Sqrt(mse), nil
+}
+
+// MAPE is the mean absolute percentage error of predicted against actual,
+// as a fraction so 0.05 is 5%. Rows where either value is NaN or the actual
+// value is 0 are ignored.
+func MAPE(actual, predicted grizzly.Series) (float64, error) {
+	actualValues, predictedValues, err := residualPairs(actual, predicted)
+	if err != nil {
+		return math.NaN(), err
+	}
+	sum, n := 0.0, 0
+	for i := range actualValues {
+		if actualValues[i] == 0 {
+			continue
+		}
+		sum += math.Abs((actualValues[i] - predictedValues[i]) / actualValues[i])
+		n++
+	}
+	if n == 0 {
+		return math.NaN(), fmt.Errorf("percentage error is undefined when every actual value is 0")
+	}
+	return sum / float64(n), nil
+}
+
+// R2 is the coefficient of determination of predicted against actual, 1 for
+// a perfect fit and 0 for a model that always predicts the mean. Rows where
+// either value is NaN are ignored.
+func R2(actual, predicted grizzly.Series) (float64, error) {
+	actualValues, predictedValues, err := residualPairs(actual, predicted)
+	if err != nil {
+		return math.NaN(), err
+	}
+	mean := 0.0
+	for _, value := range actualValues {
+		mean += value
+	}
+	mean /= float64(len(actualValues))
+	var residualSum, totalSum float64
+	for i, value := range actualValues {
+		residualSum += (value - predictedValues[i]) * (value - predictedValues[i])
+		totalSum += (value - mean) * (value - mean)
+	}
+	if totalSum == 0 {
+		return math.NaN(), fmt.Errorf("R2 is undefined when the actual values have no variance")
+	}
+	return 1 - residualSum/totalSum, nil
+}
@@ -0,0 +1,193 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Puchungualotsqui/grizzly"
+)
+
+// TestResult holds the outcome of a statistical test. DenominatorDegrees is
+// only set by tests over an F distribution.
+type TestResult struct {
+	Statistic          float64
+	PValue             float64
+	DegreesOfFreedom   float64
+	DenominatorDegrees float64
+}
+
+// ChiSquareTest runs Pearson's test of independence over a contingency table
+// such as the output of grizzly.Crosstab without margins. Only float columns
+// are read, so the label column is ignored.
+func ChiSquareTest(table grizzly.DataFrame) (TestResult, error) {
+	var observed [][]float64
+	for _, column := range table.Columns {
+		if column.DataType != "float" {
+			continue
+		}
+		observed = append(observed, column.Float)
+	}
+	if len(observed) < 2 || len(observed[0]) < 2 {
+		return TestResult{}, fmt.Errorf("chi-square test requires a table of at least 2x2")
+	}
+	numCols := len(observed)
+	numRows := len(observed[0])
+
+	rowTotals := make([]float64, numRows)
+	colTotals := make([]float64, numCols)
+	var total float64
+	for c := 0; c < numCols; c++ {
+		for r := 0; r < numRows; r++ {
+			value := observed[c][r]
+			if math.IsNaN(value) || value < 0 {
+				return TestResult{}, fmt.Errorf("chi-square test requires non negative counts")
+			}
+			rowTotals[r] += value
+			colTotals[c] += value
+			total += value
+		}
+	}
+	if total == 0 {
+		return TestResult{}, fmt.Errorf("chi-square test requires a non-empty table")
+	}
+
+	var statistic float64
+	for c := 0; c < numCols; c++ {
+		for r := 0; r < numRows; r++ {
+			expected := rowTotals[r] * colTotals[c] / total
+			if expected == 0 {
+				return TestResult{}, fmt.Errorf("chi-square test found a row or column without counts")
+			}
+			diff := observed[c][r] - expected
+			statistic += diff * diff / expected
+		}
+	}
+	dof := float64((numRows - 1) * (numCols - 1))
+	return TestResult{
+		Statistic:        statistic,
+		PValue:           ChiSquareSurvival(statistic, dof),
+		DegreesOfFreedom: dof,
+	}, nil
+}
+
+// TTest compares the means of two float series. The paired test works over
+// the differences of each row, otherwise Welch's test is used so the
+// variances may differ. NaN values are ignored.
+func TTest(seriesA, seriesB grizzly.Series, paired bool) (TestResult, error) {
+	if seriesA.DataType != "float" || seriesB.DataType != "float" {
+		return TestResult{}, fmt.Errorf("t-test requires float series")
+	}
+	if paired {
+		if seriesA.GetLength() != seriesB.GetLength() {
+			return TestResult{}, fmt.Errorf("paired t-test requires series of the same length")
+		}
+		var differences []float64
+		for i := range seriesA.Float {
+			if math.IsNaN(seriesA.Float[i]) || math.IsNaN(seriesB.Float[i]) {
+				continue
+			}
+			differences = append(differences, seriesA.Float[i]-seriesB.Float[i])
+		}
+		mean, variance, n, err := sampleMoments(grizzly.NewFloatSeries("difference", differences))
+		if err != nil {
+			return TestResult{}, err
+		}
+		if variance == 0 {
+			return TestResult{}, fmt.Errorf("t-test is undefined when the differences have no variance")
+		}
+		statistic := mean / math.Sqrt(variance/n)
+		dof := n - 1
+		return TestResult{
+			Statistic:        statistic,
+			PValue:           StudentTTwoTailed(statistic, dof),
+			DegreesOfFreedom: dof,
+		}, nil
+	}
+
+	meanA, varianceA, nA, err := sampleMoments(seriesA)
+	if err != nil {
+		return TestResult{}, err
+	}
+	meanB, varianceB, nB, err := sampleMoments(seriesB)
+	if err != nil {
+		return TestResult{}, err
+	}
+	errorA := varianceA / nA
+	errorB := varianceB / nB
+	if errorA+errorB == 0 {
+		return TestResult{}, fmt.Errorf("t-test is undefined when both series have no variance")
+	}
+	statistic := (meanA - meanB) / math.Sqrt(errorA+errorB)
+	dof := (errorA + errorB) * (errorA + errorB) /
+		(errorA*errorA/(nA-1) + errorB*errorB/(nB-1))
+	return TestResult{
+		Statistic:        statistic,
+		PValue:           StudentTTwoTailed(statistic, dof),
+		DegreesOfFreedom: dof,
+	}, nil
+}
+
+// OneWayANOVA tests whether the means of several float series are equal.
+// DegreesOfFreedom holds the between groups degrees and DenominatorDegrees
+// the within groups degrees.
+func OneWayANOVA(groups []grizzly.Series) (TestResult, error) {
+	if len(groups) < 2 {
+		return TestResult{}, fmt.Errorf("ANOVA requires at least 2 groups")
+	}
+	means := make([]float64, len(groups))
+	sizes := make([]float64, len(groups))
+	var withinSquares, grandSum, total float64
+	for g, group := range groups {
+		mean, variance, n, err := sampleMoments(group)
+		if err != nil {
+			return TestResult{}, fmt.Errorf("group %d: %w", g, err)
+		}
+		means[g] = mean
+		sizes[g] = n
+		withinSquares += variance * (n - 1)
+		grandSum += mean * n
+		total += n
+	}
+	grandMean := grandSum / total
+	var betweenSquares float64
+	for g := range groups {
+		diff := means[g] - grandMean
+		betweenSquares += sizes[g] * diff * diff
+	}
+	dofBetween := float64(len(groups) - 1)
+	dofWithin := total - float64(len(groups))
+	if dofWithin <= 0 || withinSquares == 0 {
+		return TestResult{}, fmt.Errorf("ANOVA is undefined without variance within the groups")
+	}
+	statistic := (betweenSquares / dofBetween) / (withinSquares / dofWithin)
+	return TestResult{
+		Statistic:          statistic,
+		PValue:             FSurvival(statistic, dofBetween, dofWithin),
+		DegreesOfFreedom:   dofBetween,
+		DenominatorDegrees: dofWithin,
+	}, nil
+}
+
+// sampleMoments returns the mean, the unbiased variance and the number of
+// values of a float series ignoring NaN
+func sampleMoments(series grizzly.Series) (float64, float64, float64, error) {
+	if series.DataType != "float" {
+		return 0, 0, 0, fmt.Errorf("series %q is not of type float", series.Name)
+	}
+	clean := grizzly.NewFloatSeries(series.Name, append([]float64{}, series.Float...))
+	clean.DropNaN()
+	n := float64(clean.GetLength())
+	if n < 2 {
+		return 0, 0, 0, fmt.Errorf("series %q requires at least 2 values", series.Name)
+	}
+	mean, err := clean.GetMean()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	variance, err := clean.GetVariance()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	// GetVariance is the population variance
+	return mean, variance * n / (n - 1), n, nil
+}
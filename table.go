@@ -0,0 +1,250 @@
+package grizzly
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// tableManifestFile lists the schema and the chunks of a Table
+const tableManifestFile = "_manifest.json"
+
+type tableColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type tableChunk struct {
+	File string `json:"file"`
+	Rows int    `json:"rows"`
+}
+
+type tableManifest struct {
+	Columns   []tableColumn `json:"columns"`
+	Chunks    []tableChunk  `json:"chunks"`
+	Encrypted bool          `json:"encrypted,omitempty"`
+}
+
+// Table is an append only table stored in a directory, every AppendFrame
+// writes an Avro chunk and the manifest lists the committed chunks. Chunks
+// not listed in the manifest, like the ones of an interrupted append, are
+// ignored. A Table is safe for concurrent use inside one process.
+type Table struct {
+	directory string
+	key       []byte
+	mutex     sync.Mutex
+}
+
+// OpenTable opens the table stored in directory, creating the directory
+// when it does not exist
+func OpenTable(directory string) (*Table, error) {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create table directory: %w", err)
+	}
+	return &Table{directory: directory}, nil
+}
+
+// OpenEncryptedTable opens a table whose chunks are encrypted with AES-GCM
+// and key, like WriteEncrypted. The manifest with the column names and the
+// row counts is not encrypted.
+func OpenEncryptedTable(directory string, key []byte) (*Table, error) {
+	if _, err := newGCM(key); err != nil {
+		return nil, err
+	}
+	table, err := OpenTable(directory)
+	if err != nil {
+		return nil, err
+	}
+	table.key = append([]byte{}, key...)
+	return table, nil
+}
+
+func (table *Table) manifest() (tableManifest, error) {
+	manifest := tableManifest{}
+	data, err := os.ReadFile(filepath.Join(table.directory, tableManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, fmt.Errorf("failed to read table manifest: %w", err)
+	}
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse table manifest: %w", err)
+	}
+	if manifest.Encrypted != (table.key != nil) {
+		if manifest.Encrypted {
+			return manifest, fmt.Errorf("table is encrypted, open it with OpenEncryptedTable")
+		}
+		return manifest, fmt.Errorf("table is not encrypted, open it with OpenTable")
+	}
+	return manifest, nil
+}
+
+// AppendFrame adds the rows of df as a new chunk. The first frame sets the
+// schema, the next ones must have the same column names and types in any
+// order.
+func (table *Table) AppendFrame(df DataFrame) error {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+	manifest, err := table.manifest()
+	if err != nil {
+		return err
+	}
+
+	if len(manifest.Columns) == 0 {
+		for _, series := range df.Columns {
+			manifest.Columns = append(manifest.Columns, tableColumn{Name: series.Name, Type: series.DataType})
+		}
+	}
+	if len(df.Columns) != len(manifest.Columns) {
+		return fmt.Errorf("frame has %d columns, table has %d", len(df.Columns), len(manifest.Columns))
+	}
+	// Chunks keep the column order of the manifest
+	chunk := DataFrame{Columns: make([]Series, len(manifest.Columns))}
+	for i, column := range manifest.Columns {
+		series, err := df.GetColumnByName(column.Name)
+		if err != nil {
+			return fmt.Errorf("frame does not match the table schema: %w", err)
+		}
+		if series.DataType != column.Type {
+			return fmt.Errorf("column %q is of type %q, table expects %q", column.Name, series.DataType, column.Type)
+		}
+		chunk.Columns[i] = *series
+	}
+
+	name := fmt.Sprintf("chunk-%06d.avro", len(manifest.Chunks))
+	if table.key != nil {
+		name += ".enc"
+		manifest.Encrypted = true
+		err = chunk.WriteEncrypted(filepath.Join(table.directory, name), "avro", table.key)
+	} else {
+		err = chunk.WriteAvro(filepath.Join(table.directory, name))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write table chunk: %w", err)
+	}
+	manifest.Chunks = append(manifest.Chunks, tableChunk{File: name, Rows: df.GetLength()})
+
+	// The new manifest replaces the old one in a single rename
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize table manifest: %w", err)
+	}
+	temporary := filepath.Join(table.directory, tableManifestFile+".tmp")
+	if err = os.WriteFile(temporary, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write table manifest: %w", err)
+	}
+	if err = os.Rename(temporary, filepath.Join(table.directory, tableManifestFile)); err != nil {
+		return fmt.Errorf("failed to write table manifest: %w", err)
+	}
+	return nil
+}
+
+// Rows returns the number of rows committed to the table
+func (table *Table) Rows() (int, error) {
+	manifest, err := table.manifest()
+	if err != nil {
+		return 0, err
+	}
+	rows := 0
+	for _, chunk := range manifest.Chunks {
+		rows += chunk.Rows
+	}
+	return rows, nil
+}
+
+// ReadAll returns every row of the table
+func (table *Table) ReadAll() (DataFrame, error) {
+	return table.ReadWhere(nil)
+}
+
+// ReadWhere returns the rows of the table where every filter is true with
+// only the given columns, all of them when columns is empty. Chunks are read
+// in parallel and the columns that are not needed are dropped as every chunk
+// is read.
+func (table *Table) ReadWhere(columns []string, filters ...Expr) (DataFrame, error) {
+	manifest, err := table.manifest()
+	if err != nil {
+		return DataFrame{}, err
+	}
+	names := make([]string, len(manifest.Columns))
+	types := make(map[string]string, len(manifest.Columns))
+	for i, column := range manifest.Columns {
+		names[i] = column.Name
+		types[column.Name] = column.Type
+	}
+	if len(columns) == 0 {
+		columns = names
+	}
+	needed := append([]string{}, columns...)
+	for _, name := range columns {
+		if !arrayContainsString(names, name) {
+			return DataFrame{}, fmt.Errorf("column %q is not in the table", name)
+		}
+	}
+	for _, filter := range filters {
+		for _, name := range filter.columns() {
+			if !arrayContainsString(needed, name) {
+				needed = append(needed, name)
+			}
+		}
+	}
+
+	paths := make([]string, len(manifest.Chunks))
+	for i, chunk := range manifest.Chunks {
+		paths[i] = filepath.Join(table.directory, chunk.File)
+	}
+	frames, err := readFiles(paths, func(path string) (DataFrame, error) {
+		var chunk DataFrame
+		var err error
+		if table.key != nil {
+			chunk, err = ReadEncrypted(path, "avro", table.key)
+		} else {
+			chunk, err = ReadAvro(path)
+		}
+		if err != nil {
+			return DataFrame{}, fmt.Errorf("failed to read table chunk %q: %w", path, err)
+		}
+		if len(chunk.Columns) != len(names) {
+			return DataFrame{}, fmt.Errorf("table chunk %q does not match the manifest", path)
+		}
+		// Avro adapts the column names, the manifest keeps the original ones
+		kept := DataFrame{}
+		for i := range chunk.Columns {
+			chunk.Columns[i].Name = names[i]
+			if arrayContainsString(needed, names[i]) {
+				kept.Columns = append(kept.Columns, chunk.Columns[i])
+			}
+		}
+		for _, filter := range filters {
+			if err = kept.Filter(filter); err != nil {
+				return DataFrame{}, err
+			}
+		}
+		return kept, nil
+	})
+	if err != nil {
+		return DataFrame{}, err
+	}
+
+	result := DataFrame{Columns: make([]Series, len(columns))}
+	for i, name := range columns {
+		series := Series{Name: name, DataType: types[name]}
+		for _, frame := range frames {
+			source, err := frame.GetColumnByName(name)
+			if err != nil {
+				return DataFrame{}, err
+			}
+			if source.DataType != series.DataType {
+				return DataFrame{}, fmt.Errorf("column %q was read as %q, table expects %q", name, source.DataType, series.DataType)
+			}
+			series.Float = append(series.Float, source.Float...)
+			series.String = append(series.String, source.strings()...)
+		}
+		result.Columns[i] = series
+	}
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package grizzly
+
+import (
+	"fmt"
+)
+
+// TemplateRows returns one map per row from column name to the value of the
+// row formatted as set in the Config, ready to range over in text/template
+// or html/template
+func (df *DataFrame) TemplateRows() []map[string]any {
+	config := df.getConfig()
+	rows := make([]map[string]any, df.GetLength())
+	for i := range rows {
+		row := make(map[string]any, len(df.Columns))
+		for j := range df.Columns {
+			row[df.Columns[j].Name] = formatValue(&df.Columns[j], i, config)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// TemplateRecords returns the rows as formatted values in column order, maps
+// are ranged over sorted by key in templates so this keeps the column order
+func (df *DataFrame) TemplateRecords() [][]string {
+	config := df.getConfig()
+	records := make([][]string, df.GetLength())
+	for i := range records {
+		record := make([]string, len(df.Columns))
+		for j := range df.Columns {
+			record[j] = formatValue(&df.Columns[j], i, config)
+		}
+		records[i] = record
+	}
+	return records
+}
+
+// TemplateFuncs returns functions to use a DataFrame from text/template and
+// html/template, pass it to Funcs of either:
+//
+//	columns df          the column names
+//	rows df             the rows as maps, like TemplateRows
+//	records df          the rows as values in column order, like TemplateRecords
+//	values df "name"    the formatted values of a column
+//	cell df row "name"  the formatted value of one cell
+//
+// Values are formatted as set in the Config of the DataFrame.
+func TemplateFuncs() map[string]any {
+	return map[string]any{
+		"columns": func(df DataFrame) []string {
+			return df.GetColumnNames()
+		},
+		"rows": func(df DataFrame) []map[string]any {
+			return df.TemplateRows()
+		},
+		"records": func(df DataFrame) [][]string {
+			return df.TemplateRecords()
+		},
+		"values": func(df DataFrame, column string) ([]string, error) {
+			series, err := df.GetColumnByName(column)
+			if err != nil {
+				return nil, err
+			}
+			config := df.getConfig()
+			values := make([]string, series.GetLength())
+			for i := range values {
+				values[i] = formatValue(series, i, config)
+			}
+			return values, nil
+		},
+		"cell": func(df DataFrame, row int, column string) (string, error) {
+			series, err := df.GetColumnByName(column)
+			if err != nil {
+				return "", err
+			}
+			if row < 0 || row >= series.GetLength() {
+				return "", fmt.Errorf("row %d out of range for length %d", row, series.GetLength())
+			}
+			return formatValue(series, row, df.getConfig()), nil
+		},
+	}
+}
@@ -0,0 +1,122 @@
+//go:build js && wasm
+
+// Command wasm exposes grizzly to JavaScript when built with
+//
+//	GOOS=js GOARCH=wasm go build -o grizzly.wasm ./wasm
+//
+// and loaded with the wasm_exec.js of the Go distribution. It defines the
+// global object grizzly holding DataFrames by name:
+//
+//	grizzly.loadCSV(name, text)          reads CSV text, returns the number of rows
+//	grizzly.query(name, filter, columns) rows where filter is true as JSON, with
+//	                                     only columns when it is an array
+//	grizzly.toJSON(name)                 every row as JSON
+//	grizzly.columns(name)                the columns as JSON [{"name", "type"}]
+//	grizzly.drop(name)                   forgets the DataFrame
+//
+// Failures are returned as Error objects instead of being thrown.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"github.com/Puchungualotsqui/grizzly"
+)
+
+var frames = map[string]grizzly.DataFrame{}
+
+func frame(name string) (grizzly.DataFrame, error) {
+	df, ok := frames[name]
+	if !ok {
+		return grizzly.DataFrame{}, fmt.Errorf("no DataFrame named %q, load it first", name)
+	}
+	return df, nil
+}
+
+func toJSON(df grizzly.DataFrame) (string, error) {
+	var buffer bytes.Buffer
+	if err := df.WriteJSON(&buffer); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// function adapts fn to JavaScript, checking the number of arguments and
+// returning errors as Error objects
+func function(arguments int, fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) < arguments {
+			return js.Global().Get("Error").New(fmt.Sprintf("expected %d arguments, got %d", arguments, len(args)))
+		}
+		result, err := fn(args)
+		if err != nil {
+			return js.Global().Get("Error").New(err.Error())
+		}
+		return result
+	})
+}
+
+func main() {
+	api := map[string]any{
+		"loadCSV": function(2, func(args []js.Value) (any, error) {
+			df, err := grizzly.ReadCSV(strings.NewReader(args[1].String()))
+			if err != nil {
+				return nil, err
+			}
+			frames[args[0].String()] = df
+			return df.GetLength(), nil
+		}),
+		"query": function(2, func(args []js.Value) (any, error) {
+			df, err := frame(args[0].String())
+			if err != nil {
+				return nil, err
+			}
+			df = df.Copy()
+			if filter := args[1].String(); filter != "" {
+				if err = df.Filter(filter); err != nil {
+					return nil, err
+				}
+			}
+			if len(args) > 2 && args[2].Type() == js.TypeObject {
+				var selected grizzly.DataFrame
+				for i := 0; i < args[2].Length(); i++ {
+					series, err := df.GetColumnByName(args[2].Index(i).String())
+					if err != nil {
+						return nil, err
+					}
+					selected.Columns = append(selected.Columns, *series)
+				}
+				df = selected
+			}
+			return toJSON(df)
+		}),
+		"toJSON": function(1, func(args []js.Value) (any, error) {
+			df, err := frame(args[0].String())
+			if err != nil {
+				return nil, err
+			}
+			return toJSON(df)
+		}),
+		"columns": function(1, func(args []js.Value) (any, error) {
+			df, err := frame(args[0].String())
+			if err != nil {
+				return nil, err
+			}
+			columns := make([]string, len(df.Columns))
+			for i, series := range df.Columns {
+				columns[i] = fmt.Sprintf(`{"name":%q,"type":%q}`, series.Name, series.DataType)
+			}
+			return "[" + strings.Join(columns, ",") + "]", nil
+		}),
+		"drop": function(1, func(args []js.Value) (any, error) {
+			delete(frames, args[0].String())
+			return nil, nil
+		}),
+	}
+	js.Global().Set("grizzly", js.ValueOf(api))
+	// Keep the functions alive for the page
+	select {}
+}